@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -34,6 +36,7 @@ import (
 	"github.com/pauljones0/rfd-discord-bot/internal/scrapebackend"
 	"github.com/pauljones0/rfd-discord-bot/internal/scraper"
 	"github.com/pauljones0/rfd-discord-bot/internal/storage"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 	"github.com/pauljones0/rfd-discord-bot/internal/validator"
 )
 
@@ -50,9 +53,10 @@ type Server struct {
 	onEveryCorner           *oneverycorner.Processor
 	onEveryCornerController *oneverycorner.Controller
 	aiClient                *ai.Client
-	store                   processor.DealStore
+	store                   processor.DealStore // deal store selected by STORAGE; admin deal handlers use it
+	selectors               selectorReloader
 	systemNotifier          scheduledSystemNotifier
-	db                      *storage.Client
+	db                      *storage.Client // Postgres: subscriptions, runs, migrations and health
 	wg                      sync.WaitGroup
 	sem                     chan struct{} // Semaphore to limit concurrent RFD processing requests
 	ebaySem                 chan struct{} // Semaphore to limit concurrent eBay processing requests
@@ -67,6 +71,8 @@ type Server struct {
 	coreIssueLast           map[string]time.Time
 	schedulerIssueMu        sync.Mutex
 	schedulerFailures       map[string]scheduledProcessorFailure
+	errorTolerance          int     // per-deal RFD failures tolerated before /process-deals returns 500
+	errorToleranceFraction  float64 // same, as a fraction of the run's deals
 }
 
 type scheduledSystemNotifier interface {
@@ -81,6 +87,7 @@ func main() {
 		slog.Error("Critical error loading configuration", "error", err)
 		os.Exit(1)
 	}
+	slog.Info("Effective configuration", "config", cfg.Redacted())
 
 	ctx := context.Background()
 	schedulerCtx, schedulerCancel := context.WithCancel(ctx)
@@ -89,6 +96,7 @@ func main() {
 		slog.Error("Critical error initializing storage client", "error", err)
 		os.Exit(1)
 	}
+	store.SetDeleteBatchSize(cfg.StorageDeleteBatchSize)
 	defer func() {
 		if err := store.Close(); err != nil {
 			slog.Error("Error closing storage client", "error", err)
@@ -104,7 +112,17 @@ func main() {
 	n := notifier.New(cfg.DiscordBotToken,
 		cfg.XAPIKey, cfg.XAPIKeySecret, cfg.XAccessToken, cfg.XAccessTokenSecret,
 		cfg.X2APIKey, cfg.X2APIKeySecret, cfg.X2AccessToken, cfg.X2AccessTokenSecret)
+	n.SetStatsPlacement(cfg.StatsPlacement)
+	n.SetCompactEmbeds(cfg.CompactEmbeds)
+	n.SetItemLinkLabel(cfg.ItemLinkLabel, cfg.ItemLinkText)
+	n.SetPostedField(cfg.PostedFieldLayout, cfg.PostedFieldLocation)
+	n.SetStateColors(cfg.ExpiredColor, cfg.PriceErrorColor)
+	n.SetFreshBoostWindow(cfg.FreshBoostWindow)
+	n.SetEngagementEmoji(cfg.EngagementEmoji)
 	s := scraper.New(cfg, selectors)
+	// Retailer pages (Best Buy, eBay, Memory Express, Crux) are paced per host
+	// like RFD pages.
+	scrapebackend.SetHostLimiter(util.NewHostLimiter(cfg.HostRateLimit))
 	v := validator.New()
 
 	// Initialize AI client (uses Vertex AI with Application Default Credentials)
@@ -113,7 +131,28 @@ func main() {
 		slog.Warn("Failed to initialize Gemini client (AI features disabled)", "error", err)
 	}
 
-	p := processor.New(store, n, s, v, cfg, aiClient)
+	dealStore, closeDealStore, err := dealStoreFor(ctx, cfg, store)
+	if err != nil {
+		slog.Error("Critical error initializing deal store", "storage", cfg.Storage, "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := closeDealStore(); err != nil {
+			slog.Error("Error closing deal store", "error", err)
+		}
+	}()
+
+	// Pass a nil interface rather than a nil *ai.Client so the processor can
+	// tell Gemini is unavailable and fall back to deterministic title cleaning.
+	var dealAnalyzer processor.DealAnalyzer
+	if aiClient != nil {
+		dealAnalyzer = aiClient
+	}
+	p := processor.New(dealStore, n, s, v, cfg, dealAnalyzer)
+	if cfg.DealWebhookURL != "" {
+		p.AddSink(notifier.NewWebhookSink(cfg.DealWebhookURL))
+		slog.Info("Deal webhook enabled")
+	}
 
 	// Initialize eBay client (gracefully handles missing credentials)
 	ebayClient := ebay.NewClient(cfg.EbayClientID, cfg.EbayClientSecret)
@@ -283,7 +322,8 @@ func main() {
 		onEveryCorner:           onEveryCornerProc,
 		onEveryCornerController: onEveryCornerController,
 		aiClient:                aiClient,
-		store:                   store,
+		store:                   dealStore,
+		selectors:               s,
 		systemNotifier:          n,
 		db:                      store,
 		sem:                     make(chan struct{}, 2), // Allow up to 2 concurrent RFD processing attempts
@@ -296,6 +336,8 @@ func main() {
 		hwSem:                   make(chan struct{}, 1), // Allow 1 concurrent HardwareSwap processing attempt
 		coreIssueLast:           make(map[string]time.Time),
 		schedulerFailures:       make(map[string]scheduledProcessorFailure),
+		errorTolerance:          cfg.ErrorTolerance,
+		errorToleranceFraction:  cfg.ErrorToleranceFraction,
 	}
 
 	// Build HardwareSwap store for the API handler (may be nil if AI is unavailable)
@@ -324,6 +366,16 @@ func main() {
 	adminHandle("GET /process-bestbuy-compute", srv.ProcessBestBuyComputeHandler)
 	adminHandle("GET /process-crux", srv.ProcessCruxHandler)
 	adminHandle("POST /prime-bestbuy-baseline", srv.PrimeBestBuyBaselineHandler)
+	adminHandle("POST /migrate-deal-ids", srv.MigrateDealIDsHandler)
+	adminHandle("POST /admin/purge", srv.PurgeDealsHandler)
+	adminHandle("POST /admin/recover-notifications", srv.RecoverNotificationsHandler)
+	adminHandle("POST /admin/snooze", srv.SnoozeDealHandler)
+	adminHandle("POST /admin/refresh-embeds", srv.RefreshEmbedsHandler)
+	adminHandle("POST /admin/reprocess", srv.ReprocessRetailerHandler)
+	adminHandle("GET /deals/{id}/history", srv.DealHistoryHandler)
+	adminHandle("GET /admin/validate", srv.ValidateDealsHandler)
+	adminHandle("GET /admin/runs", srv.RunSummariesHandler)
+	adminHandle("POST /admin/reload-selectors", srv.ReloadSelectorsHandler)
 	mux.Handle("POST /ingest/discord-notification", swordswallowerOnly(cfg.RFDAdminToken, cfg.SwordswallowerSecret, http.HandlerFunc(srv.DiscordNotificationIngestHandler)))
 	adminHandle("POST /core/rebin", srv.CoreRebinHandler)
 	adminHandle("GET /core/raw-notifications", srv.CoreRawNotificationsHandler)
@@ -393,7 +445,7 @@ func main() {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
-		if err := srv.store.Ping(r.Context()); err != nil {
+		if err := srv.db.Ping(r.Context()); err != nil {
 			slog.Error("Health check failed", "error", err)
 			w.WriteHeader(http.StatusServiceUnavailable)
 			if encErr := json.NewEncoder(w).Encode(map[string]string{"status": "error", "details": err.Error()}); encErr != nil {
@@ -451,6 +503,50 @@ func main() {
 	slog.Info("Server stopped.")
 }
 
+// localDealStore keeps RFD deals in a memory or SQLite store while
+// subscriptions are still read from Postgres, so channels configured through
+// slash commands keep receiving posts.
+type localDealStore struct {
+	processor.DealStore
+	subs *storage.Client
+}
+
+func (l localDealStore) GetAllSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	return l.subs.GetAllSubscriptions(ctx)
+}
+
+func (l localDealStore) GetRecentRunSummaries(ctx context.Context, limit int) ([]models.RunSummary, error) {
+	return l.subs.GetRecentRunSummaries(ctx, limit)
+}
+
+func (l localDealStore) PurgeAll(ctx context.Context) (int, error) {
+	purger, ok := l.DealStore.(dealPurger)
+	if !ok {
+		return 0, fmt.Errorf("deal store %T does not support purge", l.DealStore)
+	}
+	return purger.PurgeAll(ctx)
+}
+
+// dealStoreFor returns the deal store selected by cfg.Storage and a function
+// that releases it.
+func dealStoreFor(ctx context.Context, cfg *config.Config, store *storage.Client) (processor.DealStore, func() error, error) {
+	noop := func() error { return nil }
+	switch cfg.Storage {
+	case "memory":
+		slog.Info("Using in-memory deal store; RFD deals will not persist across restarts")
+		return localDealStore{DealStore: storage.NewMemoryStore(), subs: store}, noop, nil
+	case "sqlite":
+		sqliteStore, err := storage.NewSQLite(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		slog.Info("Using SQLite deal store", "path", cfg.SQLitePath)
+		return localDealStore{DealStore: sqliteStore, subs: store}, sqliteStore.Close, nil
+	default:
+		return store, noop, nil
+	}
+}
+
 func hardwareswapStore(store *storage.Client) *hardwareswap.Store {
 	if store == nil {
 		return nil
@@ -471,6 +567,9 @@ type manualProcessOptions struct {
 	fn            func(context.Context) error
 	logAIState    bool
 	runStart      *atomic.Int64
+	// respond, if set, writes the response for a finished run (err is nil on
+	// success) instead of the plain-text default.
+	respond func(w http.ResponseWriter, err error)
 }
 
 func (s *Server) runManualProcess(w http.ResponseWriter, r *http.Request, opts manualProcessOptions) {
@@ -519,6 +618,10 @@ func (s *Server) runManualProcess(w http.ResponseWriter, r *http.Request, opts m
 		duration := time.Since(start).Round(time.Millisecond)
 		slog.Error("Manual processor failed", "processor", opts.processorName, "duration", duration.String(), "error", err)
 		s.reportScheduledProcessorFailure(opts.processorName, opts.timeout, duration, err)
+		if opts.respond != nil {
+			opts.respond(w, err)
+			return
+		}
 		http.Error(w, opts.errorMessage+" failed", http.StatusInternalServerError)
 		return
 	}
@@ -526,18 +629,39 @@ func (s *Server) runManualProcess(w http.ResponseWriter, r *http.Request, opts m
 	slog.Info(opts.finishMessage, "processor", opts.processorName, "duration", duration.String())
 	s.reportScheduledProcessorRecovery(opts.processorName, duration)
 
+	if opts.respond != nil {
+		opts.respond(w, nil)
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintln(w, opts.successText)
 }
 
+// resultProcessor is implemented by processors that report per-run counts.
+type resultProcessor interface {
+	ProcessDealsWithResult(ctx context.Context) (processor.RunResult, error)
+}
+
+// statusClientClosedRequest is nginx's non-standard 499, used when the caller
+// went away before the run finished.
+const statusClientClosedRequest = 499
+
+// ProcessDealsHandler runs the RFD processor and responds with JSON:
+// {"status":"ok","new":N,"updated":M,"skipped":K,"failed":F,"errors":[...]}.
+// Per-deal failures within ERROR_TOLERANCE report "warning" with a 200; above
+// it they report "partial", and hard failures "error", both with a 500 so
+// schedulers still flag them. A run stopped because the request was cancelled
+// reports "cancelled" with a 499, and one that ran out of time "timeout" with
+// a 503.
 func (s *Server) ProcessDealsHandler(w http.ResponseWriter, r *http.Request) {
+	var result processor.RunResult
+	var started time.Time
 	s.runManualProcess(w, r, manualProcessOptions{
 		processorName: "rfd",
 		startMessage:  "Starting RFD deal processing",
 		finishMessage: "RFD deal processing finished",
 		errorMessage:  "deal processing",
 		panicMessage:  "Panic in ProcessDeals",
-		successText:   "Deal processing finished.",
 		busyDetails:   "server is busy processing deals",
 		sem:           s.sem,
 		timeout:       4 * time.Minute,
@@ -545,12 +669,140 @@ func (s *Server) ProcessDealsHandler(w http.ResponseWriter, r *http.Request) {
 			if s.processor == nil {
 				return nil
 			}
+			if rp, ok := s.processor.(resultProcessor); ok {
+				var err error
+				started = time.Now()
+				result, err = rp.ProcessDealsWithResult(ctx)
+				if errors.Is(err, processor.ErrPartialRun) && s.toleratesErrors(result) {
+					slog.Warn("RFD deal processing finished with tolerated errors", "processor", "rfd", "failed", len(result.Errors), "error", err)
+					return nil
+				}
+				return err
+			}
 			return s.processor.ProcessDeals(ctx)
 		},
 		logAIState: true,
+		respond: func(w http.ResponseWriter, err error) {
+			if !started.IsZero() {
+				s.recordRunSummary(started, result, err)
+			}
+			writeRunResult(w, result, err)
+		},
 	})
 }
 
+// runStatus maps a finished run to its /process-deals status, HTTP code and
+// reported errors, grouped by cause when the processor summarized them.
+func runStatus(result processor.RunResult, err error) (status string, code int, errs []string) {
+	status, code = "ok", http.StatusOK
+	errs = result.Errors
+	if len(result.ErrorSummary) > 0 {
+		errs = result.ErrorSummary
+	}
+	if err == nil && len(errs) > 0 {
+		status = "warning"
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		status, code = "cancelled", statusClientClosedRequest
+		errs = append(errs, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		status, code = "timeout", http.StatusServiceUnavailable
+		errs = append(errs, err.Error())
+	case err != nil:
+		status, code = "partial", http.StatusInternalServerError
+		if len(errs) == 0 {
+			status, errs = "error", []string{err.Error()}
+		}
+	}
+	if errs == nil {
+		errs = []string{}
+	}
+	return status, code, errs
+}
+
+// maxRunSummaries bounds the runs collection: a week of 5-minute runs.
+const maxRunSummaries = 7 * 24 * 12
+
+// recordRunSummary appends a finished RFD run to the runs collection. It is
+// best-effort: a storage failure is logged and never fails the run.
+func (s *Server) recordRunSummary(started time.Time, result processor.RunResult, err error) {
+	if s.db == nil {
+		return
+	}
+	status, _, _ := runStatus(result, err)
+	run := models.RunSummary{
+		StartedAt:  started.UTC(),
+		DurationMs: time.Since(started).Milliseconds(),
+		Status:     status,
+		ScrapeOK:   result.Scraped > 0,
+		Scraped:    result.Scraped,
+		New:        result.New,
+		Updated:    result.Updated,
+		Skipped:    result.Skipped,
+		Failed:     len(result.Errors),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storage.DefaultTimeout)
+	defer cancel()
+	if err := s.db.AppendRunSummary(ctx, run, maxRunSummaries); err != nil {
+		slog.Warn("Failed to record run summary", "processor", "rfd", "error", err)
+	}
+}
+
+// RunSummariesHandler returns recent RFD run summaries, newest first, for
+// charting bot health. ?limit= caps the count (default 100).
+func (s *Server) RunSummariesHandler(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		http.Error(w, "storage client not initialized", http.StatusInternalServerError)
+		return
+	}
+
+	limit := 100
+	if val, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && val > 0 {
+		limit = min(val, maxRunSummaries)
+	}
+	runs, err := s.db.GetRecentRunSummaries(r.Context(), limit)
+	if err != nil {
+		slog.Error("Failed to retrieve run summaries", "error", err)
+		http.Error(w, "internal server error: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(runs); err != nil {
+		slog.Error("Failed to encode run summaries response", "error", err)
+	}
+}
+
+// toleratesErrors reports whether a run's per-deal failures are within
+// ERROR_TOLERANCE, either as a count or as a fraction of the run's deals.
+func (s *Server) toleratesErrors(result processor.RunResult) bool {
+	failed := len(result.Errors)
+	if s.errorToleranceFraction > 0 {
+		total := max(result.New+result.Updated+result.Skipped, failed)
+		return float64(failed) <= s.errorToleranceFraction*float64(total)
+	}
+	return failed <= s.errorTolerance
+}
+
+func writeRunResult(w http.ResponseWriter, result processor.RunResult, err error) {
+	status, code, errs := runStatus(result, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"status":  status,
+		"new":     result.New,
+		"updated": result.Updated,
+		"skipped": result.Skipped,
+		"failed":  len(result.Errors),
+		"errors":  errs,
+		"diff":    result.Diff,
+	}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
 func (s *Server) ProcessEbayHandler(w http.ResponseWriter, r *http.Request) {
 	if s.ebayProcessor == nil {
 		writeSkipped(w, "ebay", "eBay features not configured")
@@ -659,6 +911,301 @@ func (s *Server) ProcessCruxHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// MigrateDealIDsHandler rewrites legacy auto-ID deal documents under their
+// deterministic IDs. It is safe to call repeatedly.
+func (s *Server) MigrateDealIDsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		http.Error(w, "storage not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	migrated, err := s.db.MigrateDealIDs(r.Context())
+	if err != nil {
+		slog.Error("Deal ID migration failed", "processor", "rfd", "migrated", migrated, "error", err)
+		http.Error(w, fmt.Sprintf("deal ID migration failed after %d documents: %v", migrated, err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Deal ID migration complete", "processor", "rfd", "migrated", migrated)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "migrated": migrated}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+// purgeConfirmation must be passed as ?confirm= to /admin/purge so the
+// endpoint can't be triggered by an accidental authenticated request.
+const purgeConfirmation = "delete-all-deals"
+
+// dealPurger is implemented by deal stores that can delete every deal.
+type dealPurger interface {
+	PurgeAll(ctx context.Context) (int, error)
+}
+
+// PurgeDealsHandler deletes every stored RFD deal so the bot can re-bootstrap
+// after a drastic selector change.
+func (s *Server) PurgeDealsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("confirm") != purgeConfirmation {
+		http.Error(w, fmt.Sprintf("refusing to purge: pass confirm=%s", purgeConfirmation), http.StatusBadRequest)
+		return
+	}
+	purger, ok := s.store.(dealPurger)
+	if !ok {
+		http.Error(w, "deal store does not support purge", http.StatusServiceUnavailable)
+		return
+	}
+
+	deleted, err := purger.PurgeAll(r.Context())
+	if err != nil {
+		slog.Error("Deal purge failed", "processor", "rfd", "error", err)
+		http.Error(w, fmt.Sprintf("deal purge failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Warn("Purged all stored deals", "processor", "rfd", "deleted", deleted)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "deleted": deleted}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+// dealModifier is implemented by deal stores that can update a deal without
+// clobbering concurrent writes to it.
+type dealModifier interface {
+	ModifyDeal(ctx context.Context, id string, mutate func(*models.DealInfo)) (*models.DealInfo, error)
+}
+
+// SnoozeDealHandler pauses Discord edits for one deal (?id=<deal ID>&for=6h).
+// The deal keeps being tracked and saved; for=0 lifts the snooze.
+func (s *Server) SnoozeDealHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "missing id", http.StatusBadRequest)
+		return
+	}
+	duration, err := time.ParseDuration(r.URL.Query().Get("for"))
+	if err != nil || duration < 0 {
+		http.Error(w, "invalid for: pass a duration like 6h", http.StatusBadRequest)
+		return
+	}
+
+	snooze := func(deal *models.DealInfo) {
+		deal.SnoozeUntil = time.Time{}
+		if duration > 0 {
+			deal.SnoozeUntil = time.Now().Add(duration)
+		}
+	}
+
+	var deal *models.DealInfo
+	if modifier, ok := s.store.(dealModifier); ok {
+		deal, err = modifier.ModifyDeal(r.Context(), id, snooze)
+	} else if deal, err = s.store.GetDealByID(r.Context(), id); err == nil && deal != nil {
+		snooze(deal)
+		err = s.store.UpdateDeal(r.Context(), *deal)
+	}
+	if err != nil {
+		slog.Error("Failed to snooze deal", "processor", "rfd", "id", id, "error", err)
+		http.Error(w, fmt.Sprintf("failed to snooze deal: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if deal == nil {
+		http.Error(w, "deal not found", http.StatusNotFound)
+		return
+	}
+
+	slog.Info("Deal updates snoozed", "processor", "rfd", "id", id, "until", deal.SnoozeUntil)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "id": id, "snooze_until": deal.SnoozeUntil}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+// DealHistoryHandler returns one deal's price and engagement timeline, oldest
+// snapshot first.
+func (s *Server) DealHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	deal, err := s.store.GetDealByID(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to load deal history", "processor", "rfd", "id", id, "error", err)
+		http.Error(w, fmt.Sprintf("failed to load deal: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if deal == nil {
+		http.Error(w, "deal not found", http.StatusNotFound)
+		return
+	}
+
+	history := deal.History
+	if history == nil {
+		history = []models.DealSnapshot{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"id":         id,
+		"title":      deal.Title,
+		"first_seen": deal.FirstSeen,
+		"history":    history,
+	}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+// defaultValidateWindow is how far back ValidateDealsHandler looks by default,
+// matching the processor's deduplication window.
+const defaultValidateWindow = 48 * time.Hour
+
+// dealValidationFailure is one stored deal the current validator rejects.
+type dealValidationFailure struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+// ValidateDealsHandler runs the current validator over recently published
+// deals (?within=48h) and reports the ones that fail, without changing them.
+// Use it after tightening validation rules to find data from older scrapes.
+func (s *Server) ValidateDealsHandler(w http.ResponseWriter, r *http.Request) {
+	within := defaultValidateWindow
+	if raw := r.URL.Query().Get("within"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			http.Error(w, "invalid within: pass a duration like 48h", http.StatusBadRequest)
+			return
+		}
+		within = d
+	}
+
+	deals, err := s.store.GetRecentDeals(r.Context(), within)
+	if err != nil {
+		slog.Error("Failed to load deals for validation", "processor", "rfd", "error", err)
+		http.Error(w, fmt.Sprintf("failed to load deals: %v", err), http.StatusInternalServerError)
+		return
+	}
+	v := validator.New()
+	failures := []dealValidationFailure{}
+	for i := range deals {
+		if err := v.ValidateStruct(&deals[i]); err != nil {
+			failures = append(failures, dealValidationFailure{ID: deals[i].DocumentID, Title: deals[i].Title, Error: err.Error()})
+		}
+	}
+	if len(failures) > 0 {
+		slog.Warn("Stored deals fail validation", "processor", "rfd", "checked", len(deals), "invalid", len(failures))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"checked": len(deals), "invalid": failures}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+type selectorReloader interface {
+	ReloadSelectors() (scraper.SelectorConfig, error)
+}
+
+// ReloadSelectorsHandler re-reads the external selectors file so a fix for an
+// RFD layout change takes effect without a deploy. A bad file is rejected and
+// the running selectors are kept.
+func (s *Server) ReloadSelectorsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.selectors == nil {
+		http.Error(w, "selector reload not available", http.StatusServiceUnavailable)
+		return
+	}
+	selectors, err := s.selectors.ReloadSelectors()
+	if err != nil {
+		slog.Error("Failed to reload selectors", "processor", "rfd", "error", err)
+		http.Error(w, fmt.Sprintf("failed to reload selectors: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "selectors": selectors}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+type notificationRecoverer interface {
+	RecoverMissingNotifications(ctx context.Context) (int, error)
+}
+
+// RecoverNotificationsHandler re-sends recent deals that never reached
+// Discord, for manual recovery after an outage.
+func (s *Server) RecoverNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	recoverer, ok := s.processor.(notificationRecoverer)
+	if !ok {
+		http.Error(w, "deal processor does not support notification recovery", http.StatusServiceUnavailable)
+		return
+	}
+
+	recovered, err := recoverer.RecoverMissingNotifications(r.Context())
+	if err != nil {
+		slog.Error("Notification recovery failed", "processor", "rfd", "error", err)
+		http.Error(w, fmt.Sprintf("notification recovery failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "recovered": recovered}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+type embedRefresher interface {
+	RefreshEmbeds(ctx context.Context) (int, error)
+}
+
+// RefreshEmbedsHandler re-renders recent deals' Discord messages with the
+// current embed format, for use after changing the layout.
+func (s *Server) RefreshEmbedsHandler(w http.ResponseWriter, r *http.Request) {
+	refresher, ok := s.processor.(embedRefresher)
+	if !ok {
+		http.Error(w, "deal processor does not support embed refresh", http.StatusServiceUnavailable)
+		return
+	}
+
+	refreshed, err := refresher.RefreshEmbeds(r.Context())
+	if err != nil {
+		slog.Error("Embed refresh failed", "processor", "rfd", "error", err)
+		http.Error(w, fmt.Sprintf("embed refresh failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "refreshed": refreshed}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
+type retailerReprocessor interface {
+	ReprocessRetailer(ctx context.Context, retailer string) (int, error)
+}
+
+// ReprocessRetailerHandler re-fetches, re-cleans and re-renders recent deals
+// from one retailer (?retailer=X), e.g. after a prompt or parsing fix.
+func (s *Server) ReprocessRetailerHandler(w http.ResponseWriter, r *http.Request) {
+	retailer := strings.TrimSpace(r.URL.Query().Get("retailer"))
+	if retailer == "" {
+		http.Error(w, "missing retailer", http.StatusBadRequest)
+		return
+	}
+	reprocessor, ok := s.processor.(retailerReprocessor)
+	if !ok {
+		http.Error(w, "deal processor does not support reprocessing", http.StatusServiceUnavailable)
+		return
+	}
+
+	reprocessed, err := reprocessor.ReprocessRetailer(r.Context(), retailer)
+	if err != nil {
+		slog.Error("Retailer reprocess failed", "processor", "rfd", "retailer", retailer, "error", err)
+		http.Error(w, fmt.Sprintf("reprocess failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"status": "ok", "retailer": retailer, "reprocessed": reprocessed}); err != nil {
+		slog.Error("Failed to encode response", "processor", "rfd", "error", err)
+	}
+}
+
 func (s *Server) PrimeBestBuyBaselineHandler(w http.ResponseWriter, r *http.Request) {
 	if s.bestbuyProcessor == nil {
 		slog.Info("PrimeBestBuyBaselineHandler: Best Buy processor not configured, skipping", "processor", "bestbuy")
@@ -5,15 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pauljones0/rfd-discord-bot/internal/config"
 	"github.com/pauljones0/rfd-discord-bot/internal/dealtypes"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/processor"
+	"github.com/pauljones0/rfd-discord-bot/internal/scraper"
+	"github.com/pauljones0/rfd-discord-bot/internal/storage"
 )
 
 type testProcessor struct {
@@ -28,6 +34,30 @@ func (p *testProcessor) ProcessDeals(ctx context.Context) error {
 	return nil
 }
 
+type resultTestProcessor struct {
+	result processor.RunResult
+	err    error
+}
+
+func (p *resultTestProcessor) ProcessDeals(ctx context.Context) error {
+	_, err := p.ProcessDealsWithResult(ctx)
+	return err
+}
+
+func (p *resultTestProcessor) ProcessDealsWithResult(context.Context) (processor.RunResult, error) {
+	return p.result, p.err
+}
+
+type reprocessTestProcessor struct {
+	resultTestProcessor
+	retailers []string
+}
+
+func (p *reprocessTestProcessor) ReprocessRetailer(_ context.Context, retailer string) (int, error) {
+	p.retailers = append(p.retailers, retailer)
+	return 2, nil
+}
+
 type scheduledAlertTestStore struct {
 	subs []models.Subscription
 }
@@ -103,6 +133,222 @@ func TestProcessDealsHandler_RunsInlineAndReturnsOK(t *testing.T) {
 	}
 }
 
+func TestProcessDealsHandler_RespondsWithRunResultJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		proc       *resultTestProcessor
+		wantCode   int
+		wantStatus string
+		wantErrors []string
+	}{
+		{
+			name:       "success",
+			proc:       &resultTestProcessor{result: processor.RunResult{New: 2, Updated: 3, Skipped: 4}},
+			wantCode:   http.StatusOK,
+			wantStatus: "ok",
+			wantErrors: []string{},
+		},
+		{
+			name: "partial errors",
+			proc: &resultTestProcessor{
+				result: processor.RunResult{New: 1, Updated: 3, Skipped: 4, Errors: []string{"new deal error A: boom"}},
+				err:    fmt.Errorf("%w: new deal error A: boom", processor.ErrPartialRun),
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantStatus: "partial",
+			wantErrors: []string{"new deal error A: boom"},
+		},
+		{
+			name:       "hard failure",
+			proc:       &resultTestProcessor{err: errors.New("failed to scrape hot deals list")},
+			wantCode:   http.StatusInternalServerError,
+			wantStatus: "error",
+			wantErrors: []string{"failed to scrape hot deals list"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &Server{processor: tt.proc, sem: make(chan struct{}, 1)}
+			rec := httptest.NewRecorder()
+			srv.ProcessDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/process-deals", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Fatalf("Content-Type = %q, want application/json", ct)
+			}
+			var body struct {
+				Status  string   `json:"status"`
+				New     int      `json:"new"`
+				Updated int      `json:"updated"`
+				Skipped int      `json:"skipped"`
+				Errors  []string `json:"errors"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Fatalf("status body = %q, want %q", body.Status, tt.wantStatus)
+			}
+			if body.New != tt.proc.result.New || body.Updated != tt.proc.result.Updated || body.Skipped != tt.proc.result.Skipped {
+				t.Fatalf("counts = %d/%d/%d, want %d/%d/%d", body.New, body.Updated, body.Skipped,
+					tt.proc.result.New, tt.proc.result.Updated, tt.proc.result.Skipped)
+			}
+			if body.Errors == nil || strings.Join(body.Errors, "|") != strings.Join(tt.wantErrors, "|") {
+				t.Fatalf("errors = %#v, want %#v", body.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+// blockingTestProcessor runs until its context is done.
+type blockingTestProcessor struct{}
+
+func (blockingTestProcessor) ProcessDeals(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestProcessDealsHandler_StopsWhenRequestCancelled(t *testing.T) {
+	srv := &Server{processor: blockingTestProcessor{}, sem: make(chan struct{}, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/process-deals", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		srv.ProcessDealsHandler(rec, req)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return after the request was cancelled")
+	}
+	if rec.Code != statusClientClosedRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, statusClientClosedRequest)
+	}
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+	}
+	if body.Status != "cancelled" {
+		t.Fatalf("status body = %q, want cancelled", body.Status)
+	}
+}
+
+func TestProcessDealsHandler_RequestDeadlineReachesProcessor(t *testing.T) {
+	srv := &Server{processor: blockingTestProcessor{}, sem: make(chan struct{}, 1)}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	srv.ProcessDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/process-deals", nil).WithContext(ctx))
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("handler took %v, want it to stop at the request deadline", elapsed)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"timeout"`) {
+		t.Fatalf("body = %s, want timeout status", rec.Body.String())
+	}
+}
+
+func TestReprocessRetailerHandler(t *testing.T) {
+	proc := &reprocessTestProcessor{}
+	srv := &Server{processor: proc}
+
+	rec := httptest.NewRecorder()
+	srv.ReprocessRetailerHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reprocess", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing retailer: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ReprocessRetailerHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reprocess?retailer=Best+Buy", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if len(proc.retailers) != 1 || proc.retailers[0] != "Best Buy" {
+		t.Fatalf("reprocessed retailers = %v, want [Best Buy]", proc.retailers)
+	}
+	var body struct {
+		Status      string `json:"status"`
+		Retailer    string `json:"retailer"`
+		Reprocessed int    `json:"reprocessed"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+	}
+	if body.Status != "ok" || body.Retailer != "Best Buy" || body.Reprocessed != 2 {
+		t.Fatalf("body = %+v, want ok/Best Buy/2", body)
+	}
+
+	rec = httptest.NewRecorder()
+	(&Server{processor: &resultTestProcessor{}}).ReprocessRetailerHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reprocess?retailer=Amazon", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unsupported processor: status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestProcessDealsHandler_ErrorTolerance(t *testing.T) {
+	// 2 of 40 deals failed.
+	partial := func() *resultTestProcessor {
+		return &resultTestProcessor{
+			result: processor.RunResult{New: 10, Updated: 20, Skipped: 10, Errors: []string{"a", "b"}},
+			err:    fmt.Errorf("%w: a; b", processor.ErrPartialRun),
+		}
+	}
+	tests := []struct {
+		name       string
+		tolerance  int
+		fraction   float64
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "no tolerance", wantCode: http.StatusInternalServerError, wantStatus: "partial"},
+		{name: "below count", tolerance: 2, wantCode: http.StatusOK, wantStatus: "warning"},
+		{name: "above count", tolerance: 1, wantCode: http.StatusInternalServerError, wantStatus: "partial"},
+		{name: "below fraction", fraction: 0.1, wantCode: http.StatusOK, wantStatus: "warning"},
+		{name: "above fraction", fraction: 0.01, wantCode: http.StatusInternalServerError, wantStatus: "partial"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &Server{
+				processor:              partial(),
+				sem:                    make(chan struct{}, 1),
+				errorTolerance:         tt.tolerance,
+				errorToleranceFraction: tt.fraction,
+			}
+			rec := httptest.NewRecorder()
+			srv.ProcessDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/process-deals", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var body struct {
+				Status string `json:"status"`
+				Failed int    `json:"failed"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+			}
+			if body.Status != tt.wantStatus || body.Failed != 2 {
+				t.Fatalf("body = %+v, want status %q with 2 failed", body, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestProcessDealsHandler_ReturnsBusyWhenSemaphoreFull(t *testing.T) {
 	srv := &Server{
 		sem: make(chan struct{}, 1),
@@ -474,3 +720,252 @@ func TestLoggingMiddlewareRecordsResponseStatusAndBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestPurgeDealsHandler_RequiresConfirmation(t *testing.T) {
+	mem := storage.NewMemoryStore()
+	if err := mem.TryCreateDeal(context.Background(), models.DealInfo{DocumentID: "deal-1"}); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	srv := &Server{store: localDealStore{DealStore: mem}}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/purge", nil)
+	rec := httptest.NewRecorder()
+	srv.PurgeDealsHandler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status without confirm = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if deal, _ := mem.GetDealByID(context.Background(), "deal-1"); deal == nil {
+		t.Fatal("deal was purged without confirmation")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/purge?confirm="+purgeConfirmation, nil)
+	rec = httptest.NewRecorder()
+	srv.PurgeDealsHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if body["deleted"] != float64(1) {
+		t.Fatalf("deleted = %v, want 1", body["deleted"])
+	}
+	if deal, _ := mem.GetDealByID(context.Background(), "deal-1"); deal != nil {
+		t.Fatal("deal still present after purge")
+	}
+}
+
+func TestPurgeDealsHandler_UsesSelectedDealStore(t *testing.T) {
+	ctx := context.Background()
+	dealStore, closeStore, err := dealStoreFor(ctx, &config.Config{Storage: "memory"}, nil)
+	if err != nil {
+		t.Fatalf("dealStoreFor() error = %v", err)
+	}
+	defer closeStore()
+	if _, ok := dealStore.(localDealStore); !ok {
+		t.Fatalf("dealStoreFor(memory) = %T, want localDealStore", dealStore)
+	}
+	if err := dealStore.TryCreateDeal(ctx, models.DealInfo{DocumentID: "deal-1"}); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	srv := &Server{store: dealStore}
+
+	rec := httptest.NewRecorder()
+	srv.PurgeDealsHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/purge?confirm="+purgeConfirmation, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if deal, _ := dealStore.GetDealByID(ctx, "deal-1"); deal != nil {
+		t.Fatal("deal still present in the selected store after purge")
+	}
+}
+
+func TestDealHistoryHandler(t *testing.T) {
+	mem := storage.NewMemoryStore()
+	deal := models.DealInfo{DocumentID: "deal-1", Title: "Echo Dot", Price: "$39", Threads: []models.ThreadContext{{LikeCount: 2, CommentCount: 1}}}
+	first := time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC)
+	deal.RecordSnapshot(first, 0)
+	deal.Price = "$29"
+	deal.Threads[0].LikeCount = 40
+	deal.RecordSnapshot(first.Add(time.Hour), 0)
+	if err := mem.TryCreateDeal(context.Background(), deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	srv := &Server{store: localDealStore{DealStore: mem}}
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/deals/"+id+"/history", nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		srv.DealHistoryHandler(rec, req)
+		return rec
+	}
+
+	if rec := get("missing"); rec.Code != http.StatusNotFound {
+		t.Fatalf("status for unknown deal = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec := get("deal-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body struct {
+		ID      string                `json:"id"`
+		History []models.DealSnapshot `json:"history"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != "deal-1" || len(body.History) != 2 {
+		t.Fatalf("response = %+v, want 2 snapshots for deal-1", body)
+	}
+	if got := body.History[0]; got.Price != "$39" || got.Likes != 2 || !got.At.Equal(first) {
+		t.Errorf("first snapshot = %+v, want $39 with 2 likes at %v", got, first)
+	}
+	if got := body.History[1]; got.Price != "$29" || got.Likes != 40 {
+		t.Errorf("second snapshot = %+v, want $29 with 40 likes", got)
+	}
+}
+
+func TestDealAdminHandlers_UseSelectedDealStore(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Storage: "sqlite", SQLitePath: filepath.Join(t.TempDir(), "deals.db")}
+	dealStore, closeStore, err := dealStoreFor(ctx, cfg, nil)
+	if err != nil {
+		t.Fatalf("dealStoreFor() error = %v", err)
+	}
+	defer closeStore()
+	deal := models.DealInfo{DocumentID: "deal-1", Title: "Echo Dot", PostURL: "not a url", PublishedTimestamp: time.Now()}
+	deal.RecordSnapshot(time.Now(), 0)
+	if err := dealStore.TryCreateDeal(ctx, deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	srv := &Server{store: dealStore}
+
+	rec := httptest.NewRecorder()
+	srv.SnoozeDealHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/snooze?id=deal-1&for=2h", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("snooze status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got, _ := dealStore.GetDealByID(ctx, "deal-1"); got == nil || time.Until(got.SnoozeUntil) < time.Hour {
+		t.Fatalf("SnoozeUntil not saved to the selected store: %+v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/deals/deal-1/history", nil)
+	req.SetPathValue("id", "deal-1")
+	rec = httptest.NewRecorder()
+	srv.DealHistoryHandler(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"history":[{`) {
+		t.Fatalf("history status = %d, body %s, want the stored snapshot", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ValidateDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/admin/validate", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"deal-1"`) {
+		t.Fatalf("validate status = %d, body %s, want deal-1 reported invalid", rec.Code, rec.Body.String())
+	}
+}
+
+func TestValidateDealsHandler(t *testing.T) {
+	mem := storage.NewMemoryStore()
+	now := time.Now()
+	for _, deal := range []models.DealInfo{
+		{DocumentID: "valid", Title: "Echo Dot", PostURL: "https://forums.redflagdeals.com/echo-dot-1", PublishedTimestamp: now},
+		{DocumentID: "bad-url", Title: "Broken", PostURL: "not a url", PublishedTimestamp: now.Add(-time.Hour)},
+	} {
+		if err := mem.TryCreateDeal(context.Background(), deal); err != nil {
+			t.Fatalf("TryCreateDeal(%s) error = %v", deal.DocumentID, err)
+		}
+	}
+	srv := &Server{store: localDealStore{DealStore: mem}}
+
+	rec := httptest.NewRecorder()
+	srv.ValidateDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/admin/validate?within=soon", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid within: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ValidateDealsHandler(rec, httptest.NewRequest(http.MethodGet, "/admin/validate", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body struct {
+		Checked int                     `json:"checked"`
+		Invalid []dealValidationFailure `json:"invalid"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", rec.Body.String(), err)
+	}
+	if body.Checked != 2 || len(body.Invalid) != 1 {
+		t.Fatalf("body = %+v, want 2 checked and 1 invalid", body)
+	}
+	if got := body.Invalid[0]; got.ID != "bad-url" || !strings.Contains(got.Error, "PostURL") {
+		t.Errorf("invalid deal = %+v, want bad-url failing on PostURL", got)
+	}
+	if deal, _ := mem.GetDealByID(context.Background(), "bad-url"); deal == nil || deal.PostURL != "not a url" {
+		t.Errorf("stored deal = %+v, want it left unchanged", deal)
+	}
+}
+
+type fakeSelectorReloader struct {
+	selectors scraper.SelectorConfig
+	err       error
+}
+
+func (f fakeSelectorReloader) ReloadSelectors() (scraper.SelectorConfig, error) {
+	return f.selectors, f.err
+}
+
+func TestReloadSelectorsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Server{}).ReloadSelectorsHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reload-selectors", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("without a reloader: status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	srv := &Server{selectors: fakeSelectorReloader{err: errors.New("missing required selectors")}}
+	rec = httptest.NewRecorder()
+	srv.ReloadSelectorsHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reload-selectors", nil))
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "missing required selectors") {
+		t.Errorf("bad file: status = %d body = %q, want %d with the reason", rec.Code, rec.Body.String(), http.StatusUnprocessableEntity)
+	}
+
+	srv = &Server{selectors: fakeSelectorReloader{selectors: scraper.DefaultSelectors()}}
+	rec = httptest.NewRecorder()
+	srv.ReloadSelectorsHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reload-selectors", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"ok"`) {
+		t.Errorf("status = %d body = %q, want 200 ok", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSnoozeDealHandler(t *testing.T) {
+	mem := storage.NewMemoryStore()
+	if err := mem.TryCreateDeal(context.Background(), models.DealInfo{DocumentID: "deal-1"}); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	srv := &Server{store: localDealStore{DealStore: mem}}
+
+	rec := httptest.NewRecorder()
+	srv.SnoozeDealHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/snooze?id=deal-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status without duration = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.SnoozeDealHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/snooze?id=missing&for=1h", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status for unknown deal = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.SnoozeDealHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/snooze?id=deal-1&for=2h", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	deal, _ := mem.GetDealByID(context.Background(), "deal-1")
+	if deal == nil || time.Until(deal.SnoozeUntil) < time.Hour {
+		t.Fatalf("SnoozeUntil not set ~2h ahead: %+v", deal)
+	}
+}
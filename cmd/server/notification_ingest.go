@@ -151,7 +151,9 @@ func (s *Server) handleDiscordNotification(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Pass the batched messages to the core processor
+	// Pass the batched messages to the core processor. This outlives the
+	// request, which is acknowledged straight away, so it can't use
+	// r.Context().
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -2,12 +2,17 @@ package config
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/joho/godotenv"
 )
@@ -15,14 +20,72 @@ import (
 type Config struct {
 	ProjectID              string
 	DatabaseURL            string
+	Storage                string // deal store backend: "postgres" (default), "memory", or "sqlite"
+	SQLitePath             string
 	Port                   string
 	AmazonAffiliateTag     string
+	AmazonAffiliateTags    map[string]string // marketplace host -> store ID; AmazonAffiliateTag is the fallback
+	NoAffiliateDomains     []string          // NO_AFFILIATE_DOMAINS: links to these domains are unwrapped but never affiliate-tagged
 	BestBuyAffiliatePrefix string
 	DiscordUpdateInterval  time.Duration
 	RFDPollInterval        time.Duration
+	RFDDetailTimeout       time.Duration // per-deal budget for fetching one detail page, retries included
+	RFDDetailCacheSize     int           // max cached detail-page results; 0 disables the cache
+	DetailFetch            string        // DETAIL_FETCH: which deals get detail pages: "all" (default), "new-only", or "top-n"
+	DetailFetchTopN        int           // DETAIL_FETCH_TOP_N: detail pages per run under DETAIL_FETCH=top-n
+	CommentCountSources    []string      // COMMENT_COUNT_SOURCES: comment count sources in preference order: list, jsonld, detail
+	MaxDescriptionLen      int           // MAX_DESCRIPTION_LEN: detail-page post text kept, in characters; 0 keeps it whole
+	MaxCommentsLen         int           // MAX_COMMENTS_LEN: detail-page comment text kept, in characters; 0 keeps it whole
+	RFDDetailCacheTTL      time.Duration
+	RFDFailureAlertAfter   int           // consecutive failed list scrapes before alerting and cooling down; 0 disables
+	RFDFailureCooldown     time.Duration // first cooldown after RFDFailureAlertAfter failures, doubling per further failure
+	HostRateLimit          int           // max outbound requests per second to any one host; 0 disables pacing
 	MaxStoredDeals         int
+	SnapshotInterval       time.Duration // SNAPSHOT_INTERVAL: minimum gap between a deal's history snapshots; 0 records every change
+	StorageDeleteBatchSize int           // STORAGE_DELETE_BATCH_SIZE: deals deleted per statement when trimming or purging; 0 deletes in one statement
 	AllowedDomains         []string
 	RFDBaseURL             string
+	CanonicalHosts         map[string]string // CANONICAL_HOSTS: extra post-URL host rewrites on top of util.DefaultCanonicalHosts
+	RFDSort                string            // hot-deals list sort: "newest" (default), "replies", or "views"
+	RFDMaxPages            int               // RFD_MAX_PAGES: hot-deals list pages followed per run; 1 scrapes only the first
+	RFDIncludeExpired      bool              // RFD_INCLUDE_EXPIRED: also scrape the Expired Hot Deals forum as an archive
+	RFDExtraForums         []string          // RFD_EXTRA_FORUMS: more forum slugs scraped after hot deals, e.g. "computers-electronics-f214"
+	ExpiredDealsChannel    string            // EXPIRED_DEALS_CHANNEL: the only channel expired deals post to; empty stores them silently
+	AlwaysNotifyKeywords   []string          // title/retailer keywords that skip the warm/hot gate
+	BlockAuthors           []string          // RFD usernames whose deals are stored but never posted
+	CategoryChannels       map[string]string // lowercased RFD category -> channel ID reserved for it
+	LabelRules             []LabelRule       // DEAL_LABEL_RULES: labels attached to matching RFD deals
+	TitleStripPatterns     []string          // regexes for non-AI title cleaning; nil uses util.DefaultTitleStripPatterns
+	PriceErrorPatterns     []string          // regexes flagging price-error deals; nil uses util.DefaultPriceErrorPatterns
+	YMMVPatterns           []string          // regexes flagging YMMV deals; nil uses util.DefaultYMMVPatterns
+	RegionPatterns         []string          // regexes capturing a region hint; nil uses util.DefaultRegionPatterns
+	StatsPlacement         string            // where deal embeds show engagement: "description" (default), "title", "field", or "both"
+	CompactEmbeds          bool              // COMPACT_EMBEDS: single-line RFD deal embeds without a thumbnail
+	ItemLinkLabel          string            // ITEM_LINK_LABEL: compact embed item link label, "fixed" (default, ITEM_LINK_TEXT) or "domain"
+	ItemLinkText           string            // ITEM_LINK_TEXT: fixed item link label; empty uses "Item"
+	PostedFieldLayout      string            // POSTED_FIELD_LAYOUT: Go time layout for the "Posted" embed field; empty when POSTED_FIELD is off
+	PostedFieldLocation    *time.Location    // POSTED_FIELD_TIMEZONE: zone the "Posted" field is shown in; defaults to UTC
+	ExpiredColor           int               // EMBED_COLOR_EXPIRED: embed color for expired deals; 0 keeps the default grey
+	PriceErrorColor        int               // EMBED_COLOR_PRICE_ERROR: embed color for price-error deals; 0 keeps the default purple
+	FreshBoostWindow       time.Duration     // FRESH_BOOST_WINDOW: cold deals younger than this are tinted toward warm; 0 disables
+	EngagementEmoji        map[string]string // ENGAGEMENT_EMOJI: like/dislike/comment/view -> emoji replacing the default 👍/👎/💬/👀
+	SuppressDealUpdates    bool              // NOTIFY_UPDATES=false: persist changes but never edit posted Discord messages
+	UpdateMinDelta         int               // minimum likes+comments+views change before an engagement-only Discord edit
+	UpdateMinDeltaPct      int               // same gate as a percentage of the last notified engagement; 0 disables
+	MaxNotifyPerRun        int               // cap on new-deal notifications per run; 0 is unlimited
+	MaxDealsPerRun         int               // cap on scraped deals processed per run, newest kept; 0 is unlimited
+	MinExpectedDeals       int               // MIN_EXPECTED_DEALS: alert when a list scrape yields fewer valid deals; 0 disables
+	MinExpectedDealsSkip   bool              // MIN_EXPECTED_DEALS_SKIP: also abandon the run instead of processing the short list
+	QuietHours             *QuietHours       // QUIET_HOURS: window when new non-hot deals are deferred; nil disables
+	MergeReposts           bool              // MERGE_REPOSTS: fold new threads whose title matches an active stored deal into it, even across retailers
+	RepostSimilarity       float64           // REPOST_SIMILARITY: title token overlap (0-1] MERGE_REPOSTS needs; default 0.9
+	ReheatTTL              time.Duration     // REHEAT_TTL: Discord silence after which a deal that heats up again is reposted; 0 disables
+	NotifyOrder            string            // send order for a batch of new deals: "oldest" (default) or "hottest" (hottest posted last)
+	BatchEmbeds            bool              // BATCH_EMBEDS: post a run's new deals as messages of up to 10 embeds per channel
+	DealWebhookURL         string            // DEAL_WEBHOOK_URL: also POST each saved new or updated deal as JSON here
+	ErrorTolerance         int               // per-deal failures a /process-deals run may have and still return 200
+	ErrorToleranceFraction float64           // same tolerance as a fraction of the run's deals; 0 disables
+	LogRunDiff             bool              // LOG_RUN_DIFF: log each run's new/changed/removed deals with changed fields
 	GeminiAPIKeys          []string
 	GeminiLocations        []string
 	GeminiFallbackModels   []string
@@ -30,6 +93,11 @@ type Config struct {
 	RFDAdminToken          string
 	SwordswallowerSecret   string
 
+	// WATCH_KEYWORDS: keyword -> Discord user IDs DM'd about new matching deals.
+	WatchKeywords map[string][]string
+	// PRICE_ALERT_RULES: keyword + price ceiling rules that DM their users.
+	PriceAlertRules []PriceAlertRule
+
 	// OnEveryCorner source controller configuration.
 	OnEveryCornerEnabled                    bool
 	OnEveryCornerPrimarySource              string
@@ -140,6 +208,51 @@ type Config struct {
 	HardwareSwapEnabled bool
 }
 
+// LabelRule attaches Label to RFD deals that match every condition it sets.
+// Within a condition, any listed value matches.
+type LabelRule struct {
+	Label     string   `json:"label"`
+	Keywords  []string `json:"keywords,omitempty"`  // case-insensitive substrings of the title
+	Retailers []string `json:"retailers,omitempty"` // case-insensitive retailer names
+	MaxPrice  float64  `json:"max_price,omitempty"` // dollars; deals without a parseable price never match
+}
+
+// PriceAlertRule DMs Users about new RFD deals whose title contains any of
+// Keywords at a price of at most MaxPrice, e.g. "RTX 4070" under $600.
+type PriceAlertRule struct {
+	Keywords []string `json:"keywords"`  // case-insensitive substrings of the title
+	MaxPrice float64  `json:"max_price"` // dollars; deals without a parseable price never match
+	Users    []string `json:"users"`     // Discord user IDs
+}
+
+// defaultDiscordUpdateInterval is used when DISCORD_UPDATE_INTERVAL is unset
+// or can't be parsed.
+const defaultDiscordUpdateInterval = 10 * time.Minute
+
+// defaultPostedFieldLayout renders e.g. "Jun 1, 2025 12:00 UTC".
+const defaultPostedFieldLayout = "Jan 2, 2006 15:04 MST"
+
+// QuietHours is a daily window, possibly spanning midnight, during which new
+// RFD deals are stored but their Discord posts are deferred.
+type QuietHours struct {
+	Start    time.Duration // offset from midnight in Location
+	End      time.Duration
+	Location *time.Location
+}
+
+// Contains reports whether t falls inside the window. A nil window never does.
+func (q *QuietHours) Contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	local := t.In(q.Location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if q.Start <= q.End {
+		return offset >= q.Start && offset < q.End
+	}
+	return offset >= q.Start || offset < q.End
+}
+
 func Load() (*Config, error) {
 	// Try loading from .env file. Some local .env files include multiline JSON blobs
 	// that godotenv can't parse, so fall back to a loose loader that still picks up
@@ -169,9 +282,10 @@ func Load() (*Config, error) {
 		bestBuyAffiliatePrefix = "https://bestbuyca.o93x.net/c/5215192/2035226/10221?u="
 	}
 
-	discordUpdateInterval, err := durationEnv("DISCORD_UPDATE_INTERVAL", 10*time.Minute)
+	discordUpdateInterval, err := durationEnv("DISCORD_UPDATE_INTERVAL", defaultDiscordUpdateInterval)
 	if err != nil {
-		return nil, err
+		slog.Warn("Invalid duration env value; using default", "key", "DISCORD_UPDATE_INTERVAL", "error", err, "default", defaultDiscordUpdateInterval)
+		discordUpdateInterval = defaultDiscordUpdateInterval
 	}
 
 	rfdPollInterval, err := durationEnv("RFD_POLL_INTERVAL", 3*time.Minute)
@@ -179,6 +293,26 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	rfdDetailTimeout, err := durationEnv("DETAIL_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	rfdDetailCacheTTL, err := durationEnv("RFD_DETAIL_CACHE_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	rfdFailureCooldown, err := durationEnv("RFD_FAILURE_COOLDOWN", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotInterval, err := durationEnv("SNAPSHOT_INTERVAL", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	ebayPollInterval, err := durationEnv("EBAY_POLL_INTERVAL", 30*time.Minute)
 	if err != nil {
 		return nil, err
@@ -193,6 +327,190 @@ func Load() (*Config, error) {
 		maxStoredDeals = parsed
 	}
 
+	storageBackend := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE")))
+	switch storageBackend {
+	case "":
+		storageBackend = "postgres"
+	case "postgres", "memory", "sqlite":
+	default:
+		return nil, fmt.Errorf("invalid STORAGE %q: must be postgres, memory, or sqlite", storageBackend)
+	}
+
+	statsPlacement := strings.ToLower(strings.TrimSpace(os.Getenv("STATS_PLACEMENT")))
+	switch statsPlacement {
+	case "":
+		statsPlacement = "description"
+	case "description", "title", "field", "both":
+	default:
+		return nil, fmt.Errorf("invalid STATS_PLACEMENT %q: must be description, title, field, or both", statsPlacement)
+	}
+
+	itemLinkLabel := strings.ToLower(strings.TrimSpace(os.Getenv("ITEM_LINK_LABEL")))
+	switch itemLinkLabel {
+	case "":
+		itemLinkLabel = "fixed"
+	case "fixed", "domain":
+	default:
+		return nil, fmt.Errorf("invalid ITEM_LINK_LABEL %q: must be fixed or domain", itemLinkLabel)
+	}
+
+	var postedFieldLayout string
+	postedFieldLocation := time.UTC
+	if boolEnv("POSTED_FIELD", false) {
+		postedFieldLayout = firstNonEmpty(os.Getenv("POSTED_FIELD_LAYOUT"), defaultPostedFieldLayout)
+		if timezone := strings.TrimSpace(os.Getenv("POSTED_FIELD_TIMEZONE")); timezone != "" {
+			if postedFieldLocation, err = time.LoadLocation(timezone); err != nil {
+				return nil, fmt.Errorf("invalid POSTED_FIELD_TIMEZONE %q: %w", timezone, err)
+			}
+		}
+	}
+
+	rfdSort := strings.ToLower(strings.TrimSpace(os.Getenv("RFD_SORT")))
+	switch rfdSort {
+	case "":
+		rfdSort = "newest"
+	case "newest", "replies", "views":
+	default:
+		return nil, fmt.Errorf("invalid RFD_SORT %q: must be newest, replies, or views", rfdSort)
+	}
+
+	repostSimilarity := 0.9
+	if raw := strings.TrimSpace(os.Getenv("REPOST_SIMILARITY")); raw != "" {
+		repostSimilarity, err = strconv.ParseFloat(raw, 64)
+		if err != nil || repostSimilarity <= 0 || repostSimilarity > 1 {
+			return nil, fmt.Errorf("invalid REPOST_SIMILARITY %q: must be a number in (0, 1]", raw)
+		}
+	}
+
+	reheatTTL, err := durationEnv("REHEAT_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	detailFetch := strings.ToLower(strings.TrimSpace(os.Getenv("DETAIL_FETCH")))
+	switch detailFetch {
+	case "":
+		detailFetch = "all"
+	case "all", "new-only", "top-n":
+	default:
+		return nil, fmt.Errorf("invalid DETAIL_FETCH %q: must be all, new-only, or top-n", detailFetch)
+	}
+
+	watchKeywords := make(map[string][]string)
+	for keyword, raw := range mapEnv("WATCH_KEYWORDS") {
+		for _, userID := range strings.Split(raw, "|") {
+			userID = strings.TrimSpace(userID)
+			if userID == "" {
+				continue
+			}
+			if strings.Trim(userID, "0123456789") != "" {
+				return nil, fmt.Errorf("invalid WATCH_KEYWORDS user ID %q for %q: must be a numeric Discord user ID", userID, keyword)
+			}
+			watchKeywords[keyword] = append(watchKeywords[keyword], userID)
+		}
+	}
+
+	rfdExtraForums := csvEnv("RFD_EXTRA_FORUMS", nil)
+	for i, forum := range rfdExtraForums {
+		forum = strings.Trim(forum, "/")
+		if forum == "" || strings.ContainsAny(forum, "/?#") {
+			return nil, fmt.Errorf("invalid RFD_EXTRA_FORUMS entry %q: must be a forum slug like computers-electronics-f214", rfdExtraForums[i])
+		}
+		rfdExtraForums[i] = forum
+	}
+
+	commentCountSources := csvEnv("COMMENT_COUNT_SOURCES", []string{"list"})
+	for i, source := range commentCountSources {
+		source = strings.ToLower(source)
+		switch source {
+		case "list", "jsonld", "detail":
+		default:
+			return nil, fmt.Errorf("invalid COMMENT_COUNT_SOURCES entry %q: must be list, jsonld, or detail", source)
+		}
+		commentCountSources[i] = source
+	}
+
+	notifyOrder := strings.ToLower(strings.TrimSpace(os.Getenv("NOTIFY_ORDER")))
+	switch notifyOrder {
+	case "":
+		notifyOrder = "oldest"
+	case "oldest", "hottest":
+	default:
+		return nil, fmt.Errorf("invalid NOTIFY_ORDER %q: must be oldest or hottest", notifyOrder)
+	}
+
+	expiredColor, err := parseEmbedColor("EMBED_COLOR_EXPIRED")
+	if err != nil {
+		return nil, err
+	}
+	priceErrorColor, err := parseEmbedColor("EMBED_COLOR_PRICE_ERROR")
+	if err != nil {
+		return nil, err
+	}
+
+	freshBoostWindow, err := durationEnv("FRESH_BOOST_WINDOW", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	engagementEmoji, err := parseEngagementEmoji(mapEnv("ENGAGEMENT_EMOJI"))
+	if err != nil {
+		return nil, err
+	}
+
+	updateMinDelta, updateMinDeltaPct, err := parseUpdateMinDelta(os.Getenv("UPDATE_MIN_DELTA"))
+	if err != nil {
+		return nil, err
+	}
+
+	errorTolerance, errorToleranceFraction, err := parseErrorTolerance(os.Getenv("ERROR_TOLERANCE"))
+	if err != nil {
+		return nil, err
+	}
+
+	titleStripPatterns := csvEnv("RFD_TITLE_STRIP_PATTERNS", nil)
+	for _, pattern := range titleStripPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid RFD_TITLE_STRIP_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+
+	priceErrorPatterns := csvEnv("PRICE_ERROR_PATTERNS", nil)
+	for _, pattern := range priceErrorPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid PRICE_ERROR_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+
+	ymmvPatterns := csvEnv("YMMV_PATTERNS", nil)
+	for _, pattern := range ymmvPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid YMMV_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+
+	regionPatterns := csvEnv("REGION_PATTERNS", nil)
+	for _, pattern := range regionPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid REGION_PATTERNS entry %q: %w", pattern, err)
+		}
+	}
+
+	quietHours, err := parseQuietHours(os.Getenv("QUIET_HOURS"), os.Getenv("QUIET_HOURS_TIMEZONE"))
+	if err != nil {
+		return nil, err
+	}
+
+	priceAlertRules, err := parsePriceAlertRules(os.Getenv("PRICE_ALERT_RULES"))
+	if err != nil {
+		return nil, err
+	}
+
+	labelRules, err := parseLabelRules(os.Getenv("DEAL_LABEL_RULES"))
+	if err != nil {
+		return nil, err
+	}
+
 	discordPublicKey := os.Getenv("DISCORD_PUBLIC_KEY")
 	discordBotToken := os.Getenv("DISCORD_BOT_TOKEN")
 	if discordBotToken == "" {
@@ -331,14 +649,74 @@ func Load() (*Config, error) {
 	return &Config{
 		ProjectID:              projectID,
 		DatabaseURL:            os.Getenv("DATABASE_URL"),
+		Storage:                storageBackend,
+		SQLitePath:             firstNonEmpty(os.Getenv("SQLITE_PATH"), "rfd-deals.db"),
 		Port:                   port,
 		AmazonAffiliateTag:     amazonAffiliateTag,
+		AmazonAffiliateTags:    mapEnv("AMAZON_AFFILIATE_TAGS"),
+		NoAffiliateDomains:     csvEnv("NO_AFFILIATE_DOMAINS", nil),
 		BestBuyAffiliatePrefix: bestBuyAffiliatePrefix,
 		DiscordUpdateInterval:  discordUpdateInterval,
 		RFDPollInterval:        rfdPollInterval,
+		RFDDetailTimeout:       rfdDetailTimeout,
+		RFDDetailCacheSize:     intEnv("RFD_DETAIL_CACHE_SIZE", 0),
+		DetailFetch:            detailFetch,
+		DetailFetchTopN:        max(intEnv("DETAIL_FETCH_TOP_N", 10), 0),
+		CommentCountSources:    commentCountSources,
+		MaxDescriptionLen:      max(intEnv("MAX_DESCRIPTION_LEN", 0), 0),
+		MaxCommentsLen:         max(intEnv("MAX_COMMENTS_LEN", 2000), 0),
+		RFDDetailCacheTTL:      rfdDetailCacheTTL,
+		RFDFailureAlertAfter:   intEnv("RFD_FAILURE_ALERT_AFTER", 3),
+		RFDFailureCooldown:     rfdFailureCooldown,
+		HostRateLimit:          intEnv("HOST_RATE_LIMIT", 0),
 		MaxStoredDeals:         maxStoredDeals,
+		SnapshotInterval:       snapshotInterval,
+		StorageDeleteBatchSize: max(intEnv("STORAGE_DELETE_BATCH_SIZE", 500), 0),
 		AllowedDomains:         []string{"redflagdeals.com", "forums.redflagdeals.com", "www.redflagdeals.com", "bestbuy.ca"},
 		RFDBaseURL:             "https://forums.redflagdeals.com",
+		CanonicalHosts:         mapEnv("CANONICAL_HOSTS"),
+		RFDSort:                rfdSort,
+		RFDMaxPages:            max(intEnv("RFD_MAX_PAGES", 1), 1),
+		RFDIncludeExpired:      boolEnv("RFD_INCLUDE_EXPIRED", false),
+		RFDExtraForums:         rfdExtraForums,
+		ExpiredDealsChannel:    strings.TrimSpace(os.Getenv("EXPIRED_DEALS_CHANNEL")),
+		AlwaysNotifyKeywords:   csvEnv("RFD_ALWAYS_NOTIFY_KEYWORDS", nil),
+		BlockAuthors:           csvEnv("BLOCK_AUTHORS", nil),
+		WatchKeywords:          watchKeywords,
+		PriceAlertRules:        priceAlertRules,
+		CategoryChannels:       mapEnv("CATEGORY_CHANNELS"),
+		LabelRules:             labelRules,
+		TitleStripPatterns:     titleStripPatterns,
+		PriceErrorPatterns:     priceErrorPatterns,
+		YMMVPatterns:           ymmvPatterns,
+		RegionPatterns:         regionPatterns,
+		StatsPlacement:         statsPlacement,
+		CompactEmbeds:          boolEnv("COMPACT_EMBEDS", false),
+		ItemLinkLabel:          itemLinkLabel,
+		ItemLinkText:           strings.TrimSpace(os.Getenv("ITEM_LINK_TEXT")),
+		PostedFieldLayout:      postedFieldLayout,
+		PostedFieldLocation:    postedFieldLocation,
+		ExpiredColor:           expiredColor,
+		PriceErrorColor:        priceErrorColor,
+		FreshBoostWindow:       freshBoostWindow,
+		EngagementEmoji:        engagementEmoji,
+		SuppressDealUpdates:    !boolEnv("NOTIFY_UPDATES", true),
+		LogRunDiff:             boolEnv("LOG_RUN_DIFF", false),
+		UpdateMinDelta:         updateMinDelta,
+		UpdateMinDeltaPct:      updateMinDeltaPct,
+		MaxNotifyPerRun:        intEnv("MAX_NOTIFY_PER_RUN", 0),
+		MaxDealsPerRun:         intEnv("MAX_DEALS_PER_RUN", 0),
+		MinExpectedDeals:       intEnv("MIN_EXPECTED_DEALS", 0),
+		MinExpectedDealsSkip:   boolEnv("MIN_EXPECTED_DEALS_SKIP", true),
+		QuietHours:             quietHours,
+		MergeReposts:           boolEnv("MERGE_REPOSTS", false),
+		RepostSimilarity:       repostSimilarity,
+		ReheatTTL:              reheatTTL,
+		NotifyOrder:            notifyOrder,
+		BatchEmbeds:            boolEnv("BATCH_EMBEDS", false),
+		DealWebhookURL:         os.Getenv("DEAL_WEBHOOK_URL"),
+		ErrorTolerance:         errorTolerance,
+		ErrorToleranceFraction: errorToleranceFraction,
 		GeminiAPIKeys:          geminiAPIKeys,
 		GeminiLocations:        geminiLocations,
 		GeminiFallbackModels: []string{
@@ -436,6 +814,228 @@ func Load() (*Config, error) {
 	}, nil
 }
 
+// Redacted returns a copy of the config that is safe to log: tokens, secrets
+// and API keys are masked and credentials are stripped from URLs.
+func (c *Config) Redacted() Config {
+	r := *c
+	r.DatabaseURL = redactURL(c.DatabaseURL)
+	r.ProxyURL = redactURL(c.ProxyURL)
+	r.DealWebhookURL = maskSecret(c.DealWebhookURL)
+	r.RFDAdminToken = maskSecret(c.RFDAdminToken)
+	r.SwordswallowerSecret = maskSecret(c.SwordswallowerSecret)
+	r.OnEveryCornerTotalCornerAPIToken = maskSecret(c.OnEveryCornerTotalCornerAPIToken)
+	r.DiscordBotToken = maskSecret(c.DiscordBotToken)
+	r.EbayClientSecret = maskSecret(c.EbayClientSecret)
+	r.CarfaxTokenServiceSecret = maskSecret(c.CarfaxTokenServiceSecret)
+	r.RedditServiceSecret = maskSecret(c.RedditServiceSecret)
+	r.XAPIKey = maskSecret(c.XAPIKey)
+	r.XAPIKeySecret = maskSecret(c.XAPIKeySecret)
+	r.XAccessToken = maskSecret(c.XAccessToken)
+	r.XAccessTokenSecret = maskSecret(c.XAccessTokenSecret)
+	r.X2APIKey = maskSecret(c.X2APIKey)
+	r.X2APIKeySecret = maskSecret(c.X2APIKeySecret)
+	r.X2AccessToken = maskSecret(c.X2AccessToken)
+	r.X2AccessTokenSecret = maskSecret(c.X2AccessTokenSecret)
+	if c.GeminiAPIKeys != nil {
+		r.GeminiAPIKeys = make([]string, len(c.GeminiAPIKeys))
+		for i, key := range c.GeminiAPIKeys {
+			r.GeminiAPIKeys[i] = maskSecret(key)
+		}
+	}
+	return r
+}
+
+// maskSecret keeps the last four characters of long secrets so operators can
+// tell which key is loaded; empty values stay empty to show they are unset.
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return "***"
+	}
+	return "***" + s[len(s)-4:]
+}
+
+// redactURL masks the password in a URL. Values that aren't URLs (e.g. a
+// key=value Postgres DSN) are masked entirely since they may embed one.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return "***"
+	}
+	return u.Redacted()
+}
+
+// parseLabelRules reads DEAL_LABEL_RULES, a JSON array of LabelRule.
+func parseLabelRules(raw string) ([]LabelRule, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var rules []LabelRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid DEAL_LABEL_RULES: %w", err)
+	}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Label) == "" {
+			return nil, fmt.Errorf("invalid DEAL_LABEL_RULES: rule %d has no label", i)
+		}
+		if len(rule.Keywords) == 0 && len(rule.Retailers) == 0 && rule.MaxPrice <= 0 {
+			return nil, fmt.Errorf("invalid DEAL_LABEL_RULES: rule %q has no conditions", rule.Label)
+		}
+	}
+	return rules, nil
+}
+
+// parsePriceAlertRules reads PRICE_ALERT_RULES, a JSON array of
+// PriceAlertRule.
+func parsePriceAlertRules(raw string) ([]PriceAlertRule, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var rules []PriceAlertRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid PRICE_ALERT_RULES: %w", err)
+	}
+	for i, rule := range rules {
+		if len(rule.Keywords) == 0 || rule.MaxPrice <= 0 {
+			return nil, fmt.Errorf("invalid PRICE_ALERT_RULES: rule %d needs keywords and a positive max_price", i)
+		}
+		if len(rule.Users) == 0 {
+			return nil, fmt.Errorf("invalid PRICE_ALERT_RULES: rule %d has no users", i)
+		}
+		for _, userID := range rule.Users {
+			if userID == "" || strings.Trim(userID, "0123456789") != "" {
+				return nil, fmt.Errorf("invalid PRICE_ALERT_RULES: rule %d user ID %q must be a numeric Discord user ID", i, userID)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// parseQuietHours reads QUIET_HOURS as "HH:MM-HH:MM" in the given timezone
+// (UTC when empty). An empty window disables quiet hours.
+func parseQuietHours(raw, timezone string) (*QuietHours, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	startRaw, endRaw, ok := strings.Cut(raw, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: must look like 23:00-07:00", raw)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startRaw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: %w", raw, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endRaw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: %w", raw, err)
+	}
+	if start.Equal(end) {
+		return nil, fmt.Errorf("invalid QUIET_HOURS %q: start and end must differ", raw)
+	}
+	location, err := time.LoadLocation(firstNonEmpty(strings.TrimSpace(timezone), "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUIET_HOURS_TIMEZONE %q: %w", timezone, err)
+	}
+	sinceMidnight := func(t time.Time) time.Duration {
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return &QuietHours{Start: sinceMidnight(start), End: sinceMidnight(end), Location: location}, nil
+}
+
+// parseUpdateMinDelta reads UPDATE_MIN_DELTA as either an absolute engagement
+// change ("10") or a percentage of the last notified engagement ("5%").
+func parseUpdateMinDelta(raw string) (absolute, percent int, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, 0, nil
+	}
+	value, isPct := strings.CutSuffix(raw, "%")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("invalid UPDATE_MIN_DELTA %q: must be a non-negative integer or percentage", raw)
+	}
+	if isPct {
+		return 0, n, nil
+	}
+	return n, 0, nil
+}
+
+// parseEmbedColor reads an embed color env var as hex ("#9B59B6" or
+// "0x9B59B6") or decimal; unset returns 0.
+func parseEmbedColor(key string) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return 0, nil
+	}
+	value, base := raw, 10
+	if hex, ok := strings.CutPrefix(raw, "#"); ok {
+		value, base = hex, 16
+	} else if hex, ok := strings.CutPrefix(strings.ToLower(raw), "0x"); ok {
+		value, base = hex, 16
+	}
+	n, err := strconv.ParseInt(value, base, 32)
+	if err != nil || n < 0 || n > 0xFFFFFF {
+		return 0, fmt.Errorf("invalid %s %q: must be a hex (#RRGGBB) or decimal color", key, raw)
+	}
+	return int(n), nil
+}
+
+// customEmojiPattern matches a Discord server emoji, e.g. "<:rfdup:1234567890123456789>"
+// or the animated "<a:name:id>" form.
+var customEmojiPattern = regexp.MustCompile(`^<a?:[A-Za-z0-9_]{2,32}:[0-9]{17,20}>$`)
+
+// parseEngagementEmoji validates ENGAGEMENT_EMOJI overrides. Each value must be
+// a Discord custom emoji or a short Unicode emoji; anything containing ASCII
+// (markdown, whitespace, links) could break the embed and is rejected.
+func parseEngagementEmoji(entries map[string]string) (map[string]string, error) {
+	for key, emoji := range entries {
+		switch key {
+		case "like", "dislike", "comment", "view":
+		default:
+			return nil, fmt.Errorf("invalid ENGAGEMENT_EMOJI key %q: must be like, dislike, comment, or view", key)
+		}
+		if customEmojiPattern.MatchString(emoji) {
+			continue
+		}
+		valid := utf8.RuneCountInString(emoji) <= 16
+		for _, r := range emoji {
+			if r < utf8.RuneSelf || !unicode.IsGraphic(r) && r != '\u200d' {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid ENGAGEMENT_EMOJI %s %q: must be a Unicode emoji or <:name:id>", key, emoji)
+		}
+	}
+	return entries, nil
+}
+
+// parseErrorTolerance reads ERROR_TOLERANCE as either an absolute failure
+// count ("3") or a fraction of the run's deals ("0.1").
+func parseErrorTolerance(raw string) (absolute int, fraction float64, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, 0, nil
+	}
+	if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+		return n, 0, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f <= 0 || f >= 1 {
+		return 0, 0, fmt.Errorf("invalid ERROR_TOLERANCE %q: must be a non-negative integer or a fraction between 0 and 1", raw)
+	}
+	return 0, f, nil
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if value != "" {
@@ -464,6 +1064,32 @@ func csvEnv(key string, fallback []string) []string {
 	return out
 }
 
+// mapEnv parses a comma-separated list of key=value pairs, e.g.
+// "amazon.ca=tag-20,amazon.com=tag2-20". Keys are lower-cased; malformed
+// entries are logged and skipped.
+func mapEnv(key string) map[string]string {
+	raw := strings.TrimSpace(os.Getenv(key))
+	if raw == "" {
+		return nil
+	}
+	out := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(entry, "=")
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			slog.Warn("Invalid key=value env entry; skipping", "key", key, "entry", entry)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
 func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
 	raw := strings.TrimSpace(os.Getenv(key))
 	if raw == "" {
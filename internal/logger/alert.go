@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Alerter raises operationally significant events, such as RFD blocking the
+// scraper, separately from routine logging so they can be routed to a pager
+// or webhook without parsing logs. args are slog-style key/value pairs.
+type Alerter interface {
+	Alert(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// LogAlerter is the default Alerter: it logs each alert at its level with
+// alert=true.
+type LogAlerter struct{}
+
+func (LogAlerter) Alert(ctx context.Context, level slog.Level, msg string, args ...any) {
+	slog.Default().Log(ctx, level, msg, append([]any{"alert", true}, args...)...)
+}
+
+// MultiAlerter sends each alert to every Alerter in order.
+type MultiAlerter []Alerter
+
+func (m MultiAlerter) Alert(ctx context.Context, level slog.Level, msg string, args ...any) {
+	for _, alerter := range m {
+		if alerter != nil {
+			alerter.Alert(ctx, level, msg, args...)
+		}
+	}
+}
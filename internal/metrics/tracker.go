@@ -26,6 +26,7 @@ type Tracker struct {
 	adsScraped   atomic.Int64
 	adsProcessed atomic.Int64
 	dealsFound   atomic.Int64
+	dealsBlocked atomic.Int64
 }
 
 // NewTracker creates a new API usage tracker for a specific processor.
@@ -69,6 +70,11 @@ func (t *Tracker) TrackDealFound() {
 	t.dealsFound.Add(1)
 }
 
+// TrackDealBlocked records a deal withheld from Discord because its author is blocked.
+func (t *Tracker) TrackDealBlocked() {
+	t.dealsBlocked.Add(1)
+}
+
 // LogSummary emits an INFO-level log with all accumulated metrics.
 // Call this at the end of each processor run.
 func (t *Tracker) LogSummary() {
@@ -83,5 +89,6 @@ func (t *Tracker) LogSummary() {
 		"ads_scraped", t.adsScraped.Load(),
 		"ads_processed", t.adsProcessed.Load(),
 		"deals_found", t.dealsFound.Load(),
+		"deals_blocked", t.dealsBlocked.Load(),
 	)
 }
@@ -15,6 +15,7 @@ func TestTracker_BasicOperations(t *testing.T) {
 	tracker.TrackAdProcessed()
 	tracker.TrackAdProcessed()
 	tracker.TrackDealFound()
+	tracker.TrackDealBlocked()
 
 	if tracker.geminiCalls.Load() != 2 {
 		t.Errorf("expected 2 gemini calls, got %d", tracker.geminiCalls.Load())
@@ -43,6 +44,9 @@ func TestTracker_BasicOperations(t *testing.T) {
 	if tracker.dealsFound.Load() != 1 {
 		t.Errorf("expected 1 deal found, got %d", tracker.dealsFound.Load())
 	}
+	if tracker.dealsBlocked.Load() != 1 {
+		t.Errorf("expected 1 deal blocked, got %d", tracker.dealsBlocked.Load())
+	}
 
 	// Just verify LogSummary doesn't panic
 	tracker.LogSummary()
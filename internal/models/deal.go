@@ -1,7 +1,10 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -10,19 +13,53 @@ var ErrDealExists = errors.New("deal already exists")
 
 const dealRetention = 30 * 24 * time.Hour
 
+// MaxDealHistory bounds DealInfo.History; the oldest snapshots are dropped.
+const MaxDealHistory = 48
+
+// DealID returns the deterministic document ID for a deal published at the
+// given time. It survives title and URL edits by the post author.
+func DealID(published time.Time) string {
+	hash := sha256.Sum256([]byte(published.Format(time.RFC3339Nano)))
+	return hex.EncodeToString(hash[:])
+}
+
+// CollisionDealID returns the ID for a distinct deal published at the same
+// instant as the deal that owns baseID. threadKey identifies the deal's RFD
+// thread, so title edits keep the ID.
+func CollisionDealID(baseID, threadKey string) string {
+	hash := sha256.Sum256([]byte(threadKey))
+	return baseID + "-" + hex.EncodeToString(hash[:4])
+}
+
+// IsDealIDFor reports whether id is the ID for a deal published at the given
+// time: DealID(published) or one of its CollisionDealIDs.
+func IsDealIDFor(id string, published time.Time) bool {
+	base := DealID(published)
+	return id == base || strings.HasPrefix(id, base+"-")
+}
+
 // DealInfo represents the structured information for a deal.
 type DealInfo struct {
 	Title                  string            `docstore:"title" validate:"required"`
-	PostURL                string            `docstore:"postURL" validate:"required,url"`
+	PostURL                string            `docstore:"postURL" validate:"required,url,rfd_url"`
 	Category               string            `docstore:"category,omitempty"`
 	ThreadImageURL         string            `docstore:"threadImageURL,omitempty" validate:"omitempty,url"`
-	ActualDealURL          string            `docstore:"actualDealURL,omitempty" validate:"omitempty,url"`
+	ActualDealURL          string            `docstore:"actualDealURL,omitempty" validate:"omitempty,url,external_url"`
 	DocumentID             string            `docstore:"-"`                           // Document ID; not stored in the document itself.
 	DiscordMessageIDs      map[string]string `docstore:"discordMessageIDs,omitempty"` // Mapping of ChannelID -> MessageID
+	DiscordEmbedIndex      map[string]int    `docstore:"discordEmbedIndex,omitempty"` // ChannelID -> the deal's embed position in a shared BATCH_EMBEDS message
 	LastUpdated            time.Time         `docstore:"lastUpdated"`
 	PublishedTimestamp     time.Time         `docstore:"publishedTimestamp" validate:"required"` // Parsed from PostedTime
 	DiscordLastUpdatedTime time.Time         `docstore:"discordLastUpdatedTime,omitempty"`
+	DiscordEngagement      int               `docstore:"discordEngagement,omitempty"` // likes+comments+views at the last Discord send/edit
 	ExpiresAt              time.Time         `docstore:"expiresAt,omitempty"`
+	FirstSeen              time.Time         `docstore:"firstSeen,omitempty"`      // when the deal was first stored; creation-only, updates never change it
+	NotifyCapped           bool              `docstore:"notifyCapped,omitempty"`   // stored past MAX_NOTIFY_PER_RUN; never posted
+	SnoozeUntil            time.Time         `docstore:"snoozeUntil,omitempty"`    // Discord edits are skipped until then; data still persists
+	Expired                bool              `docstore:"expired,omitempty"`        // scraped from RFD's Expired Hot Deals forum; archived, never pinged as hot
+	Forum                  string            `docstore:"forum,omitempty"`          // RFD forum slug the deal was scraped from, e.g. "hot-deals-f9"
+	ReheatedAt             time.Time         `docstore:"reheatedAt,omitempty"`     // last REHEAT_TTL "back on the hot list" repost
+	NotifyDeferred         bool              `docstore:"notifyDeferred,omitempty"` // held back during QUIET_HOURS; posted on the first run after
 
 	Threads      []ThreadContext `docstore:"threads"`
 	SearchTokens []string        `docstore:"searchTokens,omitempty"`
@@ -31,6 +68,8 @@ type DealInfo struct {
 	OriginalPrice string `docstore:"originalPrice,omitempty"`
 	Savings       string `docstore:"savings,omitempty"`
 	Retailer      string `docstore:"retailer,omitempty"`
+	AuthorName    string `docstore:"authorName,omitempty"`
+	DiscountPct   int    `docstore:"discountPct,omitempty"` // Derived from Price/OriginalPrice; 0 when unknown
 
 	// AI Enriched Fields
 	CleanTitle  string `docstore:"cleanTitle,omitempty"`
@@ -39,11 +78,61 @@ type DealInfo struct {
 	// Rank Tracking — sticky flags set by engagement heat score
 	HasBeenWarm bool `docstore:"hasBeenWarm,omitempty"`
 	HasBeenHot  bool `docstore:"hasBeenHot,omitempty"`
+	PriceError  bool `docstore:"priceError,omitempty"` // title/comments suggest a pricing mistake; implies hot
+
+	// Availability hints from the title/description, shown as an embed note
+	YMMV   bool   `docstore:"ymmv,omitempty"`   // availability varies by store or account
+	Region string `docstore:"region,omitempty"` // province hint, e.g. "ON"; empty when Canada-wide
+
+	// Labels from DEAL_LABEL_RULES, rendered as tags on the embed
+	Labels []string `docstore:"labels,omitempty"`
 
 	// Detailed Content
 	Description string `docstore:"description,omitempty"`
 	Comments    string `docstore:"comments,omitempty"` // Flattened comments for AI context
 	Summary     string `docstore:"summary,omitempty"`  // RFD editor summary if available
+
+	// History holds bounded price/engagement snapshots, oldest first, taken
+	// when a run saw them change, at most once per SNAPSHOT_INTERVAL; see
+	// RecordSnapshot.
+	History []DealSnapshot `docstore:"history,omitempty"`
+
+	// ParseWarnings lists "field: problem" notes from scraping the list card,
+	// for diagnostics only; never persisted.
+	ParseWarnings []string `docstore:"-"`
+}
+
+// DealSnapshot is one point on a deal's timeline.
+type DealSnapshot struct {
+	At       time.Time `docstore:"at" json:"at"`
+	Price    string    `docstore:"price,omitempty" json:"price,omitempty"`
+	Likes    int       `docstore:"likes" json:"likes"`
+	Comments int       `docstore:"comments" json:"comments"`
+	Views    int       `docstore:"views" json:"views"`
+}
+
+// RecordSnapshot appends the deal's current price and primary-thread stats to
+// History unless they match the last snapshot or that snapshot is less than
+// minInterval old, keeping at most MaxDealHistory. It reports whether a
+// snapshot was added.
+func (d *DealInfo) RecordSnapshot(at time.Time, minInterval time.Duration) bool {
+	likes, comments, views := d.Stats()
+	snapshot := DealSnapshot{At: at, Price: d.Price, Likes: likes, Comments: comments, Views: views}
+	if n := len(d.History); n > 0 {
+		last := d.History[n-1]
+		if at.Sub(last.At) < minInterval {
+			return false
+		}
+		last.At = at
+		if last == snapshot {
+			return false
+		}
+	}
+	d.History = append(d.History, snapshot)
+	if extra := len(d.History) - MaxDealHistory; extra > 0 {
+		d.History = append([]DealSnapshot(nil), d.History[extra:]...)
+	}
+	return true
 }
 
 // DealDetailFetchStats summarizes RFD detail-page fetch health for a run.
@@ -58,7 +147,7 @@ type DealDetailFetchStats struct {
 // ThreadContext represents an individual RedFlagDeals thread that is part of a DealIdea.
 type ThreadContext struct {
 	DocumentID         string `docstore:"documentID"`
-	PostURL            string `docstore:"postURL" validate:"required,url"`
+	PostURL            string `docstore:"postURL" validate:"required,url,rfd_url"`
 	LikeCount          int    `docstore:"likeCount"`
 	CommentCount       int    `docstore:"commentCount" validate:"gte=0"`
 	ViewCount          int    `docstore:"viewCount" validate:"gte=0"`
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RunSummary records one RFD processing run in the runs collection, so bot
+// health and RFD activity can be charted over time.
+type RunSummary struct {
+	StartedAt  time.Time `docstore:"startedAt" json:"startedAt"`
+	DurationMs int64     `docstore:"durationMs" json:"durationMs"`
+	Status     string    `docstore:"status" json:"status"` // as reported by /process-deals: ok, warning, partial, error, cancelled or timeout
+	ScrapeOK   bool      `docstore:"scrapeOK" json:"scrapeOK"`
+	Scraped    int       `docstore:"scraped" json:"scraped"` // valid deals on the list
+	New        int       `docstore:"new" json:"new"`
+	Updated    int       `docstore:"updated" json:"updated"`
+	Skipped    int       `docstore:"skipped" json:"skipped"`
+	Failed     int       `docstore:"failed" json:"failed"`
+}
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"crypto/hmac"
 	"crypto/sha1"
@@ -11,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"math/rand"
 	"mime/multipart"
 	"net/http"
@@ -37,6 +39,14 @@ const (
 	colorWarmDeal = 16098851 // #F5A623 (amber)            — getting traction
 	colorHotDeal  = 16723320 // #FF2D78 (magenta-pink)     — blowing up, act fast
 
+	// freshBoostMax caps how far a brand-new cold deal is tinted toward
+	// colorWarmDeal, so fresh posts never look like ones with real traction.
+	freshBoostMax = 0.8
+
+	// State colors win over heat; SetStateColors overrides them.
+	colorExpiredDeal    = 9807270  // #95A5A6 (grey)   — thread moved to Expired Offers
+	colorPriceErrorDeal = 10181046 // #9B59B6 (purple) — likely pricing mistake
+
 	heatScoreThresholdWarm = 0.05
 	heatScoreThresholdHot  = 0.20
 
@@ -60,8 +70,40 @@ type Client struct {
 	xAccounts      []xAccount
 	xPostIssueMu   sync.Mutex
 	xPostIssueLast map[string]time.Time
+
+	statsPlacement string
+	stateColors    embedStateColors
+	emoji          engagementEmoji
+	compactEmbeds  bool
+	itemLink       itemLinkLabel
+	postedField    postedTimeField
+	messageFlags   map[string]int // processor -> Discord message flags
+	clock          util.Clock     // nil reads the wall clock
+
+	dmMu       sync.Mutex
+	dmChannels map[string]string // user ID -> DM channel ID
 }
 
+// Discord message flags that can be set per notification type.
+const (
+	MessageFlagSuppressEmbeds        = 1 << 2  // post links without their preview embeds
+	MessageFlagSuppressNotifications = 1 << 12 // deliver silently, without push/desktop alerts
+)
+
+// Where RFD deal embeds render likes/comments/views.
+const (
+	StatsInDescription = "description" // last line of the description (default)
+	StatsInTitle       = "title"       // compact suffix on the embed title
+	StatsInField       = "field"       // dedicated "Engagement" embed field
+	StatsInBoth        = "both"        // title suffix and field
+)
+
+// How compact embeds label the link to the retailer's item page.
+const (
+	ItemLinkFixed  = "fixed"  // the configured text, "Item" by default
+	ItemLinkDomain = "domain" // the item's host, e.g. "amazon.ca"
+)
+
 type xAccount struct {
 	apiKey, apiKeySecret, accessToken, accessTokenSecret string
 }
@@ -92,6 +134,113 @@ func New(botToken string, xCreds ...string) *Client {
 	return c
 }
 
+// SetStatsPlacement chooses where deal embeds show engagement stats. Empty
+// input keeps the default description placement.
+func (c *Client) SetStatsPlacement(placement string) {
+	if c == nil {
+		return
+	}
+	c.statsPlacement = placement
+}
+
+// SetStateColors overrides the embed colors for expired and price-error
+// deals. Zero keeps the built-in grey and purple.
+func (c *Client) SetStateColors(expired, priceError int) {
+	if c == nil {
+		return
+	}
+	c.stateColors.expired = expired
+	c.stateColors.priceError = priceError
+}
+
+// SetFreshBoostWindow tints cold deals posted less than window ago toward the
+// warm color (FRESH_BOOST_WINDOW). Zero disables it.
+func (c *Client) SetFreshBoostWindow(window time.Duration) {
+	if c == nil {
+		return
+	}
+	c.stateColors.freshWindow = max(window, 0)
+}
+
+// embedColors returns the state colors stamped with the current time for
+// the freshness boost.
+func (c *Client) embedColors() embedStateColors {
+	colors := c.stateColors
+	colors.now = c.now()
+	return colors
+}
+
+// SetEngagementEmoji overrides the engagement stat emoji, keyed "like",
+// "dislike", "comment" and "view" (config.EngagementEmoji). Missing keys keep
+// the defaults.
+func (c *Client) SetEngagementEmoji(emoji map[string]string) {
+	if c == nil {
+		return
+	}
+	c.emoji = engagementEmoji{like: emoji["like"], dislike: emoji["dislike"], comment: emoji["comment"], view: emoji["view"]}
+}
+
+// SetCompactEmbeds switches RFD deals to the single-line embed without a
+// thumbnail (COMPACT_EMBEDS).
+func (c *Client) SetCompactEmbeds(compact bool) {
+	if c == nil {
+		return
+	}
+	c.compactEmbeds = compact
+}
+
+// SetItemLinkLabel chooses how compact embeds label the item link
+// (ITEM_LINK_LABEL): ItemLinkDomain shows the item's host, anything else the
+// fixed text. Empty text keeps "Item".
+func (c *Client) SetItemLinkLabel(mode, text string) {
+	if c == nil {
+		return
+	}
+	c.itemLink = itemLinkLabel{domain: mode == ItemLinkDomain, text: strings.TrimSpace(text)}
+}
+
+// SetPostedField adds a "Posted" field showing the RFD post time in layout
+// and location (POSTED_FIELD). An empty layout removes it; a nil location
+// uses UTC.
+func (c *Client) SetPostedField(layout string, location *time.Location) {
+	if c == nil {
+		return
+	}
+	c.postedField = postedTimeField{layout: layout, location: location}
+}
+
+// SetClock replaces the client's time source; tests use util.FakeClock.
+func (c *Client) SetClock(clock util.Clock) {
+	if c == nil {
+		return
+	}
+	c.clock = clock
+}
+
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// SetMessageFlags sets the Discord message flags sent with every message of a
+// notification type, keyed by processor name (e.g. "rfd", "ebay"). Zero clears
+// them.
+func (c *Client) SetMessageFlags(processor string, flags int) {
+	if c == nil {
+		return
+	}
+	if c.messageFlags == nil {
+		c.messageFlags = make(map[string]int)
+	}
+	if flags == 0 {
+		delete(c.messageFlags, processor)
+		return
+	}
+	c.messageFlags[processor] = flags
+}
+
 // Send sends a new deal notification to all subscribed channels.
 // Returns a map of ChannelID -> MessageID.
 func (c *Client) Send(ctx context.Context, deal models.DealInfo, subs []models.Subscription) (map[string]string, error) {
@@ -99,7 +248,8 @@ func (c *Client) Send(ctx context.Context, deal models.DealInfo, subs []models.S
 		return nil, nil // No bot token configured
 	}
 
-	payload := createDiscordPayload(deal)
+	payload := c.createDiscordPayload(deal)
+	payload.Flags = c.messageFlags["rfd"]
 	results := make(map[string]string)
 
 	for _, sub := range subs {
@@ -121,18 +271,120 @@ func (c *Client) Send(ctx context.Context, deal models.DealInfo, subs []models.S
 	return results, nil
 }
 
+// maxEmbedsPerMessage is Discord's limit on embeds in one message.
+const maxEmbedsPerMessage = 10
+
+// SendBatch posts deals to one channel packed up to maxEmbedsPerMessage
+// embeds per message (BATCH_EMBEDS) and returns each deal's message ID in
+// order. Deals sharing a message share its ID; a deal's embed position is its
+// index modulo maxEmbedsPerMessage, which Update needs to edit it alone. A
+// failed message leaves its deals' IDs empty and the others are still sent.
+func (c *Client) SendBatch(ctx context.Context, channelID string, deals []models.DealInfo) ([]string, error) {
+	if c.botToken == "" {
+		return nil, nil
+	}
+
+	msgIDs := make([]string, len(deals))
+	urlStr := fmt.Sprintf("%s/channels/%s/messages", discordAPIBase, channelID)
+	var errs []error
+	for start := 0; start < len(deals); start += maxEmbedsPerMessage {
+		chunk := deals[start:min(start+maxEmbedsPerMessage, len(deals))]
+		payload := discordWebhookPayload{Flags: c.messageFlags["rfd"]}
+		for _, deal := range chunk {
+			dealPayload := c.createDiscordPayload(deal)
+			payload.Embeds = append(payload.Embeds, dealPayload.Embeds...)
+		}
+
+		body, err := c.doRequest(ctx, "POST", urlStr, payload)
+		if err != nil {
+			slog.Error("Failed to send deal batch to channel", "processor", "rfd", "channel", channelID, "deals", len(chunk), "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		var msgResponse discordMessageResponse
+		if err := json.Unmarshal(body, &msgResponse); err != nil {
+			slog.Error("Failed to parse discord message response", "processor", "rfd", "channel", channelID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		for i := range chunk {
+			msgIDs[start+i] = msgResponse.ID
+		}
+	}
+	return msgIDs, errors.Join(errs...)
+}
+
+// SendDM direct-messages a deal to one Discord user through the bot. It is
+// separate from channel subscriptions and used for WATCH_KEYWORDS.
+func (c *Client) SendDM(ctx context.Context, userID string, deal models.DealInfo) error {
+	if c.botToken == "" {
+		return nil
+	}
+	channelID, err := c.dmChannel(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	payload := c.createDiscordPayload(deal)
+	urlStr := fmt.Sprintf("%s/channels/%s/messages", discordAPIBase, channelID)
+	if _, err := c.doRequest(ctx, "POST", urlStr, payload); err != nil {
+		return fmt.Errorf("send DM to %s: %w", userID, err)
+	}
+	return nil
+}
+
+// dmChannel opens (or reuses) the bot's DM channel with a user. Discord
+// returns the same channel for repeat opens, so it is cached per user.
+func (c *Client) dmChannel(ctx context.Context, userID string) (string, error) {
+	c.dmMu.Lock()
+	channelID, ok := c.dmChannels[userID]
+	c.dmMu.Unlock()
+	if ok {
+		return channelID, nil
+	}
+
+	reqBody, err := json.Marshal(map[string]string{"recipient_id": userID})
+	if err != nil {
+		return "", err
+	}
+	body, err := c.doRawRequest(ctx, "POST", discordAPIBase+"/users/@me/channels", "application/json", reqBody)
+	if err != nil {
+		return "", fmt.Errorf("open DM channel with %s: %w", userID, err)
+	}
+	var channel struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &channel); err != nil || channel.ID == "" {
+		return "", fmt.Errorf("open DM channel with %s: unexpected response %s", userID, string(body))
+	}
+
+	c.dmMu.Lock()
+	if c.dmChannels == nil {
+		c.dmChannels = make(map[string]string)
+	}
+	c.dmChannels[userID] = channel.ID
+	c.dmMu.Unlock()
+	return channel.ID, nil
+}
+
 // Update updates an existing notification in all channels it was published to.
 func (c *Client) Update(ctx context.Context, deal models.DealInfo) error {
 	if c.botToken == "" || len(deal.DiscordMessageIDs) == 0 {
 		return nil
 	}
 
-	payload := createDiscordPayload(deal)
+	payload := c.createDiscordPayload(deal)
+	payload.Flags = c.messageFlags["rfd"]
 	var errs []error
 
 	for channelID, messageID := range deal.DiscordMessageIDs {
 		patchURL := fmt.Sprintf("%s/channels/%s/messages/%s", discordAPIBase, channelID, messageID)
-		_, err := c.doRequest(ctx, "PATCH", patchURL, payload)
+		var err error
+		if index, batched := deal.DiscordEmbedIndex[channelID]; batched {
+			err = c.updateBatchedEmbed(ctx, patchURL, index, payload)
+		} else {
+			_, err = c.doRequest(ctx, "PATCH", patchURL, payload)
+		}
 		if err != nil {
 			slog.Error("Failed to update deal", "processor", "rfd", "channel", channelID, "message", messageID, "error", err)
 			errs = append(errs, fmt.Errorf("channel %s: %w", channelID, err))
@@ -142,12 +394,44 @@ func (c *Client) Update(ctx context.Context, deal models.DealInfo) error {
 	return errors.Join(errs...)
 }
 
+// updateBatchedEmbed edits one deal's embed in a message shared with other
+// deals (BATCH_EMBEDS). The message is read back first so the other embeds
+// are sent unchanged; PATCHing only this deal's embed would drop them.
+func (c *Client) updateBatchedEmbed(ctx context.Context, messageURL string, index int, payload discordWebhookPayload) error {
+	body, err := c.doRawRequest(ctx, "GET", messageURL, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("read batched message: %w", err)
+	}
+	var message struct {
+		Embeds []json.RawMessage `json:"embeds"`
+	}
+	if err := json.Unmarshal(body, &message); err != nil {
+		return fmt.Errorf("parse batched message: %w", err)
+	}
+	if index < 0 || index >= len(message.Embeds) || len(payload.Embeds) != 1 {
+		return fmt.Errorf("batched message has %d embeds, cannot replace embed %d", len(message.Embeds), index)
+	}
+	embed, err := json.Marshal(payload.Embeds[0])
+	if err != nil {
+		return err
+	}
+	message.Embeds[index] = embed
+
+	patch, err := json.Marshal(map[string]any{"embeds": message.Embeds, "flags": payload.Flags})
+	if err != nil {
+		return err
+	}
+	_, err = c.doRawRequest(ctx, "PATCH", messageURL, "application/json", patch)
+	return err
+}
+
 // Internal structures
 type discordWebhookPayload struct {
 	Content         string                  `json:"content"`
 	Embeds          []discordEmbed          `json:"embeds"`
 	Attachments     []discordAttachment     `json:"attachments,omitempty"`
 	AllowedMentions *discordAllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           int                     `json:"flags,omitempty"`
 
 	// Internal field for multipart payload
 	ImageBase64 string `json:"-"`
@@ -192,17 +476,114 @@ type discordMessageResponse struct {
 	ChannelID string `json:"channel_id"`
 }
 
-func createDiscordPayload(deal models.DealInfo) discordWebhookPayload {
-	embed := formatDealToEmbed(deal)
+// createDiscordPayload renders an RFD deal with the client's embed settings.
+func (c *Client) createDiscordPayload(deal models.DealInfo) discordWebhookPayload {
+	colors := c.embedColors()
+	var embed discordEmbed
+	if c.compactEmbeds {
+		embed = formatCompactDealEmbed(deal, colors, c.emoji, c.itemLink)
+	} else {
+		embed = formatDealToEmbed(deal, c.statsPlacement, colors, c.emoji)
+		if field, ok := c.postedField.field(deal.PublishedTimestamp); ok {
+			embed.Fields = append(embed.Fields, field)
+		}
+	}
+	content := "" // clear any hidden message text
+	if !deal.ReheatedAt.IsZero() {
+		content = backOnHotListContent
+	}
 	return discordWebhookPayload{
-		Content: "", // clear any hidden message text
+		Content: content,
 		Embeds:  []discordEmbed{embed},
 	}
 }
 
-func formatDealToEmbed(deal models.DealInfo) discordEmbed {
-	// 1. Determine Title
-	title := deal.Title
+// backOnHotListContent heads reposts of old deals that heated up again
+// (REHEAT_TTL).
+const backOnHotListContent = "🔁 Back on the hot list"
+
+// embedStateColors holds the configured state colors; zero fields use the
+// defaults. Cold deals posted within freshWindow of now are tinted toward
+// warm, fading as they age; a zero freshWindow disables the boost.
+type embedStateColors struct {
+	expired     int
+	priceError  int
+	freshWindow time.Duration
+	now         time.Time
+}
+
+// engagementEmoji holds the configured stat emoji; empty fields use the
+// defaults.
+type engagementEmoji struct {
+	like    string
+	dislike string
+	comment string
+	view    string
+}
+
+// itemLinkLabel holds the configured item link label; an empty text uses
+// "Item".
+type itemLinkLabel struct {
+	domain bool
+	text   string
+}
+
+// label returns the link text for itemURL. Domain labels drop a leading
+// "www." and fall back to the fixed text when the URL has no host.
+func (l itemLinkLabel) label(itemURL string) string {
+	if l.domain {
+		if parsed, err := url.Parse(itemURL); err == nil && parsed.Hostname() != "" {
+			return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+		}
+	}
+	return cmp.Or(l.text, "Item")
+}
+
+// postedTimeField renders the RFD post time as an embed field; an empty
+// layout disables it.
+type postedTimeField struct {
+	layout   string
+	location *time.Location
+}
+
+// field formats posted, e.g. "Jun 1, 2025 12:00 UTC". Zero times have no
+// field.
+func (p postedTimeField) field(posted time.Time) (discordEmbedField, bool) {
+	if p.layout == "" || posted.IsZero() {
+		return discordEmbedField{}, false
+	}
+	location := p.location
+	if location == nil {
+		location = time.UTC
+	}
+	return discordEmbedField{Name: "Posted", Value: posted.In(location).Format(p.layout), Inline: true}, true
+}
+
+// isExpiredDeal reports whether RFD has marked the thread expired, either by
+// moving it to Expired Offers or by prefixing the title.
+func isExpiredDeal(deal models.DealInfo) bool {
+	if deal.Expired || strings.EqualFold(strings.TrimSpace(deal.Category), "expired offers") {
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(deal.Title)), "[expired]")
+}
+
+// availabilityNote warns that a deal may not be available to everyone, e.g.
+// "⚠️ YMMV · 📍 ON only".
+func availabilityNote(deal models.DealInfo) string {
+	var parts []string
+	if deal.YMMV {
+		parts = append(parts, "⚠️ YMMV")
+	}
+	if deal.Region != "" {
+		parts = append(parts, "📍 "+deal.Region+" only")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatDealToEmbed(deal models.DealInfo, statsPlacement string, colors embedStateColors, emoji engagementEmoji) discordEmbed {
+	// 1. Determine Title (the store prefix is redundant with the footer)
+	title := util.StripStorePrefix(deal.Title)
 	if deal.CleanTitle != "" {
 		title = deal.CleanTitle
 	}
@@ -215,17 +596,9 @@ func formatDealToEmbed(deal models.DealInfo) discordEmbed {
 		title += " 🔥"
 	}
 
-	// 5. Heat Color
+	// 5. Color: state (expired > price error) before heat
 	likes, comments, views, hasViews := deal.EngagementStats()
-	liveWarm := isWarmByEngagement(likes, comments, views, hasViews)
-	liveHot := isHotByEngagement(likes, comments, views, hasViews)
-	embedColor := colorColdDeal
-
-	if deal.HasBeenHot || liveHot {
-		embedColor = colorHotDeal
-	} else if deal.HasBeenWarm || liveWarm {
-		embedColor = colorWarmDeal
-	}
+	embedColor := dealEmbedColor(deal, colors)
 
 	// Construct Description
 	var descriptionBuilder strings.Builder
@@ -238,6 +611,21 @@ func formatDealToEmbed(deal models.DealInfo) discordEmbed {
 	}
 	descriptionBuilder.WriteString("\n\n")
 
+	if deal.PriceError {
+		descriptionBuilder.WriteString("🚨 Possible price error\n")
+	}
+	if note := availabilityNote(deal); note != "" {
+		descriptionBuilder.WriteString(note)
+		descriptionBuilder.WriteString("\n")
+	}
+	if priceLine := formatDealPriceLine(deal); priceLine != "" {
+		descriptionBuilder.WriteString(priceLine)
+		descriptionBuilder.WriteString("\n")
+	}
+	if len(deal.Labels) > 0 {
+		descriptionBuilder.WriteString("`" + strings.Join(deal.Labels, "` `") + "`\n")
+	}
+
 	// 6. Thumbnail
 	var thumbnail discordEmbedThumbnail
 	if deal.ThreadImageURL != "" {
@@ -253,12 +641,20 @@ func formatDealToEmbed(deal models.DealInfo) discordEmbed {
 		footerText = strings.TrimSpace(fmt.Sprintf("%s %s", emoji, deal.Retailer))
 	}
 
-	// Add Engagement Metrics directly to description
-	likeIcon := "👍"
-	if likes < 0 {
-		likeIcon = "👎"
+	// Add Engagement Metrics where configured (description by default)
+	engagement := formatEngagementLine(emoji, likes, comments, views, hasViews)
+	var fields []discordEmbedField
+	switch statsPlacement {
+	case StatsInTitle, StatsInField, StatsInBoth:
+		if statsPlacement != StatsInField {
+			title += " (" + engagement + ")"
+		}
+		if statsPlacement != StatsInTitle {
+			fields = append(fields, discordEmbedField{Name: "Engagement", Value: engagement, Inline: true})
+		}
+	default:
+		descriptionBuilder.WriteString(engagement)
 	}
-	descriptionBuilder.WriteString(formatEngagementLine(likeIcon, likes, comments, views, hasViews))
 
 	var timestampStr string
 	if !deal.PublishedTimestamp.IsZero() {
@@ -268,10 +664,11 @@ func formatDealToEmbed(deal models.DealInfo) discordEmbed {
 	embed := discordEmbed{
 		Title:       title,
 		URL:         titleURL,
-		Description: descriptionBuilder.String(),
+		Description: strings.TrimRight(descriptionBuilder.String(), "\n "),
 		Timestamp:   timestampStr,
 		Color:       embedColor,
 		Thumbnail:   thumbnail,
+		Fields:      fields,
 		Footer: discordEmbedFooter{
 			Text: footerText, // Generalized category footer
 		},
@@ -280,6 +677,111 @@ func formatDealToEmbed(deal models.DealInfo) discordEmbed {
 	return embed
 }
 
+// dealEmbedColor picks the embed color: state (expired > price error) before
+// heat, with fresh cold deals tinted toward warm.
+func dealEmbedColor(deal models.DealInfo, colors embedStateColors) int {
+	likes, comments, views, hasViews := deal.EngagementStats()
+	switch {
+	case isExpiredDeal(deal):
+		return cmp.Or(colors.expired, colorExpiredDeal)
+	case deal.PriceError:
+		return cmp.Or(colors.priceError, colorPriceErrorDeal)
+	case deal.HasBeenHot || isHotByEngagement(likes, comments, views, hasViews):
+		return colorHotDeal
+	case deal.HasBeenWarm || isWarmByEngagement(likes, comments, views, hasViews):
+		return colorWarmDeal
+	}
+	if fresh := dealFreshness(deal, colors.freshWindow, colors.now); fresh > 0 {
+		return blendColor(colorColdDeal, colorWarmDeal, fresh*freshBoostMax)
+	}
+	return colorColdDeal
+}
+
+// dealFreshness is 1 for a deal posted just now, falling linearly to 0 once
+// it is window old. Deals without a post time fall back to FirstSeen.
+func dealFreshness(deal models.DealInfo, window time.Duration, now time.Time) float64 {
+	posted := deal.PublishedTimestamp
+	if posted.IsZero() {
+		posted = deal.FirstSeen
+	}
+	if window <= 0 || posted.IsZero() || now.IsZero() {
+		return 0
+	}
+	age := max(now.Sub(posted), 0)
+	if age >= window {
+		return 0
+	}
+	return 1 - float64(age)/float64(window)
+}
+
+// blendColor mixes two 0xRRGGBB colors, t=0 giving from and t=1 giving to.
+func blendColor(from, to int, t float64) int {
+	mix := func(shift uint) int {
+		a, b := float64(from>>shift&0xFF), float64(to>>shift&0xFF)
+		return int(math.Round(a+(b-a)*t)) << shift
+	}
+	return mix(16) | mix(8) | mix(0)
+}
+
+// compactLinkText escapes the characters that would end a markdown link label.
+var compactLinkText = strings.NewReplacer("[", "\\[", "]", "\\]")
+
+// formatCompactDealEmbed renders a deal as one description line, e.g.
+// "[Echo Dot](rfd) · 💰 **$29** · 👍 12  💬 3 · [Item](amazon)", with no
+// title, thumbnail or footer so it scans quickly on mobile.
+func formatCompactDealEmbed(deal models.DealInfo, colors embedStateColors, emoji engagementEmoji, itemLink itemLinkLabel) discordEmbed {
+	title := util.StripStorePrefix(deal.Title)
+	if deal.CleanTitle != "" {
+		title = deal.CleanTitle
+	}
+	if deal.PriceError {
+		title = "🚨 " + title
+	}
+	if deal.HasBeenHot {
+		title += " 🔥"
+	}
+
+	threadURL, hasThread := discordEmbedURL(deal.PrimaryPostURL())
+	if hasThread {
+		title = fmt.Sprintf("[%s](%s)", compactLinkText.Replace(title), threadURL)
+	}
+	parts := []string{title}
+	if priceLine := formatDealPriceLine(deal); priceLine != "" {
+		parts = append(parts, priceLine)
+	}
+	if note := availabilityNote(deal); note != "" {
+		parts = append(parts, note)
+	}
+
+	likes, comments, views, hasViews := deal.EngagementStats()
+	parts = append(parts, formatEngagementLine(emoji, likes, comments, views, hasViews))
+	if itemURL, ok := discordEmbedURL(deal.ActualDealURL); ok && itemURL != threadURL {
+		parts = append(parts, fmt.Sprintf("[%s](%s)", compactLinkText.Replace(itemLink.label(itemURL)), itemURL))
+	}
+
+	return discordEmbed{
+		Description: strings.Join(parts, " · "),
+		Color:       dealEmbedColor(deal, colors),
+	}
+}
+
+// formatDealPriceLine renders the current price, struck-through original and
+// percent off. The discount is only shown when the original price is known.
+func formatDealPriceLine(deal models.DealInfo) string {
+	price := strings.TrimSpace(deal.Price)
+	if price == "" {
+		return ""
+	}
+	line := "💰 **" + price + "**"
+	if deal.DiscountPct > 0 {
+		if original := strings.TrimSpace(deal.OriginalPrice); original != "" {
+			line += " ~~" + original + "~~"
+		}
+		line += fmt.Sprintf(" (%d%% off)", deal.DiscountPct)
+	}
+	return line
+}
+
 func preferredDealURL(deal models.DealInfo) string {
 	if safeURL, ok := discordEmbedURL(deal.ActualDealURL); ok {
 		return safeURL
@@ -314,8 +816,6 @@ func discordEmbedURL(raw string) (string, bool) {
 // doRequest handles the shared retry/rate-limit/backoff loop for Discord API calls.
 // It returns the response body on success.
 func (c *Client) doRequest(ctx context.Context, method, targetURL string, payload discordWebhookPayload) ([]byte, error) {
-	start := time.Now()
-
 	var payloadBodyBytes []byte
 	var contentType = "application/json"
 
@@ -345,6 +845,12 @@ func (c *Client) doRequest(ctx context.Context, method, targetURL string, payloa
 		}
 		payloadBodyBytes = jsonBytes
 	}
+	return c.doRawRequest(ctx, method, targetURL, contentType, payloadBodyBytes)
+}
+
+// doRawRequest sends an already-encoded body through the retry loop.
+func (c *Client) doRawRequest(ctx context.Context, method, targetURL, contentType string, payloadBodyBytes []byte) ([]byte, error) {
+	start := time.Now()
 
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
@@ -457,11 +963,18 @@ func isHotByEngagement(likes, comments, views int, hasViews bool) bool {
 	return calculateNoViewsEngagement(likes, comments) >= noViewsEngagementThresholdHot
 }
 
-func formatEngagementLine(likeIcon string, likes, comments, views int, hasViews bool) string {
+// formatEngagementLine renders e.g. "👍 12  💬 3  👀 400", with 👎 for a
+// negative score.
+func formatEngagementLine(emoji engagementEmoji, likes, comments, views int, hasViews bool) string {
+	likeIcon := cmp.Or(emoji.like, "👍")
+	if likes < 0 {
+		likeIcon = cmp.Or(emoji.dislike, "👎")
+	}
+	commentIcon := cmp.Or(emoji.comment, "💬")
 	if hasViews {
-		return fmt.Sprintf("%s %d  💬 %d  👀 %d", likeIcon, likes, comments, views)
+		return fmt.Sprintf("%s %d  %s %d  %s %d", likeIcon, likes, commentIcon, comments, cmp.Or(emoji.view, "👀"), views)
 	}
-	return fmt.Sprintf("%s %d  💬 %d", likeIcon, likes, comments)
+	return fmt.Sprintf("%s %d  %s %d", likeIcon, likes, commentIcon, comments)
 }
 
 // IsWarm determines if a deal is considered warm based on community engagement.
@@ -487,6 +1000,9 @@ func (c *Client) sendPayloadToSubscriptions(ctx context.Context, processor, titl
 	if c.botToken == "" {
 		return nil
 	}
+	if payload.Flags == 0 {
+		payload.Flags = c.messageFlags[processor]
+	}
 
 	var errs []error
 	for i, sub := range subs {
@@ -992,7 +1508,7 @@ func (c *Client) reportXPostIssue(alert models.OnEveryCornerAlert, subs []models
 		SourceApp:      "X",
 		RawTitle:       "OnEveryCorner X auto-post failed",
 		RawText:        errorText,
-		ReceivedAt:     time.Now(),
+		ReceivedAt:     c.now(),
 		SystemSeverity: "error",
 		SystemDetails:  fmt.Sprintf("X account %d failed to post a goal alert. Discord alerts still sent.", account),
 		SystemFields:   fields,
@@ -1008,7 +1524,7 @@ func (c *Client) shouldReportXPostIssue(key string) bool {
 	if c.xPostIssueLast == nil {
 		c.xPostIssueLast = make(map[string]time.Time)
 	}
-	now := time.Now()
+	now := c.now()
 	if last, ok := c.xPostIssueLast[key]; ok && now.Sub(last) < xPostIssueRepeatInterval {
 		return false
 	}
@@ -1373,7 +1889,7 @@ func formatEbayEmbed(item ebay.EbayItem) discordEmbed {
 
 	if itemURL != "" {
 		itemURL = util.CleanProductURL(itemURL)
-		if cleanedURL, changed := util.CleanReferralLink(itemURL, "", ""); changed {
+		if cleanedURL, changed := util.CleanReferralLink(itemURL, util.AffiliateConfig{}); changed {
 			itemURL = cleanedURL
 		}
 	}
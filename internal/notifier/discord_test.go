@@ -17,6 +17,7 @@ import (
 	"github.com/pauljones0/rfd-discord-bot/internal/crux"
 	"github.com/pauljones0/rfd-discord-bot/internal/ebay"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 )
 
 func TestFormatDealToEmbed(t *testing.T) {
@@ -38,7 +39,7 @@ func TestFormatDealToEmbed(t *testing.T) {
 		},
 	}
 
-	embed := formatDealToEmbed(deal)
+	embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 
 	// Check Title format: "Title 🔥" (suffix added for hot deals)
 	expectedTitle := deal.Title + " 🔥"
@@ -85,7 +86,7 @@ func TestFormatDealToEmbed_FallsBackToPostURLWhenActualDealURLInvalid(t *testing
 		},
 	}
 
-	embed := formatDealToEmbed(deal)
+	embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 	if embed.URL != deal.PostURL {
 		t.Fatalf("URL incorrect. Got: %s, Want fallback: %s", embed.URL, deal.PostURL)
 	}
@@ -107,7 +108,7 @@ func TestFormatDealToEmbed_FallsBackWhenActualDealURLContainsSpaces(t *testing.T
 		},
 	}
 
-	embed := formatDealToEmbed(deal)
+	embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 	if embed.URL != deal.PostURL {
 		t.Fatalf("URL incorrect. Got: %s, Want fallback: %s", embed.URL, deal.PostURL)
 	}
@@ -296,13 +297,344 @@ func TestFormatDealToEmbed_OmitsViewsWhenUnavailable(t *testing.T) {
 		},
 	}
 
-	embed := formatDealToEmbed(deal)
+	embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 	expectedDesc := "[RFD](https://forums.redflagdeals.com/deal-1) \n\n👍 13  💬 10"
 	if embed.Description != expectedDesc {
 		t.Fatalf("Description incorrect.\nGot:  %q\nWant: %q", embed.Description, expectedDesc)
 	}
 }
 
+func TestFormatDealToEmbed_StripsStorePrefixFromRawTitle(t *testing.T) {
+	deal := models.DealInfo{Title: "[Amazon.ca] Echo Dot $29", Retailer: "Amazon.ca"}
+	if got := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{}).Title; got != "Echo Dot $29" {
+		t.Fatalf("Title = %q, want store prefix stripped", got)
+	}
+
+	deal.CleanTitle = "Amazon Echo Dot (5th Gen)"
+	if got := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{}).Title; got != "Amazon Echo Dot (5th Gen)" {
+		t.Fatalf("Title = %q, want CleanTitle", got)
+	}
+}
+
+func TestFormatDealToEmbed_PriceLine(t *testing.T) {
+	base := models.DealInfo{
+		Title:   "Great Deal",
+		PostURL: "https://forums.redflagdeals.com/deal-1",
+		Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1", LikeCount: 2}},
+	}
+
+	discounted := base
+	discounted.Price = "$74.99"
+	discounted.OriginalPrice = "$99.99"
+	discounted.DiscountPct = 25
+	want := "[RFD](https://forums.redflagdeals.com/deal-1) \n\n💰 **$74.99** ~~$99.99~~ (25% off)\n👍 2  💬 0"
+	if got := formatDealToEmbed(discounted, "", embedStateColors{}, engagementEmoji{}).Description; got != want {
+		t.Fatalf("Description incorrect.\nGot:  %q\nWant: %q", got, want)
+	}
+
+	priceOnly := base
+	priceOnly.Price = "$74.99"
+	want = "[RFD](https://forums.redflagdeals.com/deal-1) \n\n💰 **$74.99**\n👍 2  💬 0"
+	if got := formatDealToEmbed(priceOnly, "", embedStateColors{}, engagementEmoji{}).Description; got != want {
+		t.Fatalf("Description incorrect.\nGot:  %q\nWant: %q", got, want)
+	}
+}
+
+func TestFormatDealToEmbed_StatsPlacement(t *testing.T) {
+	deal := models.DealInfo{
+		Title:   "Great Deal",
+		PostURL: "https://forums.redflagdeals.com/deal-1",
+		Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1", LikeCount: 13, CommentCount: 10}},
+	}
+	const stats = "👍 13  💬 10"
+	const links = "[RFD](https://forums.redflagdeals.com/deal-1)"
+
+	tests := []struct {
+		placement string
+		wantTitle string
+		wantDesc  string
+		wantField bool
+	}{
+		{placement: "", wantTitle: "Great Deal", wantDesc: links + " \n\n" + stats},
+		{placement: StatsInDescription, wantTitle: "Great Deal", wantDesc: links + " \n\n" + stats},
+		{placement: StatsInTitle, wantTitle: "Great Deal (" + stats + ")", wantDesc: links},
+		{placement: StatsInField, wantTitle: "Great Deal", wantDesc: links, wantField: true},
+		{placement: StatsInBoth, wantTitle: "Great Deal (" + stats + ")", wantDesc: links, wantField: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("placement="+tt.placement, func(t *testing.T) {
+			embed := formatDealToEmbed(deal, tt.placement, embedStateColors{}, engagementEmoji{})
+			if embed.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", embed.Title, tt.wantTitle)
+			}
+			if embed.Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", embed.Description, tt.wantDesc)
+			}
+			if tt.wantField {
+				if len(embed.Fields) != 1 || embed.Fields[0].Name != "Engagement" || embed.Fields[0].Value != stats {
+					t.Errorf("Fields = %+v, want one Engagement field with %q", embed.Fields, stats)
+				}
+			} else if len(embed.Fields) != 0 {
+				t.Errorf("Fields = %+v, want none", embed.Fields)
+			}
+		})
+	}
+}
+
+func TestFormatDealToEmbed_CustomEngagementEmoji(t *testing.T) {
+	c := New("token")
+	c.SetEngagementEmoji(map[string]string{"like": "<:rfdup:123456789012345678>", "comment": "🗨️", "view": "<a:eyes:876543210987654321>"})
+
+	deal := models.DealInfo{
+		Title:   "Great Deal",
+		Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1", LikeCount: 12, CommentCount: 3, ViewCount: 400, ViewCountAvailable: true}},
+	}
+	embed := formatDealToEmbed(deal, StatsInField, c.stateColors, c.emoji)
+	want := "<:rfdup:123456789012345678> 12  🗨️ 3  <a:eyes:876543210987654321> 400"
+	if len(embed.Fields) != 1 || embed.Fields[0].Value != want {
+		t.Fatalf("Fields = %+v, want Engagement %q", embed.Fields, want)
+	}
+
+	// Unset keys keep the defaults.
+	deal.Threads[0].LikeCount = -2
+	deal.Threads[0].ViewCountAvailable = false
+	embed = formatDealToEmbed(deal, StatsInField, c.stateColors, c.emoji)
+	if want := "👎 -2  🗨️ 3"; embed.Fields[0].Value != want {
+		t.Errorf("Engagement = %q, want %q", embed.Fields[0].Value, want)
+	}
+}
+
+func TestFormatDealToEmbed_Labels(t *testing.T) {
+	deal := models.DealInfo{
+		Title:   "Great Deal",
+		PostURL: "https://forums.redflagdeals.com/deal-1",
+		Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1", LikeCount: 1}},
+		Labels:  []string{"🔥Clearance", "💻Tech"},
+	}
+
+	embed := formatDealToEmbed(deal, StatsInTitle, embedStateColors{}, engagementEmoji{})
+	want := "[RFD](https://forums.redflagdeals.com/deal-1) \n\n`🔥Clearance` `💻Tech`"
+	if embed.Description != want {
+		t.Errorf("Description = %q, want %q", embed.Description, want)
+	}
+}
+
+func TestFormatDealToEmbed_PriceError(t *testing.T) {
+	deal := models.DealInfo{
+		Title:      "TV $49",
+		PostURL:    "https://forums.redflagdeals.com/deal-1",
+		Threads:    []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}},
+		PriceError: true,
+		HasBeenHot: true,
+	}
+
+	embed := formatDealToEmbed(deal, StatsInTitle, embedStateColors{}, engagementEmoji{})
+	if !strings.Contains(embed.Description, "🚨 Possible price error") {
+		t.Errorf("Description = %q, want a price error line", embed.Description)
+	}
+	if embed.Color != colorPriceErrorDeal {
+		t.Errorf("Color = %d, want price-error purple over hot", embed.Color)
+	}
+}
+
+func TestFormatDealToEmbed_AvailabilityNote(t *testing.T) {
+	tests := []struct {
+		name   string
+		ymmv   bool
+		region string
+		want   string
+	}{
+		{name: "ymmv", ymmv: true, want: "⚠️ YMMV"},
+		{name: "region", region: "QC", want: "📍 QC only"},
+		{name: "both", ymmv: true, region: "ON", want: "⚠️ YMMV · 📍 ON only"},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deal := models.DealInfo{Title: "Slurpee", YMMV: tt.ymmv, Region: tt.region}
+			desc := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{}).Description
+			if tt.want == "" {
+				if strings.Contains(desc, "YMMV") || strings.Contains(desc, "📍") {
+					t.Errorf("Description = %q, want no availability note", desc)
+				}
+				return
+			}
+			if !strings.Contains(desc, tt.want+"\n") {
+				t.Errorf("Description = %q, want note %q", desc, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateDiscordPayload_CompactEmbed(t *testing.T) {
+	deal := models.DealInfo{
+		Title:          "[Amazon] Echo Dot [5th Gen] $29",
+		Price:          "$29",
+		ActualDealURL:  "https://www.amazon.ca/dp/B09B8V1LZ3",
+		PostURL:        "https://forums.redflagdeals.com/echo-dot-2806520/",
+		ThreadImageURL: "https://forums.redflagdeals.com/thumb.jpg",
+		Retailer:       "Amazon",
+		Category:       "Electronics",
+		HasBeenHot:     true,
+		Threads:        []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/echo-dot-2806520/", LikeCount: 12, CommentCount: 3}},
+	}
+
+	c := New("token")
+	c.SetStatsPlacement(StatsInField)
+	c.SetPostedField("Jan 2, 2006 15:04 MST", time.UTC)
+	rich := c.createDiscordPayload(deal).Embeds[0]
+	if rich.Thumbnail.URL == "" {
+		t.Fatal("rich embed lost its thumbnail; compact mode must be opt-in")
+	}
+
+	c.SetCompactEmbeds(true)
+	embed := c.createDiscordPayload(deal).Embeds[0]
+	if embed.Thumbnail.URL != "" {
+		t.Errorf("Thumbnail.URL = %q, want none in compact mode", embed.Thumbnail.URL)
+	}
+	if embed.Title != "" || len(embed.Fields) != 0 || embed.Footer.Text != "" {
+		t.Errorf("compact embed = %+v, want everything in the description", embed)
+	}
+	want := `[Echo Dot \[5th Gen\] $29 🔥](https://forums.redflagdeals.com/echo-dot-2806520/) · 💰 **$29** · 👍 12  💬 3 · [Item](https://www.amazon.ca/dp/B09B8V1LZ3)`
+	if embed.Description != want {
+		t.Errorf("Description = %q, want %q", embed.Description, want)
+	}
+	if strings.Contains(embed.Description, "\n") {
+		t.Errorf("Description = %q, want a single line", embed.Description)
+	}
+	if embed.Color != colorHotDeal {
+		t.Errorf("Color = %d, want hot color %d", embed.Color, colorHotDeal)
+	}
+}
+
+func TestCreateDiscordPayload_ItemLinkLabel(t *testing.T) {
+	deal := models.DealInfo{
+		Title:         "Echo Dot $29",
+		ActualDealURL: "https://www.amazon.ca/dp/B09B8V1LZ3",
+		PostURL:       "https://forums.redflagdeals.com/echo-dot-2806520/",
+	}
+	tests := []struct {
+		name, mode, text, want string
+	}{
+		{name: "default", want: "[Item](https://www.amazon.ca/dp/B09B8V1LZ3)"},
+		{name: "fixed text", mode: ItemLinkFixed, text: "Buy [now]", want: `[Buy \[now\]](https://www.amazon.ca/dp/B09B8V1LZ3)`},
+		{name: "domain", mode: ItemLinkDomain, text: "ignored", want: "[amazon.ca](https://www.amazon.ca/dp/B09B8V1LZ3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("token")
+			c.SetItemLinkLabel(tt.mode, tt.text)
+			c.SetCompactEmbeds(true)
+			embed := c.createDiscordPayload(deal).Embeds[0]
+			if !strings.HasSuffix(embed.Description, " · "+tt.want) {
+				t.Errorf("Description = %q, want it to end with item link %q", embed.Description, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateDiscordPayload_PostedField(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	c := New("token")
+	c.SetPostedField("Jan 2, 2006 15:04 MST", toronto)
+	deal := models.DealInfo{Title: "Echo Dot $29", PublishedTimestamp: time.Date(2025, 6, 1, 16, 0, 0, 0, time.UTC)}
+
+	embed := c.createDiscordPayload(deal).Embeds[0]
+	want := discordEmbedField{Name: "Posted", Value: "Jun 1, 2025 12:00 EDT", Inline: true}
+	if len(embed.Fields) != 1 || embed.Fields[0] != want {
+		t.Fatalf("Fields = %+v, want [%+v]", embed.Fields, want)
+	}
+
+	deal.PublishedTimestamp = time.Time{}
+	if embed := c.createDiscordPayload(deal).Embeds[0]; len(embed.Fields) != 0 {
+		t.Errorf("Fields = %+v, want none for a zero timestamp", embed.Fields)
+	}
+}
+
+func TestFormatDealToEmbed_FreshDealsAreWarmerThanOldOnes(t *testing.T) {
+	now := time.Date(2026, 4, 16, 18, 0, 0, 0, time.UTC)
+	c := New("token")
+	c.SetClock(util.NewFakeClock(now))
+	c.SetFreshBoostWindow(30 * time.Minute)
+	colors := c.embedColors()
+
+	colorAt := func(age time.Duration) int {
+		deal := models.DealInfo{Title: "Deal", PublishedTimestamp: now.Add(-age)}
+		return formatDealToEmbed(deal, "", colors, engagementEmoji{}).Color
+	}
+	// warmth is the red channel, which rises from cold (0x2B) toward warm (0xF5).
+	warmth := func(color int) int { return color >> 16 & 0xFF }
+
+	justPosted, fading, old := colorAt(time.Minute), colorAt(20*time.Minute), colorAt(2*time.Hour)
+	if old != colorColdDeal {
+		t.Errorf("old deal color = %#06x, want cold %#06x", old, colorColdDeal)
+	}
+	if !(warmth(justPosted) > warmth(fading) && warmth(fading) > warmth(old)) {
+		t.Errorf("colors just posted=%#06x, 20m=%#06x, 2h=%#06x, want warmth to decay with age", justPosted, fading, old)
+	}
+	if justPosted == colorWarmDeal {
+		t.Error("fresh deal should stay short of the full warm color")
+	}
+
+	deal := models.DealInfo{Title: "Deal", PublishedTimestamp: now.Add(-time.Minute)}
+	if got := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{}).Color; got != colorColdDeal {
+		t.Errorf("color without FRESH_BOOST_WINDOW = %#06x, want cold", got)
+	}
+	deal.PriceError = true
+	if got := formatDealToEmbed(deal, "", colors, engagementEmoji{}).Color; got != colorPriceErrorDeal {
+		t.Errorf("fresh price error color = %#06x, want state color to win", got)
+	}
+}
+
+func TestFormatDealToEmbed_StateColorPrecedence(t *testing.T) {
+	hotThread := []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1", LikeCount: 500, CommentCount: 200, ViewCount: 1000}}
+	tests := []struct {
+		name   string
+		deal   models.DealInfo
+		colors embedStateColors
+		want   int
+	}{
+		{
+			name: "expired and hot renders grey",
+			deal: models.DealInfo{Title: "[Expired] TV $299", Category: "Expired Offers", HasBeenHot: true, Threads: hotThread},
+			want: colorExpiredDeal,
+		},
+		{
+			name: "expired beats price error",
+			deal: models.DealInfo{Title: "[Expired] TV $49", PriceError: true, HasBeenHot: true, Threads: hotThread},
+			want: colorExpiredDeal,
+		},
+		{
+			name: "price error renders purple",
+			deal: models.DealInfo{Title: "TV $49", PriceError: true, HasBeenHot: true, Threads: hotThread},
+			want: colorPriceErrorDeal,
+		},
+		{
+			name:   "configured colors override defaults",
+			deal:   models.DealInfo{Title: "TV $49", Category: "Expired Offers", PriceError: true},
+			colors: embedStateColors{expired: 0x123456, priceError: 0x654321},
+			want:   0x123456,
+		},
+		{
+			name: "hot without state keeps heat color",
+			deal: models.DealInfo{Title: "TV $299", HasBeenHot: true, Threads: hotThread},
+			want: colorHotDeal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDealToEmbed(tt.deal, "", tt.colors, engagementEmoji{}).Color; got != tt.want {
+				t.Errorf("Color = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatDealToEmbed_Footer(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -342,7 +674,7 @@ func TestFormatDealToEmbed_Footer(t *testing.T) {
 				Category: tt.category,
 				Retailer: tt.retailer,
 			}
-			embed := formatDealToEmbed(deal)
+			embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 			if embed.Footer.Text != tt.wantFooter {
 				t.Errorf("Footer.Text = %q, want %q", embed.Footer.Text, tt.wantFooter)
 			}
@@ -422,7 +754,7 @@ func TestFormatDealToEmbed_Colors(t *testing.T) {
 					},
 				},
 			}
-			embed := formatDealToEmbed(deal)
+			embed := formatDealToEmbed(deal, "", embedStateColors{}, engagementEmoji{})
 			if embed.Color != tt.wantColor {
 				t.Errorf("Color = %d, want %d", embed.Color, tt.wantColor)
 			}
@@ -570,6 +902,58 @@ func TestClient_Send(t *testing.T) {
 	}
 }
 
+func TestDiscordPayload_FlagsSerializeWhenSet(t *testing.T) {
+	unset, err := json.Marshal(discordWebhookPayload{Content: "link"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(unset), `"flags"`) {
+		t.Errorf("flags should be omitted when unset: %s", unset)
+	}
+
+	set, err := json.Marshal(discordWebhookPayload{Content: "link", Flags: MessageFlagSuppressEmbeds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(set), `"flags":4`) {
+		t.Errorf("expected SUPPRESS_EMBEDS flag in payload: %s", set)
+	}
+}
+
+func TestClient_Send_AppliesMessageFlagsPerProcessor(t *testing.T) {
+	var gotFlags []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload discordWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		gotFlags = append(gotFlags, payload.Flags)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "12345", "channel_id": "67890"}`))
+	}))
+	defer server.Close()
+
+	client := New("token")
+	client.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	client.client = server.Client()
+	client.client.Transport = &rewriteTransport{target: server.URL}
+	client.SetMessageFlags("rfd", MessageFlagSuppressEmbeds|MessageFlagSuppressNotifications)
+
+	subs := []models.Subscription{{ChannelID: "67890"}}
+	deal := models.DealInfo{Title: "Test Deal", PostURL: "http://example.com"}
+	if _, err := client.Send(context.Background(), deal, subs); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	if err := client.sendPayloadToSubscriptions(context.Background(), "crux", "alert", discordWebhookPayload{Content: "x"}, subs); err != nil {
+		t.Fatalf("sendPayloadToSubscriptions() returned error: %v", err)
+	}
+
+	want := []int{MessageFlagSuppressEmbeds | MessageFlagSuppressNotifications, 0}
+	if len(gotFlags) != 2 || gotFlags[0] != want[0] || gotFlags[1] != want[1] {
+		t.Errorf("flags = %v, want %v (rfd only)", gotFlags, want)
+	}
+}
+
 func TestClient_Send_UsesPostURLFallbackWhenActualDealURLInvalid(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var payload discordWebhookPayload
@@ -657,6 +1041,132 @@ func TestClient_Update(t *testing.T) {
 	}
 }
 
+func TestCreateDiscordPayload_ReheatedContent(t *testing.T) {
+	c := New("token")
+	deal := models.DealInfo{Title: "Dyson V15", PostURL: "https://forums.redflagdeals.com/dyson-1"}
+	if got := c.createDiscordPayload(deal).Content; got != "" {
+		t.Errorf("Content = %q, want empty for a first post", got)
+	}
+	deal.ReheatedAt = time.Now()
+	if got := c.createDiscordPayload(deal).Content; got != backOnHotListContent {
+		t.Errorf("Content = %q, want %q", got, backOnHotListContent)
+	}
+}
+
+func TestClient_SendDM(t *testing.T) {
+	var opens, messages int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v10/users/@me/channels":
+			opens++
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["recipient_id"] != "111" {
+				t.Errorf("DM open body = %v (%v), want recipient_id 111", body, err)
+			}
+			w.Write([]byte(`{"id": "dm-1", "type": 1}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v10/channels/dm-1/messages":
+			messages++
+			w.Write([]byte(`{"id": "msg-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := New("token")
+	client.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	client.client.Transport = &rewriteTransport{target: server.URL}
+
+	deal := models.DealInfo{Title: "Dyson V15", PostURL: "https://forums.redflagdeals.com/dyson-1", Threads: []models.ThreadContext{{LikeCount: 1}}}
+	for i := 0; i < 2; i++ {
+		if err := client.SendDM(context.Background(), "111", deal); err != nil {
+			t.Fatalf("SendDM() returned error: %v", err)
+		}
+	}
+	if opens != 1 || messages != 2 {
+		t.Errorf("opens = %d, messages = %d, want the DM channel opened once and reused", opens, messages)
+	}
+}
+
+func TestClient_SendBatch(t *testing.T) {
+	var embedCounts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v10/channels/chan-1/messages" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var payload discordWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		embedCounts = append(embedCounts, len(payload.Embeds))
+		fmt.Fprintf(w, `{"id": "msg-%d"}`, len(embedCounts))
+	}))
+	defer server.Close()
+
+	client := New("token")
+	client.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	client.client.Transport = &rewriteTransport{target: server.URL}
+
+	deals := make([]models.DealInfo, 12)
+	for i := range deals {
+		deals[i] = models.DealInfo{Title: fmt.Sprintf("Deal %d", i), PostURL: fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)}
+	}
+	msgIDs, err := client.SendBatch(context.Background(), "chan-1", deals)
+	if err != nil {
+		t.Fatalf("SendBatch() returned error: %v", err)
+	}
+	if len(embedCounts) != 2 || embedCounts[0] != 10 || embedCounts[1] != 2 {
+		t.Errorf("embeds per message = %v, want [10 2]", embedCounts)
+	}
+	if msgIDs[0] != "msg-1" || msgIDs[9] != "msg-1" || msgIDs[10] != "msg-2" || len(msgIDs) != 12 {
+		t.Errorf("message IDs = %v, want the first 10 deals on msg-1 and the rest on msg-2", msgIDs)
+	}
+}
+
+func TestClient_Update_BatchedEmbedKeepsSiblings(t *testing.T) {
+	var patched struct {
+		Embeds []map[string]any `json:"embeds"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"id": "msg-1", "embeds": [{"title": "Other deal"}, {"title": "Old title"}, {"title": "Third deal"}]}`))
+		case "PATCH":
+			if err := json.NewDecoder(r.Body).Decode(&patched); err != nil {
+				t.Fatalf("decode patch: %v", err)
+			}
+			w.Write([]byte(`{"id": "msg-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := New("token")
+	client.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	client.client.Transport = &rewriteTransport{target: server.URL}
+
+	deal := models.DealInfo{
+		Title:             "New title",
+		PostURL:           "https://forums.redflagdeals.com/deal-1",
+		DiscordMessageIDs: map[string]string{"chan-1": "msg-1"},
+		DiscordEmbedIndex: map[string]int{"chan-1": 1},
+	}
+	if err := client.Update(context.Background(), deal); err != nil {
+		t.Fatalf("Update() returned error: %v", err)
+	}
+	var titles []any
+	for _, embed := range patched.Embeds {
+		titles = append(titles, embed["title"])
+	}
+	if len(titles) != 3 || titles[0] != "Other deal" || titles[2] != "Third deal" || !strings.Contains(fmt.Sprint(titles[1]), "New title") {
+		t.Errorf("patched embed titles = %v, want only the middle embed replaced", titles)
+	}
+}
+
 func TestClient_Send_RetriesOn5xx(t *testing.T) {
 	var attempts int32
 
@@ -976,3 +1486,21 @@ func TestOrdinal(t *testing.T) {
 		}
 	}
 }
+
+func TestShouldReportXPostIssue_RepeatIntervalWithFakeClock(t *testing.T) {
+	clock := util.NewFakeClock(time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC))
+	c := &Client{}
+	c.SetClock(clock)
+
+	if !c.shouldReportXPostIssue("acct1") {
+		t.Fatal("Expected the first issue to be reported")
+	}
+	clock.Advance(xPostIssueRepeatInterval - time.Second)
+	if c.shouldReportXPostIssue("acct1") {
+		t.Fatal("Expected a repeat inside the interval to be suppressed")
+	}
+	clock.Advance(time.Second)
+	if !c.shouldReportXPostIssue("acct1") {
+		t.Fatal("Expected the issue to be reported again once the interval elapsed")
+	}
+}
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+// WebhookSink POSTs deals as JSON to a generic webhook (DEAL_WEBHOOK_URL) for
+// integrations such as Zapier, n8n or a custom dashboard.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// webhookDealPayload is the body of each webhook POST.
+type webhookDealPayload struct {
+	Event string          `json:"event"` // "new" or "updated"
+	Deal  models.DealInfo `json:"deal"`
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendDeal POSTs one deal. Any non-2xx response is an error.
+func (w *WebhookSink) SendDeal(ctx context.Context, event string, deal models.DealInfo) error {
+	body, err := json.Marshal(webhookDealPayload{Event: event, Deal: deal})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post deal webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("deal webhook returned status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
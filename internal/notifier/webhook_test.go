@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+func TestWebhookSink_SendDeal(t *testing.T) {
+	var got webhookDealPayload
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	deal := models.DealInfo{
+		DocumentID:        "deal-1",
+		Title:             "Great Deal",
+		PostURL:           "https://forums.redflagdeals.com/deal-1",
+		Price:             "$19.99",
+		Description:       "Half price",
+		Retailer:          "Costco",
+		DiscordMessageIDs: map[string]string{"channel1": "msg-1"},
+	}
+	if err := NewWebhookSink(srv.URL).SendDeal(context.Background(), "new", deal); err != nil {
+		t.Fatalf("SendDeal() error = %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	if got.Event != "new" {
+		t.Errorf("event = %q, want new", got.Event)
+	}
+	if got.Deal.DocumentID != deal.DocumentID || got.Deal.Title != deal.Title || got.Deal.Price != deal.Price ||
+		got.Deal.Description != deal.Description || got.Deal.Retailer != deal.Retailer ||
+		got.Deal.DiscordMessageIDs["channel1"] != "msg-1" {
+		t.Errorf("delivered deal = %+v, want %+v", got.Deal, deal)
+	}
+}
+
+func TestWebhookSink_SendDealErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad hook", http.StatusGone)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookSink(srv.URL).SendDeal(context.Background(), "updated", models.DealInfo{Title: "Deal"})
+	if err == nil {
+		t.Fatal("SendDeal() error = nil, want an error for a 410 response")
+	}
+}
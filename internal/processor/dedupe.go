@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
 	"net/url"
@@ -222,6 +223,72 @@ func recentDealsByCanonicalURL(recentDeals []models.DealInfo) map[string]*models
 	return byURL
 }
 
+// dedupeScrapedThreads collapses list entries for the same RFD thread, e.g. a
+// pinned copy and the normal row, or a thread that moved between pages while
+// the list was scraped. The richest record is kept in the position of the
+// first one; the second return value is how many were dropped.
+func dedupeScrapedThreads(deals []models.DealInfo) ([]models.DealInfo, int) {
+	kept := make([]models.DealInfo, 0, len(deals))
+	index := make(map[string]int, len(deals))
+	for _, deal := range deals {
+		key := threadKey(deal.PostURL)
+		i, seen := index[key]
+		if !seen || key == "" {
+			index[key] = len(kept)
+			kept = append(kept, deal)
+			continue
+		}
+		if richerScrapedDeal(deal, kept[i]) {
+			kept[i] = deal
+		}
+	}
+	return kept, len(deals) - len(kept)
+}
+
+// richerScrapedDeal reports whether candidate is the more complete list
+// record: more populated fields net of parse warnings, then more engagement
+// (the fresher copy).
+func richerScrapedDeal(candidate, current models.DealInfo) bool {
+	if a, b := populatedListFields(candidate), populatedListFields(current); a != b {
+		return a > b
+	}
+	candidateLikes, candidateComments, candidateViews := candidate.Stats()
+	currentLikes, currentComments, currentViews := current.Stats()
+	return candidateLikes+candidateComments+candidateViews > currentLikes+currentComments+currentViews
+}
+
+func populatedListFields(deal models.DealInfo) int {
+	n := -len(deal.ParseWarnings)
+	for _, field := range []string{deal.ActualDealURL, deal.ThreadImageURL, deal.Price, deal.OriginalPrice, deal.Retailer, deal.Category, deal.AuthorName} {
+		if field != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// recentDealsByThreadKey indexes recent deals by every RFD thread they hold,
+// so a thread RFD re-timed (bumped) can be matched back to its record.
+func recentDealsByThreadKey(recentDeals []models.DealInfo) map[string]*models.DealInfo {
+	byThread := make(map[string]*models.DealInfo)
+	for i := range recentDeals {
+		deal := &recentDeals[i]
+		keys := []string{threadKey(deal.PostURL)}
+		for _, thread := range deal.Threads {
+			keys = append(keys, threadKey(thread.PostURL))
+		}
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			if current := byThread[key]; current == nil || preferCanonicalDeal(deal, current) {
+				byThread[key] = deal
+			}
+		}
+	}
+	return byThread
+}
+
 func preferCanonicalDeal(candidate, current *models.DealInfo) bool {
 	if current == nil {
 		return true
@@ -292,6 +359,31 @@ func isFuzzyDealMatch(tokensA, tokensB []string) bool {
 	return calculateSimilarity(tokensA, tokensB) >= 0.75
 }
 
+// isRepost reports whether candidate looks like a fresh thread for stored,
+// an active deal (MERGE_REPOSTS). Titles are compared without their store
+// prefix, and since reposts often drop or change the retailer tag, only
+// conflicting non-empty retailers rule a match out.
+func isRepost(candidate, stored *models.DealInfo, threshold float64) bool {
+	if stored.Expired || candidate.Expired {
+		return false
+	}
+	left, right := normalizeRetailerForDedupe(candidate.Retailer), normalizeRetailerForDedupe(stored.Retailer)
+	if left != "" && right != "" && left != right {
+		return false
+	}
+	candidateTokens, storedTokens := repostTitleTokens(candidate), repostTitleTokens(stored)
+	if tokenOverlapCount(candidateTokens, storedTokens) < 3 {
+		return false
+	}
+	return calculateSimilarity(candidateTokens, storedTokens) >= threshold
+}
+
+// repostTitleTokens tokenizes the deal's title alone, without its store
+// prefix or product URL.
+func repostTitleTokens(deal *models.DealInfo) []string {
+	return GenerateSearchTokens(&models.DealInfo{Title: util.StripStorePrefix(cmp.Or(deal.CleanTitle, deal.Title))})
+}
+
 func retailersCompatible(left, right string) bool {
 	left = normalizeRetailerForDedupe(left)
 	right = normalizeRetailerForDedupe(right)
@@ -308,6 +400,7 @@ func normalizeRetailerForDedupe(retailer string) string {
 func (p *DealProcessor) deduplicateDeals(ctx context.Context, scrapedDeals []models.DealInfo, existingDeals map[string]*models.DealInfo, recentDeals []models.DealInfo, logger *slog.Logger) []models.DealInfo {
 	var dedupedScraped []models.DealInfo
 	canonicalRecentByURL := recentDealsByCanonicalURL(recentDeals)
+	recentByThread := recentDealsByThreadKey(recentDeals)
 
 	// Map to keep track of matched scraped deals so we don't process them twice.
 	matchedScrapedIndices := make(map[int]bool)
@@ -319,6 +412,27 @@ func (p *DealProcessor) deduplicateDeals(ctx context.Context, scrapedDeals []mod
 
 		dealA := &scrapedDeals[i]
 
+		// Layer 0: same RFD thread under a new PublishedTimestamp — RFD re-timed
+		// (bumped) the thread, which would otherwise mint a duplicate ID. Keep the
+		// stored timestamp so the record still matches its ID.
+		if stored := recentByThread[threadKey(dealA.PrimaryPostURL())]; stored != nil && stored.DocumentID != dealA.DocumentID {
+			if _, collision := existingDeals[dealA.DocumentID]; !collision {
+				logger.Info("Deal re-timed on RFD, merging with stored record",
+					"title", dealA.Title, "id", stored.DocumentID, "scrapedID", dealA.DocumentID,
+					"storedPublished", stored.PublishedTimestamp, "scrapedPublished", dealA.PublishedTimestamp)
+				dealA.DocumentID = stored.DocumentID
+				dealA.PublishedTimestamp = stored.PublishedTimestamp
+				if len(dealA.Threads) > 0 {
+					dealA.Threads[0].DocumentID = stored.DocumentID
+				}
+				if _, ok := existingDeals[stored.DocumentID]; !ok {
+					existingDeals[stored.DocumentID] = stored
+				}
+				dedupedScraped = append(dedupedScraped, *dealA)
+				continue
+			}
+		}
+
 		if canonical := canonicalRecentByURL[canonicalDealURL(dealA.ActualDealURL)]; canonical != nil && canonical.DocumentID != dealA.DocumentID {
 			logger.Info("Deal deduplicated with canonical product record", "scrapedTitle", dealA.Title, "existingTitle", canonical.Title)
 			dealA.DocumentID = canonical.DocumentID
@@ -359,6 +473,12 @@ func (p *DealProcessor) deduplicateDeals(ctx context.Context, scrapedDeals []mod
 				matchedExisting = rDeal
 				break
 			}
+
+			if p.config != nil && p.config.MergeReposts && isRepost(dealA, rDeal, p.config.RepostSimilarity) {
+				logger.Info("Deal looks like a repost, merging into original", "scrapedTitle", dealA.Title, "originalID", rDeal.DocumentID)
+				matchedExisting = rDeal
+				break
+			}
 		}
 
 		if matchedExisting != nil {
@@ -20,6 +20,12 @@ type DealStore interface {
 	GetAllSubscriptions(ctx context.Context) ([]models.Subscription, error)
 }
 
+// RunHistoryReader is implemented by stores that keep run summaries. A fresh
+// processor reads them to seed the truncated-list guard.
+type RunHistoryReader interface {
+	GetRecentRunSummaries(ctx context.Context, limit int) ([]models.RunSummary, error)
+}
+
 // DealNotifier abstracts the notification layer.
 type DealNotifier interface {
 	Send(ctx context.Context, deal models.DealInfo, subs []models.Subscription) (map[string]string, error)
@@ -28,12 +34,38 @@ type DealNotifier interface {
 	IsHot(deal models.DealInfo) bool
 }
 
+// DealDMSender is implemented by notifiers that can direct-message a deal to
+// a Discord user (WATCH_KEYWORDS).
+type DealDMSender interface {
+	SendDM(ctx context.Context, userID string, deal models.DealInfo) error
+}
+
+// DealBatchSender is implemented by notifiers that can post several deals to
+// a channel as one message (BATCH_EMBEDS). It returns each deal's message ID
+// in order; deals in one message share its ID.
+type DealBatchSender interface {
+	SendBatch(ctx context.Context, channelID string, deals []models.DealInfo) ([]string, error)
+}
+
+// DealSink receives every deal a run creates or updates, after it is saved
+// (DEAL_WEBHOOK_URL). event is "new" or "updated".
+type DealSink interface {
+	SendDeal(ctx context.Context, event string, deal models.DealInfo) error
+}
+
 // DealScraper abstracts the web scraping layer.
 type DealScraper interface {
 	ScrapeDealList(ctx context.Context) ([]models.DealInfo, error)
 	FetchDealDetails(ctx context.Context, deals []*models.DealInfo) models.DealDetailFetchStats
 }
 
+// PagedDealScraper is implemented by scrapers that page through the deal list
+// (RFD_MAX_PAGES). The processor passes the newest stored deal's publish time
+// before each scrape so paging can stop at deals it already has.
+type PagedDealScraper interface {
+	SetPageFloor(t time.Time)
+}
+
 // DealValidator abstracts the validation layer.
 type DealValidator interface {
 	ValidateStruct(s interface{}) error
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/config"
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
+)
+
+// dealLabels returns the labels of every DEAL_LABEL_RULES rule the deal
+// matches, in rule order.
+func dealLabels(rules []config.LabelRule, deal models.DealInfo) []string {
+	var labels []string
+	for _, rule := range rules {
+		if labelRuleMatches(rule, deal) {
+			labels = append(labels, rule.Label)
+		}
+	}
+	return labels
+}
+
+func labelRuleMatches(rule config.LabelRule, deal models.DealInfo) bool {
+	if len(rule.Keywords) > 0 {
+		title := strings.ToLower(deal.Title + " " + deal.CleanTitle)
+		matched := false
+		for _, keyword := range rule.Keywords {
+			keyword = strings.ToLower(strings.TrimSpace(keyword))
+			if keyword != "" && strings.Contains(title, keyword) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(rule.Retailers) > 0 {
+		matched := false
+		for _, retailer := range rule.Retailers {
+			if strings.EqualFold(strings.TrimSpace(retailer), strings.TrimSpace(deal.Retailer)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if rule.MaxPrice > 0 {
+		cents, ok := util.ParsePriceCents(deal.Price)
+		if !ok || float64(cents) > rule.MaxPrice*100 {
+			return false
+		}
+	}
+	return true
+}
+
+// priceAlertRecipients returns the users of every PRICE_ALERT_RULES rule the
+// deal matches: a title keyword at or under the rule's price.
+func priceAlertRecipients(rules []config.PriceAlertRule, deal models.DealInfo) []string {
+	var users []string
+	for _, rule := range rules {
+		if labelRuleMatches(config.LabelRule{Keywords: rule.Keywords, MaxPrice: rule.MaxPrice}, deal) {
+			users = append(users, rule.Users...)
+		}
+	}
+	return users
+}
@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/config"
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+func TestDealLabels(t *testing.T) {
+	rules := []config.LabelRule{
+		{Label: "🔥Clearance", Keywords: []string{"clearance", "liquidation"}},
+		{Label: "💻Tech", Retailers: []string{"Best Buy", "Newegg"}},
+		{Label: "Cheap Tech", Retailers: []string{"best buy"}, MaxPrice: 20},
+	}
+
+	tests := []struct {
+		name string
+		deal models.DealInfo
+		want []string
+	}{
+		{
+			name: "keyword matches case-insensitively",
+			deal: models.DealInfo{Title: "[Walmart] CLEARANCE patio sets", Retailer: "Walmart", Price: "$99"},
+			want: []string{"🔥Clearance"},
+		},
+		{
+			name: "retailer and price rules both match",
+			deal: models.DealInfo{Title: "USB-C cable", Retailer: "Best Buy", Price: "$14.99"},
+			want: []string{"💻Tech", "Cheap Tech"},
+		},
+		{
+			name: "price above max misses that rule only",
+			deal: models.DealInfo{Title: "Monitor", Retailer: "Best Buy", Price: "$249.99"},
+			want: []string{"💻Tech"},
+		},
+		{
+			name: "unparseable price never matches a price rule",
+			deal: models.DealInfo{Title: "Mystery box", Retailer: "Best Buy", Price: "Free w/ purchase"},
+			want: []string{"💻Tech"},
+		},
+		{
+			name: "no rule matches",
+			deal: models.DealInfo{Title: "Groceries", Retailer: "Costco", Price: "$5"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dealLabels(rules, tt.deal); !slices.Equal(got, tt.want) {
+				t.Errorf("dealLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessDeals_StoresLabels(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Clearance blenders", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.LabelRules = []config.LabelRule{{Label: "🔥Clearance", Keywords: []string{"clearance"}}}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 1 || !slices.Equal(notif.sentDeals[0].Labels, []string{"🔥Clearance"}) {
+		t.Fatalf("Expected the sent deal to carry its label, got %+v", notif.sentDeals)
+	}
+	for _, deal := range store.deals {
+		if !slices.Equal(deal.Labels, []string{"🔥Clearance"}) {
+			t.Errorf("stored Labels = %v, want [🔥Clearance]", deal.Labels)
+		}
+	}
+}
+
+func TestPriceAlertRecipients(t *testing.T) {
+	rules := []config.PriceAlertRule{
+		{Keywords: []string{"rtx 4070"}, MaxPrice: 600, Users: []string{"111"}},
+		{Keywords: []string{"4070", "4080"}, MaxPrice: 900, Users: []string{"222"}},
+	}
+
+	tests := []struct {
+		name  string
+		price string
+		want  []string
+	}{
+		{name: "under both thresholds", price: "$579.99", want: []string{"111", "222"}},
+		{name: "over the tighter threshold", price: "$649", want: []string{"222"}},
+		{name: "over every threshold", price: "$999", want: nil},
+		{name: "missing price never matches", price: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deal := models.DealInfo{Title: "[Best Buy] ASUS RTX 4070 Super", Price: tt.price}
+			if got := priceAlertRecipients(rules, deal); !slices.Equal(got, tt.want) {
+				t.Errorf("priceAlertRecipients() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := priceAlertRecipients(rules, models.DealInfo{Title: "RX 7800 XT", Price: "$499"}); got != nil {
+		t.Errorf("priceAlertRecipients() = %v for an unrelated title, want none", got)
+	}
+}
+
+func TestProcessDeals_PriceAlertDMs(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "RTX 4070 $579", Price: "$579", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1},
+			{Title: "RTX 4070 Ti $799", Price: "$799", PostURL: "https://forums.redflagdeals.com/deal-2", PublishedTimestamp: testTime2},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.PriceAlertRules = []config.PriceAlertRule{{Keywords: []string{"rtx 4070"}, MaxPrice: 600, Users: []string{"111"}}}
+	p.config.WatchKeywords = map[string][]string{"4070": {"111"}}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"111:RTX 4070 $579", "111:RTX 4070 Ti $799"}
+	if !slices.Equal(notif.dms, want) {
+		t.Errorf("DMs = %q, want %q (one DM per user per deal)", notif.dms, want)
+	}
+
+	notif.dms = nil
+	p.config.WatchKeywords = nil
+	scraper.deals = []models.DealInfo{{Title: "RTX 4070 $649", Price: "$649", PostURL: "https://forums.redflagdeals.com/deal-3", PublishedTimestamp: testTime2.Add(time.Hour)}}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.dms) != 0 {
+		t.Errorf("DMs = %q, want none for a deal over max_price", notif.dms)
+	}
+}
@@ -2,11 +2,12 @@ package processor
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/url"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/pauljones0/rfd-discord-bot/internal/config"
 	"github.com/pauljones0/rfd-discord-bot/internal/dealtypes"
+	"github.com/pauljones0/rfd-discord-bot/internal/logger"
 	"github.com/pauljones0/rfd-discord-bot/internal/metrics"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
 	"github.com/pauljones0/rfd-discord-bot/internal/util"
@@ -23,6 +25,29 @@ type Processor interface {
 	ProcessDeals(ctx context.Context) error
 }
 
+// ErrPartialRun wraps the error returned when a run finished but some deals
+// failed; the failures are listed in RunResult.Errors and grouped in its
+// message.
+var ErrPartialRun = errors.New("processed with errors")
+
+// ErrTooFewDeals is returned when a list scrape yields fewer valid deals than
+// MIN_EXPECTED_DEALS and MIN_EXPECTED_DEALS_SKIP abandons the run.
+var ErrTooFewDeals = errors.New("too few deals scraped")
+
+// RunResult summarizes one RFD processing run. Skipped counts scraped deals
+// that needed neither a create nor an update; Errors lists per-deal failures
+// that didn't abort the run, one per deal, and ErrorSummary groups them by
+// cause for display; Diff details the saved changes.
+type RunResult struct {
+	Scraped      int // valid deals on the list; 0 when the scrape failed
+	New          int
+	Updated      int
+	Skipped      int
+	Errors       []string
+	ErrorSummary []string
+	Diff         RunDiff
+}
+
 type DealProcessor struct {
 	store          DealStore
 	notifier       DealNotifier
@@ -30,8 +55,16 @@ type DealProcessor struct {
 	validator      DealValidator
 	config         *config.Config
 	aiClient       DealAnalyzer
+	titleCleaner   *util.TitleCleaner
+	priceErrors    *util.PriceErrorDetector
+	availability   *util.AvailabilityDetector
 	updateInterval time.Duration
-	mu             sync.Mutex // prevents overlapping ProcessDeals runs
+	clock          util.Clock
+	alerter        logger.Alerter
+	sinks          []DealSink
+	sinkTimeout    time.Duration // total budget for sink deliveries per run
+	mu             sync.Mutex    // prevents overlapping ProcessDeals runs
+	lastListSize   int           // valid deals the last untruncated run scraped; guarded by mu
 
 	// Title batch queue — accumulates across scrape cycles
 	titleQueue      []models.TitleRequest
@@ -44,7 +77,10 @@ type DealAnalyzer interface {
 	DrainTokens() (int, int)
 }
 
+// New builds a DealProcessor. ai may be nil, in which case titles are cleaned
+// deterministically with cfg.TitleStripPatterns instead of by Gemini.
 func New(store DealStore, n DealNotifier, s DealScraper, v DealValidator, cfg *config.Config, ai DealAnalyzer) *DealProcessor {
+	titleCleaner := newTitleCleaner(cfg.TitleStripPatterns)
 	return &DealProcessor{
 		store:          store,
 		notifier:       n,
@@ -52,22 +88,388 @@ func New(store DealStore, n DealNotifier, s DealScraper, v DealValidator, cfg *c
 		validator:      v,
 		config:         cfg,
 		aiClient:       ai,
+		titleCleaner:   titleCleaner,
+		priceErrors:    newPriceErrorDetector(cfg.PriceErrorPatterns),
+		availability:   newAvailabilityDetector(cfg.YMMVPatterns, cfg.RegionPatterns),
 		updateInterval: cfg.DiscordUpdateInterval,
+		clock:          util.RealClock{},
+		alerter:        logger.LogAlerter{},
+		sinkTimeout:    sinkBudget,
 	}
 }
 
+// SetAlerter routes processor alerts (suspiciously short deal lists) to
+// alerter instead of the log. nil restores logging.
+func (p *DealProcessor) SetAlerter(alerter logger.Alerter) {
+	if alerter == nil {
+		alerter = logger.LogAlerter{}
+	}
+	p.alerter = alerter
+}
+
+// AddSink delivers each saved new or updated deal to sink as well as
+// Discord. Sink failures are logged and don't fail the run.
+func (p *DealProcessor) AddSink(sink DealSink) {
+	p.sinks = append(p.sinks, sink)
+}
+
+// SetClock replaces the processor's time source; tests use util.FakeClock.
+func (p *DealProcessor) SetClock(clock util.Clock) {
+	p.clock = clock
+}
+
+func (p *DealProcessor) now() time.Time {
+	if p.clock == nil {
+		return time.Now()
+	}
+	return p.clock.Now()
+}
+
+func newTitleCleaner(patterns []string) *util.TitleCleaner {
+	if len(patterns) == 0 {
+		patterns = util.DefaultTitleStripPatterns
+	}
+	tc, err := util.NewTitleCleaner(patterns)
+	if err != nil {
+		slog.Warn("Invalid title strip patterns, using defaults", "processor", "rfd", "error", err)
+		tc, _ = util.NewTitleCleaner(util.DefaultTitleStripPatterns)
+	}
+	return tc
+}
+
+func newPriceErrorDetector(patterns []string) *util.PriceErrorDetector {
+	if len(patterns) == 0 {
+		patterns = util.DefaultPriceErrorPatterns
+	}
+	d, err := util.NewPriceErrorDetector(patterns)
+	if err != nil {
+		slog.Warn("Invalid price error patterns, using defaults", "processor", "rfd", "error", err)
+		d, _ = util.NewPriceErrorDetector(util.DefaultPriceErrorPatterns)
+	}
+	return d
+}
+
+func newAvailabilityDetector(ymmvPatterns, regionPatterns []string) *util.AvailabilityDetector {
+	if len(ymmvPatterns) == 0 {
+		ymmvPatterns = util.DefaultYMMVPatterns
+	}
+	if len(regionPatterns) == 0 {
+		regionPatterns = util.DefaultRegionPatterns
+	}
+	d, err := util.NewAvailabilityDetector(ymmvPatterns, regionPatterns)
+	if err != nil {
+		slog.Warn("Invalid availability patterns, using defaults", "processor", "rfd", "error", err)
+		d, _ = util.NewAvailabilityDetector(util.DefaultYMMVPatterns, util.DefaultRegionPatterns)
+	}
+	return d
+}
+
+// applyAvailability sets the deal's YMMV flag and region hint from its title
+// and description, reporting whether either changed. Both are sticky: the
+// description isn't always re-fetched, so a miss doesn't clear them.
+func (p *DealProcessor) applyAvailability(deal *models.DealInfo, description string) bool {
+	ymmv, region := p.availability.Detect(deal.Title, description)
+	changed := false
+	if ymmv && !deal.YMMV {
+		deal.YMMV = true
+		changed = true
+	}
+	if region != "" && region != deal.Region {
+		deal.Region = region
+		changed = true
+	}
+	return changed
+}
+
 // generateDealID creates a stable deal identity based on PublishedTimestamp.
-// This survives title and URL edits by the post author.
 func generateDealID(published time.Time) string {
-	hash := sha256.Sum256([]byte(published.Format(time.RFC3339Nano)))
-	return hex.EncodeToString(hash[:])
+	return models.DealID(published)
+}
+
+func normalizeTitleForID(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// clearlyDifferentDeals reports whether scraped is a separate post from owner,
+// which holds the same timestamp ID. Title edits and thread moves happen on
+// their own, so the thread must be unknown to owner and the title must differ.
+func clearlyDifferentDeals(owner, scraped *models.DealInfo) bool {
+	scrapedKey := threadKey(scraped.PrimaryPostURL())
+	if threadKey(owner.PostURL) == scrapedKey {
+		return false
+	}
+	for _, thread := range owner.Threads {
+		if threadKey(thread.PostURL) == scrapedKey {
+			return false
+		}
+	}
+	return normalizeTitleForID(owner.Title) != normalizeTitleForID(scraped.Title)
+}
+
+// resolveTimestampCollisions gives distinct deals posted in the same second
+// their own IDs. The deal already stored under the timestamp ID (or, on first
+// sight, the first one scraped) keeps it; others get a suffix hashed from
+// their thread key (models.CollisionDealID).
+// Existing records under the suffixed IDs are loaded into existingDeals.
+func (p *DealProcessor) resolveTimestampCollisions(ctx context.Context, deals []models.DealInfo, existingDeals map[string]*models.DealInfo, logger *slog.Logger) error {
+	owners := make(map[string]*models.DealInfo, len(deals))
+	var remapped []string
+	for i := range deals {
+		deal := &deals[i]
+		owner := existingDeals[deal.DocumentID]
+		if owner == nil {
+			owner = owners[deal.DocumentID]
+		}
+		if owner == nil {
+			owners[deal.DocumentID] = deal
+			continue
+		}
+		if !clearlyDifferentDeals(owner, deal) {
+			continue
+		}
+
+		newID := models.CollisionDealID(deal.DocumentID, threadKey(deal.PrimaryPostURL()))
+		logger.Info("Distinct deals share a published timestamp, disambiguating ID",
+			"title", deal.Title, "otherTitle", owner.Title, "baseID", deal.DocumentID, "id", newID)
+		deal.DocumentID = newID
+		if len(deal.Threads) > 0 {
+			deal.Threads[0].DocumentID = newID
+		}
+		remapped = append(remapped, newID)
+	}
+
+	if len(remapped) == 0 {
+		return nil
+	}
+	found, err := p.store.GetDealsByIDs(ctx, remapped)
+	if err != nil {
+		return fmt.Errorf("failed to load disambiguated deals: %w", err)
+	}
+	for id, deal := range found {
+		existingDeals[id] = deal
+	}
+	return nil
+}
+
+// notificationRecoveryWindow bounds how far back RecoverMissingNotifications
+// looks; older deals are stale enough that posting them would be noise.
+const notificationRecoveryWindow = 12 * time.Hour
+
+// RecoverMissingNotifications re-sends recent deals that were stored without
+// any Discord message (e.g. after a Discord outage) and returns how many were
+// recovered. Deals that no subscription is eligible for are left alone.
+func (p *DealProcessor) RecoverMissingNotifications(ctx context.Context) (int, error) {
+	if !p.mu.TryLock() {
+		return 0, fmt.Errorf("deal processing in progress")
+	}
+	defer p.mu.Unlock()
+
+	recent, err := p.store.GetRecentDeals(ctx, notificationRecoveryWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load recent deals: %w", err)
+	}
+	subs, err := p.store.GetAllSubscriptions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	now := p.now()
+	recovered := 0
+	for i := range recent {
+		deal := &recent[i]
+		if ctx.Err() != nil {
+			return recovered, ctx.Err()
+		}
+		if len(deal.DiscordMessageIDs) > 0 {
+			continue
+		}
+		if expiry := deal.ExpiryTime(); !expiry.IsZero() && now.After(expiry) {
+			continue
+		}
+
+		var eligibleSubs []models.Subscription
+		for _, sub := range subs {
+			if p.isDealEligibleForSubscription(*deal, sub) {
+				eligibleSubs = append(eligibleSubs, sub)
+			}
+		}
+		if len(eligibleSubs) == 0 {
+			continue
+		}
+
+		msgIDs, err := p.notifier.Send(ctx, *deal, eligibleSubs)
+		if err != nil {
+			slog.Warn("Failed to recover discord notification", "processor", "rfd", "id", deal.DocumentID, "error", err)
+			continue
+		}
+		if len(msgIDs) == 0 {
+			continue
+		}
+		deal.DiscordMessageIDs = msgIDs
+		deal.DiscordLastUpdatedTime = now
+		deal.DiscordEngagement = engagementTotal(*deal)
+		if err := p.store.UpdateDeal(ctx, *deal); err != nil {
+			return recovered, fmt.Errorf("failed to save recovered deal %s: %w", deal.DocumentID, err)
+		}
+		recovered++
+	}
+
+	slog.Info("Recovered missing discord notifications", "processor", "rfd", "recovered", recovered)
+	return recovered, nil
+}
+
+// embedRefreshWindow bounds how far back RefreshEmbeds re-renders messages.
+const embedRefreshWindow = 24 * time.Hour
+
+// RefreshEmbeds re-renders the Discord messages of recent deals with the
+// current embed format (e.g. after a layout change) and returns how many were
+// updated. Snoozed deals are skipped; the notifier's rate limiter paces edits.
+func (p *DealProcessor) RefreshEmbeds(ctx context.Context) (int, error) {
+	if !p.mu.TryLock() {
+		return 0, fmt.Errorf("deal processing in progress")
+	}
+	defer p.mu.Unlock()
+
+	recent, err := p.store.GetRecentDeals(ctx, embedRefreshWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load recent deals: %w", err)
+	}
+
+	now := p.now()
+	refreshed := 0
+	for _, deal := range recent {
+		if ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+		if len(deal.DiscordMessageIDs) == 0 || now.Before(deal.SnoozeUntil) {
+			continue
+		}
+		if err := p.notifier.Update(ctx, deal); err != nil {
+			slog.Warn("Failed to refresh discord embed", "processor", "rfd", "id", deal.DocumentID, "error", err)
+			continue
+		}
+		refreshed++
+	}
+
+	slog.Info("Refreshed discord embeds", "processor", "rfd", "refreshed", refreshed)
+	return refreshed, nil
+}
+
+// reprocessWindow bounds which stored deals ReprocessRetailer revisits,
+// matching the deduplication lookback.
+const reprocessWindow = 48 * time.Hour
+
+// ReprocessRetailer re-fetches details, re-cleans titles and re-renders the
+// Discord messages of recent deals from one retailer (case-insensitive), e.g.
+// after a prompt or retailer parsing fix, and returns how many were saved.
+// Snoozed deals are saved but not edited; the notifier paces the edits.
+func (p *DealProcessor) ReprocessRetailer(ctx context.Context, retailer string) (int, error) {
+	retailer = strings.TrimSpace(retailer)
+	if retailer == "" {
+		return 0, fmt.Errorf("retailer is required")
+	}
+	if !p.mu.TryLock() {
+		return 0, fmt.Errorf("deal processing in progress")
+	}
+	defer p.mu.Unlock()
+
+	recent, err := p.store.GetRecentDeals(ctx, reprocessWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load recent deals: %w", err)
+	}
+	var deals []*models.DealInfo
+	for i := range recent {
+		if strings.EqualFold(strings.TrimSpace(recent[i].Retailer), retailer) {
+			deals = append(deals, &recent[i])
+		}
+	}
+	if len(deals) == 0 {
+		return 0, nil
+	}
+
+	tracker := metrics.NewTracker("rfd")
+	defer tracker.LogSummary()
+
+	p.scraper.FetchDealDetails(ctx, deals)
+	p.recleanTitles(ctx, deals, tracker)
+
+	reprocessed := 0
+	for _, deal := range deals {
+		if ctx.Err() != nil {
+			return reprocessed, ctx.Err()
+		}
+		deal.DiscountPct = discountPct(*deal)
+		deal.Labels = dealLabels(p.config.LabelRules, *deal)
+		if p.priceErrors.Detect(deal.Title, deal.Comments) {
+			deal.PriceError = true
+			deal.HasBeenHot = true
+		}
+		p.applyAvailability(deal, deal.Description)
+		if len(deal.DiscordMessageIDs) > 0 && !p.now().Before(deal.SnoozeUntil) {
+			if err := p.notifier.Update(ctx, *deal); err != nil {
+				slog.Warn("Failed to re-render reprocessed deal", "processor", "rfd", "id", deal.DocumentID, "error", err)
+			} else {
+				deal.DiscordLastUpdatedTime = p.now()
+			}
+		}
+		if deal.AIProcessed {
+			deal.Description = ""
+			deal.Comments = ""
+			deal.Summary = ""
+		}
+		deal.LastUpdated = p.now()
+		if err := p.store.UpdateDeal(ctx, *deal); err != nil {
+			return reprocessed, fmt.Errorf("failed to save reprocessed deal %s: %w", deal.DocumentID, err)
+		}
+		reprocessed++
+	}
+
+	slog.Info("Reprocessed retailer deals", "processor", "rfd", "retailer", retailer, "reprocessed", reprocessed)
+	return reprocessed, nil
+}
+
+// recleanTitles cleans the deals' titles in one batch, bypassing the
+// cross-run queue; without Gemini the deterministic cleaner is used.
+func (p *DealProcessor) recleanTitles(ctx context.Context, deals []*models.DealInfo, tracker *metrics.Tracker) {
+	if p.aiClient == nil {
+		for _, deal := range deals {
+			deal.CleanTitle = p.titleCleaner.Clean(deal.Title)
+		}
+		return
+	}
+
+	requests := make([]models.TitleRequest, len(deals))
+	for i, deal := range deals {
+		requests[i] = models.TitleRequest{Index: i, Title: deal.Title, Retailer: deal.Retailer, Price: deal.Price}
+	}
+	results, err := p.aiClient.CleanTitles(ctx, requests)
+	inTok, outTok := p.aiClient.DrainTokens()
+	tracker.TrackGeminiCall(inTok, outTok)
+	if err != nil {
+		slog.Warn("Title cleaning failed while reprocessing, keeping existing titles", "processor", "rfd", "error", err)
+		return
+	}
+	for i, deal := range deals {
+		if cleanTitle, ok := results[i]; ok && cleanTitle != "" {
+			deal.CleanTitle = cleanTitle
+			deal.AIProcessed = true
+			tracker.TrackAdProcessed()
+		}
+	}
 }
 
 func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
+	_, err := p.ProcessDealsWithResult(ctx)
+	return err
+}
+
+// ProcessDealsWithResult runs ProcessDeals and also reports what it did.
+func (p *DealProcessor) ProcessDealsWithResult(ctx context.Context) (RunResult, error) {
+	var result RunResult
 	// Prevent overlapping processing runs
 	if !p.mu.TryLock() {
 		slog.Info("ProcessDeals: already in progress, skipping", "processor", "rfd")
-		return nil
+		return result, nil
 	}
 	defer p.mu.Unlock()
 
@@ -84,15 +486,28 @@ func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
 	}
 
 	// 1. Scrape and Validate
+	if paged, ok := p.scraper.(PagedDealScraper); ok {
+		paged.SetPageFloor(newestPublished(recentDeals))
+	}
 	scrapedDeals, err := p.scrapeAndValidate(ctx, logger, tracker)
 	if err != nil {
-		return err
+		return result, err
 	}
+	result.Scraped = len(scrapedDeals)
+	previousListSize, truncated := p.listLooksTruncated(ctx, len(scrapedDeals), logger)
 
 	// 2. Load Existing Deals (Strict ID check)
 	existingDeals, err := p.loadExistingDeals(ctx, scrapedDeals, logger)
 	if err != nil {
-		return err
+		return result, err
+	}
+
+	if err := p.resolveTimestampCollisions(ctx, scrapedDeals, existingDeals, logger); err != nil {
+		return result, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
 	}
 
 	// 3. Deduplicate
@@ -101,7 +516,7 @@ func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
 	// 4. Fetch Details for New/Changed Deals
 	detailStats := p.enrichDealsWithDetails(ctx, validDeals, existingDeals, logger)
 	if rfdDetailFetchUnhealthy(detailStats) {
-		return fmt.Errorf("rfd detail fetch unhealthy: attempted=%d succeeded=%d failed=%d not_found=%d",
+		return result, fmt.Errorf("rfd detail fetch unhealthy: attempted=%d succeeded=%d failed=%d not_found=%d",
 			detailStats.Attempted,
 			detailStats.Succeeded,
 			detailStats.Failed,
@@ -113,6 +528,11 @@ func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
 	// 5. AI Analysis for New Deals
 	p.analyzeDeals(ctx, validDeals, existingDeals, logger, tracker)
 
+	// Nothing has been posted yet, so a cancelled run can stop cleanly here.
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
 	// 6. Fetch Subscriptions
 	subs, err := p.store.GetAllSubscriptions(ctx)
 	if err != nil {
@@ -120,7 +540,18 @@ func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
 	}
 
 	// 7. Notify Discord and Prepare Updates
-	newDeals, updatedDeals, errorMessages := p.processNotificationsAndPrepareUpdates(ctx, validDeals, existingDeals, subs, tracker)
+	storedDeals := snapshotDeals(existingDeals)
+	newDeals, updatedDeals, failures := p.processNotificationsAndPrepareUpdates(ctx, validDeals, existingDeals, subs, tracker)
+	updatedDeals = p.releaseDeferredDeals(ctx, recentDeals, existingDeals, updatedDeals, subs, tracker)
+	result.New, result.Updated = len(newDeals), len(updatedDeals)
+	result.Errors, result.ErrorSummary = failureMessages(failures), summarizeFailures(failures)
+	result.Skipped = max(countDocumentIDs(validDeals)-result.New-result.Updated, 0)
+
+	// Sinks get the deals before their text is cleared for storage below.
+	var sinkNew, sinkUpdated []models.DealInfo
+	if len(p.sinks) > 0 {
+		sinkNew, sinkUpdated = slices.Clone(newDeals), slices.Clone(updatedDeals)
+	}
 
 	// 8. Batch Save
 	// Optimization: Clear large text fields for AI processed deals to save storage
@@ -139,25 +570,144 @@ func (p *DealProcessor) ProcessDeals(ctx context.Context) error {
 			updatedDeals[i].Summary = ""
 		}
 	}
+	result.Diff = buildRunDiff(storedDeals, validDeals, newDeals, updatedDeals)
+	if p.config.LogRunDiff {
+		logRunDiff(logger, result.Diff)
+	}
+
+	if err := ctx.Err(); err != nil {
+		logger.Warn("Context cancelled, skipping batch write", "created", len(newDeals), "updated", len(updatedDeals))
+		return result, err
+	}
+
 	if len(newDeals) > 0 || len(updatedDeals) > 0 {
 		// 8a. Consolidated batch write
 		if err := p.store.BatchWrite(ctx, newDeals, updatedDeals); err != nil {
-			return fmt.Errorf("batch write failed: %w", err)
+			return result, fmt.Errorf("batch write failed: %w", err)
 		}
 		logger.Info("Batch write completed", "created", len(newDeals), "updated", len(updatedDeals))
 	}
+	p.sendToSinks(ctx, logger, sinkNew, sinkUpdated)
 
-	// 9. Cleanup Old Deals
-	if len(newDeals) > 0 {
+	// 9. Cleanup Old Deals. Deals still on RFD but missing from a truncated
+	// list weren't refreshed this run and would be the first ones trimmed.
+	if len(newDeals) > 0 && truncated {
+		logger.Warn("Skipping old deal cleanup: deal list much shorter than last run", "scraped", len(scrapedDeals), "previous", previousListSize)
+	} else if len(newDeals) > 0 {
 		if err := p.store.TrimOldDeals(ctx, p.config.MaxStoredDeals); err != nil {
 			logger.Warn("Failed to trim old deals", "error", err)
 		}
 	}
 
-	if len(errorMessages) > 0 {
-		return fmt.Errorf("processed with errors: %s", strings.Join(errorMessages, "; "))
+	if len(failures) > 0 {
+		return result, fmt.Errorf("%w: %s", ErrPartialRun, strings.Join(result.ErrorSummary, "; "))
 	}
-	return nil
+	return result, nil
+}
+
+// sinkBudget bounds all DealSink deliveries in one run, so a slow webhook
+// can't push a run whose deals are already saved past its deadline.
+const sinkBudget = 30 * time.Second
+
+// sendToSinks hands each saved deal to every DealSink until sinkTimeout runs
+// out; deals left over are skipped for this run.
+func (p *DealProcessor) sendToSinks(ctx context.Context, logger *slog.Logger, newDeals, updatedDeals []models.DealInfo) {
+	if len(p.sinks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.sinkTimeout)
+	defer cancel()
+
+	type sinkEvent struct {
+		event string
+		deal  models.DealInfo
+	}
+	var events []sinkEvent
+	for _, deal := range newDeals {
+		events = append(events, sinkEvent{"new", deal})
+	}
+	for _, deal := range updatedDeals {
+		events = append(events, sinkEvent{"updated", deal})
+	}
+
+	total, sent := len(p.sinks)*len(events), 0
+	for _, sink := range p.sinks {
+		for _, e := range events {
+			if ctx.Err() != nil {
+				logger.Warn("Sink time budget spent, skipping remaining deliveries", "budget", p.sinkTimeout, "skipped", total-sent)
+				return
+			}
+			sent++
+			if err := sink.SendDeal(ctx, e.event, e.deal); err != nil {
+				logger.Warn("Failed to send deal to sink", "event", e.event, "dealID", e.deal.DocumentID, "error", err)
+			}
+		}
+	}
+}
+
+// newestPublished returns the latest publish time among deals, or zero.
+func newestPublished(deals []models.DealInfo) time.Time {
+	var newest time.Time
+	for _, deal := range deals {
+		if deal.PublishedTimestamp.After(newest) {
+			newest = deal.PublishedTimestamp
+		}
+	}
+	return newest
+}
+
+// truncatedListRatio is the fraction of the previous run's list size below
+// which a list is treated as truncated (block page, partial selector break).
+const truncatedListRatio = 0.5
+
+// listBaselineRuns is how many stored run summaries a fresh processor looks
+// back over for its truncated-list baseline: an hour of 5-minute runs.
+const listBaselineRuns = 12
+
+// listLooksTruncated reports whether this run's list size is anomalously small
+// next to the last untruncated run's, along with that size. A truncated size
+// never becomes the baseline, so a block page lasting several runs keeps
+// tripping the guard.
+func (p *DealProcessor) listLooksTruncated(ctx context.Context, size int, logger *slog.Logger) (previous int, truncated bool) {
+	if p.lastListSize == 0 {
+		p.lastListSize = p.storedListSize(ctx, logger)
+	}
+	previous = p.lastListSize
+	truncated = previous > 0 && float64(size) < float64(previous)*truncatedListRatio
+	if !truncated {
+		p.lastListSize = size
+	}
+	return previous, truncated
+}
+
+// storedListSize returns the largest list among the recent stored run
+// summaries, so the guard also covers the first run of a new instance. It
+// returns 0 when the store keeps no run history.
+func (p *DealProcessor) storedListSize(ctx context.Context, logger *slog.Logger) int {
+	history, ok := p.store.(RunHistoryReader)
+	if !ok {
+		return 0
+	}
+	runs, err := history.GetRecentRunSummaries(ctx, listBaselineRuns)
+	if err != nil {
+		logger.Warn("Failed to load run summaries for the truncated-list baseline", "error", err)
+		return 0
+	}
+	size := 0
+	for _, run := range runs {
+		size = max(size, run.Scraped)
+	}
+	return size
+}
+
+// countDocumentIDs counts distinct document IDs, since deduplication can map
+// several scraped deals onto one.
+func countDocumentIDs(deals []models.DealInfo) int {
+	ids := make(map[string]struct{}, len(deals))
+	for _, deal := range deals {
+		ids[deal.DocumentID] = struct{}{}
+	}
+	return len(ids)
 }
 
 // scrapeAndValidate scrapes the deal list and performs initial validation and ID assignment.
@@ -170,8 +720,12 @@ func (p *DealProcessor) scrapeAndValidate(ctx context.Context, logger *slog.Logg
 	logger.Info("Successfully scraped deal list", "count", len(scrapedDeals))
 
 	var validDeals []models.DealInfo
+	incomplete := 0
 	for i := range scrapedDeals {
 		deal := &scrapedDeals[i]
+		if len(deal.ParseWarnings) > 0 {
+			incomplete++
+		}
 
 		// Validate using the validator
 		if err := p.validator.ValidateStruct(deal); err != nil {
@@ -186,9 +740,43 @@ func (p *DealProcessor) scrapeAndValidate(ctx context.Context, logger *slog.Logg
 
 		validDeals = append(validDeals, *deal)
 	}
+	if incomplete > 0 {
+		logger.Warn("Scraped deals with incomplete data", "count", incomplete, "total", len(scrapedDeals))
+	}
+	validDeals, dropped := dedupeScrapedThreads(validDeals)
+	if dropped > 0 {
+		logger.Info("Dropped duplicate list entries for the same thread", "count", dropped)
+	}
+	if err := p.checkMinExpectedDeals(ctx, len(scrapedDeals), len(validDeals)); err != nil {
+		return nil, err
+	}
+	if limit := p.config.MaxDealsPerRun; limit > 0 && len(validDeals) > limit {
+		sort.SliceStable(validDeals, func(i, j int) bool {
+			return validDeals[i].PublishedTimestamp.After(validDeals[j].PublishedTimestamp)
+		})
+		logger.Warn("Truncating scraped deals to MAX_DEALS_PER_RUN", "scraped", len(validDeals), "kept", limit)
+		validDeals = validDeals[:limit]
+	}
 	return validDeals, nil
 }
 
+// checkMinExpectedDeals alerts when a scrape that didn't fail still yields
+// fewer valid deals than MIN_EXPECTED_DEALS, which usually means a selector
+// broke for part of the page. With MIN_EXPECTED_DEALS_SKIP the run stops
+// before the short list reaches storage or Discord.
+func (p *DealProcessor) checkMinExpectedDeals(ctx context.Context, scraped, valid int) error {
+	minDeals := p.config.MinExpectedDeals
+	if minDeals <= 0 || valid >= minDeals {
+		return nil
+	}
+	p.alerter.Alert(ctx, logger.LevelCritical, "RFD list scrape returned suspiciously few deals; selectors may be broken",
+		"processor", "rfd", "scraped", scraped, "valid", valid, "min_expected", minDeals, "skipped", p.config.MinExpectedDealsSkip)
+	if p.config.MinExpectedDealsSkip {
+		return fmt.Errorf("%w: %d valid of %d scraped, expected at least %d", ErrTooFewDeals, valid, scraped, minDeals)
+	}
+	return nil
+}
+
 // loadExistingDeals fetches existing deals from storage corresponding to the valid scraped deals.
 func (p *DealProcessor) loadExistingDeals(ctx context.Context, validDeals []models.DealInfo, logger *slog.Logger) (map[string]*models.DealInfo, error) {
 	var idsToLookup []string
@@ -233,18 +821,18 @@ func (p *DealProcessor) enrichDealsWithDetails(ctx context.Context, validDeals [
 			postChanged ||
 			existing.Title != deal.Title
 
-		if needsDetails {
+		if needsDetails && p.config.DetailFetch != "new-only" {
 			dealsToDetail = append(dealsToDetail, deal)
 		} else {
 			// Unchanged or only metrics changed — copy details from existing so we have them for AI (if needed) or storage
-			deal.ActualDealURL = existing.ActualDealURL
-			deal.ThreadImageURL = existing.ThreadImageURL
-			deal.Description = existing.Description
-			deal.Comments = existing.Comments
-			deal.Summary = existing.Summary
+			copyStoredDetails(deal, existing)
 		}
 	}
 
+	if p.config.DetailFetch == "top-n" && len(dealsToDetail) > p.config.DetailFetchTopN {
+		dealsToDetail = p.limitDetailFetches(dealsToDetail, existingDeals, logger)
+	}
+
 	if len(dealsToDetail) > 0 {
 		logger.Info("Fetching details for deals", "count", len(dealsToDetail))
 		return p.scraper.FetchDealDetails(ctx, dealsToDetail)
@@ -253,6 +841,34 @@ func (p *DealProcessor) enrichDealsWithDetails(ctx context.Context, validDeals [
 	return models.DealDetailFetchStats{}
 }
 
+// limitDetailFetches keeps the DETAIL_FETCH_TOP_N hottest deals, newest first
+// on ties. Stored deals left out keep their stored details; new ones are
+// picked up on a later run.
+func (p *DealProcessor) limitDetailFetches(deals []*models.DealInfo, existingDeals map[string]*models.DealInfo, logger *slog.Logger) []*models.DealInfo {
+	sort.SliceStable(deals, func(i, j int) bool {
+		if hi, hj := hotness(*deals[i]), hotness(*deals[j]); hi != hj {
+			return hi > hj
+		}
+		return deals[i].PublishedTimestamp.After(deals[j].PublishedTimestamp)
+	})
+	limit := p.config.DetailFetchTopN
+	for _, deal := range deals[limit:] {
+		if existing := existingDeals[deal.DocumentID]; existing != nil {
+			copyStoredDetails(deal, existing)
+		}
+	}
+	logger.Info("Limiting detail fetches to the hottest deals", "candidates", len(deals), "limit", limit)
+	return deals[:limit]
+}
+
+func copyStoredDetails(deal, existing *models.DealInfo) {
+	deal.ActualDealURL = existing.ActualDealURL
+	deal.ThreadImageURL = existing.ThreadImageURL
+	deal.Description = existing.Description
+	deal.Comments = existing.Comments
+	deal.Summary = existing.Summary
+}
+
 func rfdDetailFetchUnhealthy(stats models.DealDetailFetchStats) bool {
 	return stats.Attempted >= 3 && stats.Succeeded == 0 && stats.Failed > 0
 }
@@ -260,12 +876,15 @@ func rfdDetailFetchUnhealthy(stats models.DealDetailFetchStats) bool {
 const (
 	titleBatchSize     = 10
 	titleBatchMaxDelay = 5 * time.Minute
+	// titleCleanReserve is kept back from the run deadline when cleaning
+	// titles so notifications and the batch write still fit afterwards.
+	titleCleanReserve = 30 * time.Second
 )
 
 // queueTitleCleaning adds a deal to the title batch queue.
 func (p *DealProcessor) queueTitleCleaning(deal *models.DealInfo, index int) {
 	if p.titleQueueStart.IsZero() {
-		p.titleQueueStart = time.Now()
+		p.titleQueueStart = p.now()
 	}
 	p.titleQueue = append(p.titleQueue, models.TitleRequest{
 		Index:    index,
@@ -283,17 +902,32 @@ func (p *DealProcessor) flushTitleQueue(ctx context.Context, logger *slog.Logger
 	}
 
 	shouldFlush := len(p.titleQueue) >= titleBatchSize ||
-		(!p.titleQueueStart.IsZero() && time.Since(p.titleQueueStart) >= titleBatchMaxDelay)
+		(!p.titleQueueStart.IsZero() && p.now().Sub(p.titleQueueStart) >= titleBatchMaxDelay)
 
 	if !shouldFlush {
 		logger.Info("Title queue not ready to flush", "queued", len(p.titleQueue),
-			"age", time.Since(p.titleQueueStart).Round(time.Second))
+			"age", p.now().Sub(p.titleQueueStart).Round(time.Second))
 		return
 	}
 
+	aiCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		budget := time.Until(deadline) - titleCleanReserve
+		if budget <= 0 {
+			// Dropped deals stay !AIProcessed and are re-queued next run.
+			logger.Warn("Run deadline too close for title cleaning, deals keep raw titles",
+				"skipped", len(p.titleQueue), "remaining", time.Until(deadline).Round(time.Second))
+			p.clearTitleQueue()
+			return
+		}
+		var cancel context.CancelFunc
+		aiCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
 	logger.Info("Flushing title queue", "count", len(p.titleQueue))
 
-	results, err := p.aiClient.CleanTitles(ctx, p.titleQueue)
+	results, err := p.aiClient.CleanTitles(aiCtx, p.titleQueue)
 	inTok, outTok := p.aiClient.DrainTokens()
 	tracker.TrackGeminiCall(inTok, outTok)
 
@@ -309,7 +943,10 @@ func (p *DealProcessor) flushTitleQueue(ctx context.Context, logger *slog.Logger
 		}
 	}
 
-	// Clear the queue
+	p.clearTitleQueue()
+}
+
+func (p *DealProcessor) clearTitleQueue() {
 	p.titleQueue = nil
 	p.titleQueueDeals = nil
 	p.titleQueueStart = time.Time{}
@@ -317,6 +954,15 @@ func (p *DealProcessor) flushTitleQueue(ctx context.Context, logger *slog.Logger
 
 // analyzeDeals queues deals for batch title cleaning. No longer performs warm/hot AI analysis.
 func (p *DealProcessor) analyzeDeals(ctx context.Context, validDeals []models.DealInfo, existingDeals map[string]*models.DealInfo, logger *slog.Logger, tracker *metrics.Tracker) {
+	if p.aiClient == nil {
+		// Without Gemini, fall back to the deterministic cleaner. Title keeps
+		// the original text; the embed prefers CleanTitle.
+		for i := range validDeals {
+			validDeals[i].CleanTitle = p.titleCleaner.Clean(validDeals[i].Title)
+		}
+		return
+	}
+
 	for i := range validDeals {
 		if ctx.Err() != nil {
 			logger.Warn("Context cancelled, stopping title queueing", "remaining", len(validDeals)-i)
@@ -353,10 +999,10 @@ func (p *DealProcessor) analyzeDeals(ctx context.Context, validDeals []models.De
 }
 
 // processNotificationsAndPrepareUpdates sends/updates Discord notifications and prepares lists for DB persistence.
-func (p *DealProcessor) processNotificationsAndPrepareUpdates(ctx context.Context, validDeals []models.DealInfo, existingDeals map[string]*models.DealInfo, subs []models.Subscription, tracker *metrics.Tracker) ([]models.DealInfo, []models.DealInfo, []string) {
+func (p *DealProcessor) processNotificationsAndPrepareUpdates(ctx context.Context, validDeals []models.DealInfo, existingDeals map[string]*models.DealInfo, subs []models.Subscription, tracker *metrics.Tracker) ([]models.DealInfo, []models.DealInfo, []dealFailure) {
 	var newDeals []models.DealInfo
 	var updatedDeals []models.DealInfo
-	var errorMessages []string
+	var failures []dealFailure
 
 	// We need to group validDeals by document ID because deduplication might map multiple
 	// scraped deals to the same ID.
@@ -365,7 +1011,14 @@ func (p *DealProcessor) processNotificationsAndPrepareUpdates(ctx context.Contex
 		groupedDeals[deal.DocumentID] = append(groupedDeals[deal.DocumentID], deal)
 	}
 
-	for documentID, dealsGroup := range groupedDeals {
+	var batch *newDealBatch
+	if sender, ok := p.notifier.(DealBatchSender); ok && p.config.BatchEmbeds {
+		batch = &newDealBatch{sender: sender}
+	}
+
+	notified := 0
+	for _, documentID := range p.notifyOrder(groupedDeals) {
+		dealsGroup := groupedDeals[documentID]
 		if ctx.Err() != nil {
 			slog.Warn("Context cancelled, stopping notification processing", "processor", "rfd")
 			break
@@ -381,22 +1034,128 @@ func (p *DealProcessor) processNotificationsAndPrepareUpdates(ctx context.Contex
 			}
 
 			baseDeal := &liveDealsGroup[0]
-			if err := p.processNewDeal(ctx, baseDeal, liveDealsGroup, &newDeals, subs, tracker); err != nil {
+			capReached := p.config.MaxNotifyPerRun > 0 && notified >= p.config.MaxNotifyPerRun
+			sent, err := p.processNewDeal(ctx, baseDeal, liveDealsGroup, capReached, &newDeals, subs, batch, tracker)
+			if err != nil {
 				slog.Error("Failed to process new deal", "processor", "rfd", "title", baseDeal.Title, "error", err)
-				errorMessages = append(errorMessages, fmt.Sprintf("new deal error %s: %v", baseDeal.Title, err))
+				failures = append(failures, dealFailure{kind: "new deal", subject: baseDeal.Title, err: err})
+			}
+			if sent {
+				notified++
 			}
 		} else {
 			if err := p.processExistingDeal(ctx, existing, dealsGroup, &updatedDeals, subs); err != nil {
 				slog.Error("Failed to process existing deal", "processor", "rfd", "id", documentID, "error", err)
-				errorMessages = append(errorMessages, fmt.Sprintf("existing deal error %s: %v", documentID, err))
+				failures = append(failures, dealFailure{kind: "existing deal", subject: documentID, err: err})
 			}
 		}
 	}
-	return newDeals, updatedDeals, errorMessages
+	if batch != nil {
+		p.sendNewDealBatch(ctx, batch, newDeals, tracker)
+	}
+	return newDeals, updatedDeals, failures
+}
+
+// batchEmbedLimit is how many deals go in one BATCH_EMBEDS message, Discord's
+// cap on embeds per message.
+const batchEmbedLimit = 10
+
+// newDealBatch collects a run's new deals for BATCH_EMBEDS. Each entry is an
+// index into the run's newDeals and the channels the deal is eligible for.
+type newDealBatch struct {
+	sender  DealBatchSender
+	entries []batchEntry
 }
 
-func (p *DealProcessor) processNewDeal(ctx context.Context, dealToSave *models.DealInfo, scrapedDuplicates []models.DealInfo, newDeals *[]models.DealInfo, subs []models.Subscription, tracker *metrics.Tracker) error {
-	dealToSave.LastUpdated = time.Now()
+type batchEntry struct {
+	deal int
+	subs []models.Subscription
+}
+
+// sendNewDealBatch posts the batched deals channel by channel, at most
+// batchEmbedLimit per message, and records each deal's message ID and embed
+// position so later updates edit only its embed. As with Send, a failed
+// message is logged and its deals are stored without that channel's ID.
+func (p *DealProcessor) sendNewDealBatch(ctx context.Context, batch *newDealBatch, newDeals []models.DealInfo, tracker *metrics.Tracker) {
+	var channels []string
+	byChannel := make(map[string][]int)
+	for _, entry := range batch.entries {
+		for _, sub := range entry.subs {
+			if _, ok := byChannel[sub.ChannelID]; !ok {
+				channels = append(channels, sub.ChannelID)
+			}
+			byChannel[sub.ChannelID] = append(byChannel[sub.ChannelID], entry.deal)
+		}
+	}
+
+	for _, channelID := range channels {
+		for chunk := range slices.Chunk(byChannel[channelID], batchEmbedLimit) {
+			deals := make([]models.DealInfo, len(chunk))
+			for i, idx := range chunk {
+				deals[i] = newDeals[idx]
+			}
+			msgIDs, err := batch.sender.SendBatch(ctx, channelID, deals)
+			if err != nil {
+				slog.Warn("Failed to send batched deals", "processor", "rfd", "channel", channelID, "deals", len(chunk), "error", err)
+			}
+			for i, msgID := range msgIDs {
+				if msgID == "" || i >= len(chunk) {
+					continue
+				}
+				deal := &newDeals[chunk[i]]
+				if deal.DiscordMessageIDs == nil {
+					deal.DiscordMessageIDs = make(map[string]string)
+				}
+				if deal.DiscordEmbedIndex == nil {
+					deal.DiscordEmbedIndex = make(map[string]int)
+				}
+				deal.DiscordMessageIDs[channelID] = msgID
+				deal.DiscordEmbedIndex[channelID] = i
+			}
+			if len(msgIDs) > 0 && msgIDs[0] != "" {
+				tracker.TrackDiscordMessage()
+			}
+		}
+	}
+}
+
+// notifyOrder returns the grouped document IDs in the order they should be
+// sent. "oldest" keeps chronology; "hottest" sends the most engaging deal last
+// so it lands at the bottom of the channel.
+func (p *DealProcessor) notifyOrder(groupedDeals map[string][]models.DealInfo) []string {
+	ids := make([]string, 0, len(groupedDeals))
+	for id := range groupedDeals {
+		ids = append(ids, id)
+	}
+	hottest := p.config.NotifyOrder == "hottest"
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := groupedDeals[ids[i]][0], groupedDeals[ids[j]][0]
+		if hottest {
+			if ha, hb := hotness(a), hotness(b); ha != hb {
+				return ha < hb
+			}
+		}
+		if !a.PublishedTimestamp.Equal(b.PublishedTimestamp) {
+			return a.PublishedTimestamp.Before(b.PublishedTimestamp)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// hotness weighs comments over likes, matching the notifier's heat score.
+func hotness(deal models.DealInfo) int {
+	likes, comments, _ := deal.Stats()
+	return max(likes, 0) + 2*max(comments, 0)
+}
+
+// processNewDeal stores a new deal and sends it to Discord, reporting whether
+// it was posted to at least one channel. Once capReached is set (MAX_NOTIFY_PER_RUN), the deal is stored
+// flagged NotifyCapped instead and is never posted.
+func (p *DealProcessor) processNewDeal(ctx context.Context, dealToSave *models.DealInfo, scrapedDuplicates []models.DealInfo, capReached bool, newDeals *[]models.DealInfo, subs []models.Subscription, batch *newDealBatch, tracker *metrics.Tracker) (bool, error) {
+	dealToSave.LastUpdated = p.now()
+	dealToSave.FirstSeen = dealToSave.LastUpdated
+	dealToSave.RecordSnapshot(dealToSave.LastUpdated, p.config.SnapshotInterval)
 
 	// Merge any scraped duplicates' threads into this new deal
 	for i := 1; i < len(scrapedDuplicates); i++ {
@@ -405,10 +1164,39 @@ func (p *DealProcessor) processNewDeal(ctx context.Context, dealToSave *models.D
 		}
 	}
 	p.sortThreads(dealToSave)
+	dealToSave.DiscountPct = discountPct(*dealToSave)
+	dealToSave.Labels = dealLabels(p.config.LabelRules, *dealToSave)
 
-	// Initialize rank tracking
+	// Initialize rank tracking; a likely price error is treated as hot.
+	// Expired deals are never flagged, whatever the comments say.
+	dealToSave.PriceError = !dealToSave.Expired && p.priceErrors.Detect(dealToSave.Title, dealToSave.Comments)
+	p.applyAvailability(dealToSave, dealToSave.Description)
 	dealToSave.HasBeenWarm = p.notifier.IsWarm(*dealToSave)
-	dealToSave.HasBeenHot = p.notifier.IsHot(*dealToSave)
+	dealToSave.HasBeenHot = dealToSave.PriceError || p.notifier.IsHot(*dealToSave)
+
+	if p.isBlockedAuthor(*dealToSave) {
+		// Stored without a Discord post so later runs treat it as already seen.
+		slog.Info("Skipping notification for blocked author", "processor", "rfd", "author", dealToSave.AuthorName, "title", dealToSave.Title)
+		tracker.TrackDealBlocked()
+		*newDeals = append(*newDeals, *dealToSave)
+		return false, nil
+	}
+
+	p.sendWatchDMs(ctx, *dealToSave)
+
+	if p.holdForQuietHours(*dealToSave) {
+		slog.Info("Deferring notification during QUIET_HOURS", "processor", "rfd", "title", dealToSave.Title)
+		dealToSave.NotifyDeferred = true
+		*newDeals = append(*newDeals, *dealToSave)
+		return false, nil
+	}
+
+	if capReached {
+		slog.Info("Skipping notification past MAX_NOTIFY_PER_RUN", "processor", "rfd", "limit", p.config.MaxNotifyPerRun, "title", dealToSave.Title)
+		dealToSave.NotifyCapped = true
+		*newDeals = append(*newDeals, *dealToSave)
+		return false, nil
+	}
 
 	// Filter subscriptions for this new deal
 	var eligibleSubs []models.Subscription
@@ -418,17 +1206,31 @@ func (p *DealProcessor) processNewDeal(ctx context.Context, dealToSave *models.D
 		}
 	}
 
+	dealToSave.DiscordLastUpdatedTime = p.now()
+	dealToSave.DiscordEngagement = engagementTotal(*dealToSave)
+	if batch != nil {
+		// Posted with the rest of the run's new deals by sendNewDealBatch.
+		tracker.TrackDealFound()
+		*newDeals = append(*newDeals, *dealToSave)
+		if len(eligibleSubs) == 0 {
+			return false, nil
+		}
+		batch.entries = append(batch.entries, batchEntry{deal: len(*newDeals) - 1, subs: eligibleSubs})
+		return true, nil
+	}
+
 	// Send to Discord to get ID
 	msgIDs, err := p.notifier.Send(ctx, *dealToSave, eligibleSubs)
 	if err != nil {
-		return err
+		return false, err
 	}
 	dealToSave.DiscordMessageIDs = msgIDs
-	dealToSave.DiscordLastUpdatedTime = time.Now()
 	tracker.TrackDiscordMessage()
 	tracker.TrackDealFound()
 	*newDeals = append(*newDeals, *dealToSave)
-	return nil
+	// A deal no channel wants yet (e.g. cold with only hot subscribers) was
+	// not posted, so it must not use up MAX_NOTIFY_PER_RUN.
+	return len(eligibleSubs) > 0, nil
 }
 
 func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *models.DealInfo, scrapedDuplicates []models.DealInfo, updatedDeals *[]models.DealInfo, subs []models.Subscription) error {
@@ -436,6 +1238,7 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 	changed := deduplicateThreadsByKey(existing)
 
 	removedDeadThreads := removeNotFoundThreads(existing, scrapedDuplicates)
+	threadCount := len(existing.Threads)
 	if removedDeadThreads {
 		changed = true
 	}
@@ -452,8 +1255,15 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 		}
 	}
 
+	contentChanged := removedDeadThreads || len(existing.Threads) != threadCount
+	if existing.AuthorName == "" && scrapedBase.AuthorName != "" {
+		existing.AuthorName = scrapedBase.AuthorName
+		changed = true
+	}
+
 	if p.dealChanged(existing, &scrapedBase) {
 		changed = true
+		contentChanged = true
 		// Merge changes into existing
 		existing.Title = scrapedBase.Title
 		existing.PostURL = scrapedBase.PostURL
@@ -462,6 +1272,7 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 		existing.Price = scrapedBase.Price
 		existing.OriginalPrice = scrapedBase.OriginalPrice
 		existing.Savings = scrapedBase.Savings
+		existing.DiscountPct = discountPct(scrapedBase)
 		existing.ThreadImageURL = scrapedBase.ThreadImageURL
 		existing.PublishedTimestamp = scrapedBase.PublishedTimestamp
 		existing.ActualDealURL = scrapedBase.ActualDealURL
@@ -470,13 +1281,45 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 		existing.Summary = scrapedBase.Summary
 		existing.SearchTokens = scrapedBase.SearchTokens
 
-		// AI fields
-		if scrapedBase.AIProcessed {
+		// AI fields (or the deterministic clean title when AI is disabled)
+		if scrapedBase.AIProcessed || (p.aiClient == nil && scrapedBase.CleanTitle != "") {
 			existing.CleanTitle = scrapedBase.CleanTitle
 			existing.AIProcessed = scrapedBase.AIProcessed
 		}
 	}
 
+	if labels := dealLabels(p.config.LabelRules, *existing); !slices.Equal(labels, existing.Labels) {
+		existing.Labels = labels
+		changed = true
+		contentChanged = true
+	}
+
+	// A thread moved to the Expired Hot Deals forum stays expired.
+	if !existing.Expired && slices.ContainsFunc(scrapedDuplicates, func(d models.DealInfo) bool { return d.Expired }) {
+		existing.Expired = true
+		changed = true
+		contentChanged = true
+	}
+
+	if !existing.PriceError && !existing.Expired && p.priceErrors.Detect(existing.Title, scrapedBase.Comments) {
+		existing.PriceError = true
+		existing.HasBeenHot = true
+		changed = true
+		contentChanged = true
+	}
+
+	if p.applyAvailability(existing, scrapedBase.Description) {
+		changed = true
+		contentChanged = true
+	}
+
+	// Deals held back during QUIET_HOURS go out through the missing-channel
+	// send below once the window ends (or the deal turns hot).
+	if existing.NotifyDeferred && !p.holdForQuietHours(*existing) {
+		existing.NotifyDeferred = false
+		changed = true
+	}
+
 	if !changed {
 		return nil
 	}
@@ -494,7 +1337,8 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 		existing.HasBeenHot = true
 	}
 
-	existing.LastUpdated = time.Now()
+	existing.LastUpdated = p.now()
+	existing.RecordSnapshot(existing.LastUpdated, p.config.SnapshotInterval)
 
 	// Handle Discord multi-channel updates
 	// 1. Send to newly added channels that don't have this deal yet, OR channels where the deal just reached their threshold
@@ -519,22 +1363,33 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 				for channelID, msgID := range newMsgIDs {
 					existing.DiscordMessageIDs[channelID] = msgID
 				}
-				existing.DiscordLastUpdatedTime = time.Now()
+				existing.DiscordLastUpdatedTime = p.now()
 			} else {
 				slog.Warn("Failed to send missing discord notifications", "processor", "rfd", "id", existing.DocumentID, "error", err)
 			}
 		}
 	}
 
+	// 1b. Repost an old deal that heated up again; its last message is long
+	// buried and past the edit window.
+	if p.reheated(*existing) {
+		p.repostReheatedDeal(ctx, existing, subs)
+	}
+
 	// 2. Update existing channels
 	// Discord error 30046: "Maximum number of edits to messages older than 1 hour reached."
 	// The exact threshold is undocumented, but one developer hit it after ~3,600 edits
 	// over 10 hours on a single message (editing every 10 seconds).
 	// At our edit frequency (~1 per minute per deal), 2 hours is well within safe limits.
 	// See: https://github.com/discord/discord-api-docs/issues/4413
-	if len(existing.DiscordMessageIDs) > 0 && time.Since(existing.DiscordLastUpdatedTime) >= p.updateInterval && time.Since(existing.PublishedTimestamp) < 2*time.Hour {
+	// Skipped entirely when NOTIFY_UPDATES=false, while the deal is snoozed, and
+	// for engagement-only changes below UPDATE_MIN_DELTA; the changes are still
+	// persisted below.
+	if !p.config.SuppressDealUpdates && !p.now().Before(existing.SnoozeUntil) && len(existing.DiscordMessageIDs) > 0 && p.now().Sub(existing.DiscordLastUpdatedTime) >= p.updateInterval && p.now().Sub(existing.PublishedTimestamp) < 2*time.Hour &&
+		(contentChanged || p.engagementDeltaMet(*existing)) {
 		if err := p.notifier.Update(ctx, *existing); err == nil {
-			existing.DiscordLastUpdatedTime = time.Now()
+			existing.DiscordLastUpdatedTime = p.now()
+			existing.DiscordEngagement = engagementTotal(*existing)
 		} else {
 			slog.Warn("Failed to update discord notifications", "processor", "rfd", "id", existing.DocumentID, "error", err)
 		}
@@ -544,6 +1399,82 @@ func (p *DealProcessor) processExistingDeal(ctx context.Context, existing *model
 	return nil
 }
 
+// engagementTotal sums the primary thread's likes, comments, and views.
+func engagementTotal(deal models.DealInfo) int {
+	likes, comments, views := deal.Stats()
+	return likes + comments + views
+}
+
+// engagementDeltaMet reports whether engagement moved enough since the last
+// Discord send/edit to justify another edit.
+func (p *DealProcessor) engagementDeltaMet(deal models.DealInfo) bool {
+	delta := engagementTotal(deal) - deal.DiscordEngagement
+	if delta < 0 {
+		delta = -delta
+	}
+	if p.config.UpdateMinDeltaPct > 0 {
+		base := max(deal.DiscordEngagement, 1)
+		return delta*100 >= p.config.UpdateMinDeltaPct*base
+	}
+	return delta >= p.config.UpdateMinDelta
+}
+
+// reheated reports whether REHEAT_TTL should repost the deal: it is hot and
+// gaining engagement again after at least the TTL without a Discord post or
+// edit.
+func (p *DealProcessor) reheated(deal models.DealInfo) bool {
+	ttl := p.config.ReheatTTL
+	if ttl <= 0 || deal.Expired || len(deal.DiscordMessageIDs) == 0 || p.now().Before(deal.SnoozeUntil) {
+		return false
+	}
+	return p.now().Sub(deal.DiscordLastUpdatedTime) >= ttl &&
+		engagementTotal(deal) > deal.DiscordEngagement &&
+		p.notifier.IsHot(deal)
+}
+
+// repostReheatedDeal sends a reheated deal as a new "back on the hot list"
+// post to every eligible channel; later edits go to the new messages.
+func (p *DealProcessor) repostReheatedDeal(ctx context.Context, deal *models.DealInfo, subs []models.Subscription) {
+	var eligibleSubs []models.Subscription
+	for _, sub := range subs {
+		if p.isDealEligibleForSubscription(*deal, sub) {
+			eligibleSubs = append(eligibleSubs, sub)
+		}
+	}
+	if len(eligibleSubs) == 0 {
+		return
+	}
+
+	reposted := *deal
+	reposted.ReheatedAt = p.now()
+	msgIDs, err := p.notifier.Send(ctx, reposted, eligibleSubs)
+	if err != nil {
+		slog.Warn("Failed to repost reheated deal", "processor", "rfd", "id", deal.DocumentID, "error", err)
+		return
+	}
+	slog.Info("Reposted deal back on the hot list", "processor", "rfd", "id", deal.DocumentID, "title", deal.Title, "silent_for", p.now().Sub(deal.DiscordLastUpdatedTime))
+	deal.ReheatedAt = reposted.ReheatedAt
+	maps.Copy(deal.DiscordMessageIDs, msgIDs)
+	for channelID := range msgIDs {
+		delete(deal.DiscordEmbedIndex, channelID) // the repost is a message of its own
+	}
+	deal.DiscordLastUpdatedTime = p.now()
+	deal.DiscordEngagement = engagementTotal(*deal)
+}
+
+// discountPct computes the percent off from the scraped price strings.
+func discountPct(deal models.DealInfo) int {
+	current, ok := util.ParsePriceCents(deal.Price)
+	if !ok {
+		return 0
+	}
+	original, ok := util.ParsePriceCents(deal.OriginalPrice)
+	if !ok {
+		return 0
+	}
+	return util.PercentOff(current, original)
+}
+
 func liveScrapedDeals(scrapedDeals []models.DealInfo) []models.DealInfo {
 	liveDeals := make([]models.DealInfo, 0, len(scrapedDeals))
 	for _, deal := range scrapedDeals {
@@ -897,8 +1828,158 @@ func (p *DealProcessor) sortThreads(deal *models.DealInfo) {
 }
 
 func (p *DealProcessor) isDealEligibleForSubscription(deal models.DealInfo, sub models.Subscription) bool {
+	if deal.NotifyCapped || deal.NotifyDeferred || p.isBlockedAuthor(deal) || !p.routesToChannel(deal, sub.ChannelID) {
+		return false
+	}
+	// Expired deals are an archive: only EXPIRED_DEALS_CHANNEL gets them,
+	// whatever their heat, and that channel gets nothing else.
+	expiredChannel := p.config.ExpiredDealsChannel
+	if deal.Expired {
+		return expiredChannel != "" && sub.ChannelID == expiredChannel
+	}
+	if expiredChannel != "" && sub.ChannelID == expiredChannel {
+		return false
+	}
 	isTech := deal.Category != "" && util.IsTechCategory(deal.Category)
+	if p.matchesAlwaysNotify(deal) {
+		// Always-notify deals skip the heat gate but still respect the tech filter.
+		return dealtypes.RFDEligible(sub.DealType, isTech, true, true)
+	}
 	isWarm := deal.HasBeenWarm || p.notifier.IsWarm(deal)
 	isHot := deal.HasBeenHot || p.notifier.IsHot(deal)
 	return dealtypes.RFDEligible(sub.DealType, isTech, isWarm, isHot)
 }
+
+// holdForQuietHours reports whether QUIET_HOURS holds back a deal's first
+// post: the window is active and the deal is neither hot nor a price error.
+func (p *DealProcessor) holdForQuietHours(deal models.DealInfo) bool {
+	if !p.config.QuietHours.Contains(p.now()) {
+		return false
+	}
+	return !deal.PriceError && !deal.HasBeenHot && !p.notifier.IsHot(deal)
+}
+
+// releaseDeferredDeals posts deals held back during QUIET_HOURS that this
+// run did not scrape (e.g. they dropped off the list overnight) once the
+// window is over; scraped ones are released by processExistingDeal. Released
+// deals are appended to updatedDeals so the cleared flag is saved.
+func (p *DealProcessor) releaseDeferredDeals(ctx context.Context, recentDeals []models.DealInfo, existingDeals map[string]*models.DealInfo, updatedDeals []models.DealInfo, subs []models.Subscription, tracker *metrics.Tracker) []models.DealInfo {
+	now := p.now()
+	for _, deal := range recentDeals {
+		if !deal.NotifyDeferred || existingDeals[deal.DocumentID] != nil || p.holdForQuietHours(deal) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		deal.NotifyDeferred = false
+		if expiry := deal.ExpiryTime(); expiry.IsZero() || !now.After(expiry) {
+			var eligibleSubs []models.Subscription
+			for _, sub := range subs {
+				if p.isDealEligibleForSubscription(deal, sub) {
+					eligibleSubs = append(eligibleSubs, sub)
+				}
+			}
+			if len(eligibleSubs) > 0 {
+				msgIDs, err := p.notifier.Send(ctx, deal, eligibleSubs)
+				if err != nil {
+					// Left deferred so the next run tries again.
+					slog.Warn("Failed to send deferred deal", "processor", "rfd", "id", deal.DocumentID, "error", err)
+					continue
+				}
+				deal.DiscordMessageIDs = msgIDs
+				deal.DiscordLastUpdatedTime = now
+				deal.DiscordEngagement = engagementTotal(deal)
+				tracker.TrackDiscordMessage()
+			}
+		}
+		slog.Info("Released deal deferred by QUIET_HOURS", "processor", "rfd", "id", deal.DocumentID, "title", deal.Title, "channels", len(deal.DiscordMessageIDs))
+		updatedDeals = append(updatedDeals, deal)
+	}
+	return updatedDeals
+}
+
+// matchesAlwaysNotify reports whether the deal's title or retailer contains
+// one of the configured always-notify keywords.
+func (p *DealProcessor) matchesAlwaysNotify(deal models.DealInfo) bool {
+	if len(p.config.AlwaysNotifyKeywords) == 0 {
+		return false
+	}
+	haystack := strings.ToLower(deal.Title + " " + deal.CleanTitle + " " + deal.Retailer)
+	for _, keyword := range p.config.AlwaysNotifyKeywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && strings.Contains(haystack, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchRecipients returns the users whose WATCH_KEYWORDS or
+// PRICE_ALERT_RULES match the deal, each once, in ID order.
+func (p *DealProcessor) watchRecipients(deal models.DealInfo) []string {
+	if (len(p.config.WatchKeywords) == 0 && len(p.config.PriceAlertRules) == 0) || deal.Expired {
+		return nil
+	}
+	haystack := strings.ToLower(deal.Title + " " + deal.CleanTitle + " " + deal.Retailer)
+	var users []string
+	for keyword, ids := range p.config.WatchKeywords {
+		if strings.Contains(haystack, strings.ToLower(keyword)) {
+			users = append(users, ids...)
+		}
+	}
+	users = append(users, priceAlertRecipients(p.config.PriceAlertRules, deal)...)
+	sort.Strings(users)
+	return slices.Compact(users)
+}
+
+// sendWatchDMs direct-messages a new deal to its watchers. Failures are
+// logged; they never hold up the channel notification.
+func (p *DealProcessor) sendWatchDMs(ctx context.Context, deal models.DealInfo) {
+	users := p.watchRecipients(deal)
+	if len(users) == 0 {
+		return
+	}
+	sender, ok := p.notifier.(DealDMSender)
+	if !ok {
+		slog.Warn("WATCH_KEYWORDS matched but the notifier cannot send DMs", "processor", "rfd", "title", deal.Title)
+		return
+	}
+	for _, userID := range users {
+		if err := sender.SendDM(ctx, userID, deal); err != nil {
+			slog.Warn("Failed to DM watched deal", "processor", "rfd", "user", userID, "title", deal.Title, "error", err)
+		}
+	}
+}
+
+// routesToChannel applies CATEGORY_CHANNELS: a deal in a routed category only
+// goes to that category's channel, and other deals skip the reserved channels.
+func (p *DealProcessor) routesToChannel(deal models.DealInfo, channelID string) bool {
+	routes := p.config.CategoryChannels
+	if len(routes) == 0 {
+		return true
+	}
+	if routed, ok := routes[strings.ToLower(strings.TrimSpace(deal.Category))]; ok {
+		return routed == channelID
+	}
+	for _, reserved := range routes {
+		if reserved == channelID {
+			return false
+		}
+	}
+	return true
+}
+
+// isBlockedAuthor reports whether the deal was posted by a BLOCK_AUTHORS user.
+func (p *DealProcessor) isBlockedAuthor(deal models.DealInfo) bool {
+	author := strings.TrimSpace(deal.AuthorName)
+	if author == "" {
+		return false
+	}
+	for _, blocked := range p.config.BlockAuthors {
+		if strings.EqualFold(strings.TrimSpace(blocked), author) {
+			return true
+		}
+	}
+	return false
+}
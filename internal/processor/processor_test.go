@@ -3,14 +3,19 @@ package processor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/pauljones0/rfd-discord-bot/internal/config"
+	"github.com/pauljones0/rfd-discord-bot/internal/dealtypes"
 	"github.com/pauljones0/rfd-discord-bot/internal/metrics"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 	"github.com/pauljones0/rfd-discord-bot/internal/validator"
 )
 
@@ -22,6 +27,12 @@ type mockStore struct {
 	updateErr   error
 	trimCalled  bool
 	updateCount int
+	subs        []models.Subscription // overrides the default test subscription when set
+	runs        []models.RunSummary   // stored run summaries, newest first
+
+	getByIDCalls  int
+	getByIDsCalls int
+	batchWrites   int
 }
 
 func newMockStore() *mockStore {
@@ -29,6 +40,7 @@ func newMockStore() *mockStore {
 }
 
 func (m *mockStore) GetDealByID(_ context.Context, id string) (*models.DealInfo, error) {
+	m.getByIDCalls++
 	deal, ok := m.deals[id]
 	if !ok {
 		return nil, nil
@@ -60,6 +72,7 @@ func (m *mockStore) UpdateDeal(_ context.Context, deal models.DealInfo) error {
 }
 
 func (m *mockStore) GetDealsByIDs(_ context.Context, ids []string) (map[string]*models.DealInfo, error) {
+	m.getByIDsCalls++
 	result := make(map[string]*models.DealInfo)
 	for _, id := range ids {
 		if deal, ok := m.deals[id]; ok {
@@ -78,12 +91,17 @@ func (m *mockStore) GetRecentDeals(_ context.Context, duration time.Duration) ([
 	return recent, nil
 }
 
+func (m *mockStore) GetRecentRunSummaries(_ context.Context, limit int) ([]models.RunSummary, error) {
+	return m.runs[:min(limit, len(m.runs))], nil
+}
+
 func (m *mockStore) TrimOldDeals(_ context.Context, _ int) error {
 	m.trimCalled = true
 	return nil
 }
 
 func (m *mockStore) BatchWrite(ctx context.Context, creates []models.DealInfo, updates []models.DealInfo) error {
+	m.batchWrites++
 	for _, deal := range creates {
 		if err := m.TryCreateDeal(ctx, deal); err != nil {
 			return err
@@ -102,6 +120,9 @@ func (m *mockStore) Ping(ctx context.Context) error {
 }
 
 func (m *mockStore) GetAllSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	if m.subs != nil {
+		return m.subs, nil
+	}
 	// Return a default test subscription so the notifier actually sends
 	return []models.Subscription{
 		{GuildID: "guild1", ChannelID: "channel1"},
@@ -114,6 +135,10 @@ type mockNotifier struct {
 	sendErr    error
 	nextMsgID  string
 	updateErr  error
+	notHot     bool                       // makes IsHot report every deal as not hot
+	hot        func(models.DealInfo) bool // overrides notHot when set
+	dms        []string                   // "userID:title" per SendDM call
+	batches    []int                      // deals per SendBatch call
 }
 
 func newMockNotifier() *mockNotifier {
@@ -132,6 +157,20 @@ func (m *mockNotifier) Send(_ context.Context, deal models.DealInfo, subs []mode
 	return res, nil
 }
 
+func (m *mockNotifier) SendBatch(_ context.Context, channelID string, deals []models.DealInfo) ([]string, error) {
+	m.batches = append(m.batches, len(deals))
+	msgIDs := make([]string, len(deals))
+	for i := range deals {
+		msgIDs[i] = fmt.Sprintf("batch-%d-%s", len(m.batches), channelID)
+	}
+	return msgIDs, nil
+}
+
+func (m *mockNotifier) SendDM(_ context.Context, userID string, deal models.DealInfo) error {
+	m.dms = append(m.dms, userID+":"+deal.Title)
+	return nil
+}
+
 func (m *mockNotifier) Update(_ context.Context, deal models.DealInfo) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -147,7 +186,10 @@ func (m *mockNotifier) IsWarm(deal models.DealInfo) bool {
 }
 
 func (m *mockNotifier) IsHot(deal models.DealInfo) bool {
-	return true
+	if m.hot != nil {
+		return m.hot(deal)
+	}
+	return !m.notHot
 }
 
 type mockScraper struct {
@@ -156,6 +198,11 @@ type mockScraper struct {
 	fetchedDetails []*models.DealInfo
 	mutateDetails  func([]*models.DealInfo)
 	detailStats    models.DealDetailFetchStats
+	pageFloor      time.Time
+}
+
+func (m *mockScraper) SetPageFloor(t time.Time) {
+	m.pageFloor = t
 }
 
 func (m *mockScraper) ScrapeDealList(_ context.Context) ([]models.DealInfo, error) {
@@ -250,6 +297,51 @@ func TestProcessDeals_NewDeal(t *testing.T) {
 	}
 }
 
+func TestProcessDeals_SameProductURLNotifiesOnce(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	resolveTo := func(url string) func([]*models.DealInfo) {
+		return func(deals []*models.DealInfo) {
+			for _, d := range deals {
+				d.ActualDealURL = url
+			}
+		}
+	}
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Sony WH-1000XM5 $298", PostURL: "https://forums.redflagdeals.com/sony-headphones-111111", PublishedTimestamp: testTime1},
+			{Title: "[Amazon] Noise cancelling cans on sale", PostURL: "https://forums.redflagdeals.com/amazon-cans-222222", PublishedTimestamp: testTime1.Add(time.Minute)},
+		},
+		mutateDetails: resolveTo("https://www.amazon.ca/dp/B09XS7JWHH"),
+	}
+
+	p := newTestProcessor(store, notif, scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+	if len(notif.sentDeals) != 1 {
+		t.Fatalf("Expected 1 notification for two threads with the same product URL, got %d", len(notif.sentDeals))
+	}
+	if len(store.deals) != 1 {
+		t.Errorf("Expected duplicates linked into 1 stored deal, got %d", len(store.deals))
+	}
+
+	// A later thread pointing at the same product links to the stored deal.
+	scraper.deals = []models.DealInfo{
+		{Title: "XM5 headphones lowest price", PostURL: "https://forums.redflagdeals.com/xm5-lowest-333333", PublishedTimestamp: testTime2},
+	}
+	scraper.mutateDetails = resolveTo("https://www.amazon.ca/Sony-WH-1000XM5/dp/B09XS7JWHH?th=1")
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+	if len(notif.sentDeals) != 1 {
+		t.Errorf("Expected no new notification for a recent product URL, got %d total", len(notif.sentDeals))
+	}
+	if len(store.deals) != 1 {
+		t.Errorf("Expected the later thread linked to the stored deal, got %d deals", len(store.deals))
+	}
+}
+
 func TestProcessDeals_SkipsNewDealWhenDetail404s(t *testing.T) {
 	store := newMockStore()
 	notif := newMockNotifier()
@@ -292,7 +384,7 @@ func TestProcessDeals_SkipsInvalidDeal(t *testing.T) {
 		deals: []models.DealInfo{
 			{Title: "", PostURL: "", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},      // empty title and URL
 			{Title: "   ", PostURL: "  ", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{}}}, // whitespace only
-			{Title: "Valid", PostURL: "https://rfd.com/deal", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
+			{Title: "Valid", PostURL: "https://forums.redflagdeals.com/deal", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
 		},
 	}
 
@@ -312,8 +404,8 @@ func TestProcessDeals_SkipsZeroTimestamp(t *testing.T) {
 	notif := newMockNotifier()
 	scraper := &mockScraper{
 		deals: []models.DealInfo{
-			{Title: "No Timestamp", PostURL: "https://rfd.com/deal-no-ts", Threads: []models.ThreadContext{{}}},
-			{Title: "Has Timestamp", PostURL: "https://rfd.com/deal-ts", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
+			{Title: "No Timestamp", PostURL: "https://forums.redflagdeals.com/deal-no-ts", Threads: []models.ThreadContext{{}}},
+			{Title: "Has Timestamp", PostURL: "https://forums.redflagdeals.com/deal-ts", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
 		},
 	}
 
@@ -345,86 +437,1468 @@ func TestProcessDeals_UpdateExistingDeal(t *testing.T) {
 
 	p := newTestProcessor(store, notif, scraper)
 
-	// First run: creates the deal
-	err := p.ProcessDeals(context.Background())
-	if err != nil {
-		t.Fatalf("First ProcessDeals() error = %v", err)
+	// First run: creates the deal
+	err := p.ProcessDeals(context.Background())
+	if err != nil {
+		t.Fatalf("First ProcessDeals() error = %v", err)
+	}
+
+	// Now update the scraper with changed data (same timestamp = same deal)
+	scraper.deals = []models.DealInfo{
+		{
+			Title:              "Updated Title",
+			PostURL:            "https://forums.redflagdeals.com/deal-1",
+			PublishedTimestamp: testTime1,
+			Threads:            []models.ThreadContext{{LikeCount: 20, PostURL: "https://forums.redflagdeals.com/deal-1"}},
+		},
+	}
+	store.updateCount = 0
+
+	err = p.ProcessDeals(context.Background())
+	if err != nil {
+		t.Fatalf("Second ProcessDeals() error = %v", err)
+	}
+
+	if store.updateCount == 0 {
+		t.Error("Expected UpdateDeal to be called for changed deal")
+	}
+}
+
+func TestProcessDeals_URLChangedDealsUpdated(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+
+	// First run: create the deal
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Great Deal", PostURL: "https://forums.redflagdeals.com/deal-old-url", ActualDealURL: "https://amazon.ca/old-url", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-old-url"}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify 1 deal created
+	if len(store.deals) != 1 {
+		t.Fatalf("Expected 1 deal, got %d", len(store.deals))
+	}
+
+	// Second run: same timestamp but URL changed (user edited the post)
+	scraper.deals = []models.DealInfo{
+		{Title: "Great Deal", PostURL: "https://forums.redflagdeals.com/deal-new-url", ActualDealURL: "https://amazon.ca/new-url", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-new-url"}}},
+	}
+	store.updateCount = 0
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Should still be 1 deal (updated, not duplicated)
+	if len(store.deals) != 1 {
+		t.Errorf("Expected 1 deal (URL change should update, not duplicate), got %d", len(store.deals))
+	}
+	if store.updateCount == 0 {
+		t.Error("Expected UpdateDeal to be called when ActualDealURL changed")
+	}
+
+	// Verify the stored deal has the new URL
+	for _, deal := range store.deals {
+		if deal.ActualDealURL != "https://amazon.ca/new-url" {
+			t.Errorf("Expected ActualDealURL to be updated, got %q", deal.ActualDealURL)
+		}
+	}
+}
+
+func TestProcessDeals_MergeRepostsIntoOriginal(t *testing.T) {
+	originalURL := "https://forums.redflagdeals.com/sony-wh-1000xm5-headphones-2806520/"
+	repostURL := "https://forums.redflagdeals.com/sony-wh-1000xm5-wireless-headphones-2806999/"
+	run := func(mergeReposts bool) (*mockStore, *mockNotifier) {
+		store := newMockStore()
+		store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "main", DealType: dealtypes.RFDAll}}
+		notif := newMockNotifier()
+		scraper := &mockScraper{deals: []models.DealInfo{
+			{Title: "[Costco] Sony WH-1000XM5 Headphones $298", Retailer: "Costco", PostURL: originalURL, PublishedTimestamp: testTime1,
+				Threads: []models.ThreadContext{{PostURL: originalURL, LikeCount: 5}}},
+		}}
+		p := newTestProcessor(store, notif, scraper)
+		p.config.MergeReposts = mergeReposts
+		p.config.RepostSimilarity = 0.9
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		// The repost drops the store tag and adds a word.
+		scraper.deals = []models.DealInfo{
+			{Title: "Sony WH-1000XM5 Wireless Headphones $298", PostURL: repostURL, PublishedTimestamp: testTime2,
+				Threads: []models.ThreadContext{{PostURL: repostURL, LikeCount: 30}}},
+		}
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return store, notif
+	}
+
+	store, notif := run(false)
+	if len(notif.sentDeals) != 2 {
+		t.Fatalf("without MERGE_REPOSTS sent %d notifications, want the repost posted too", len(notif.sentDeals))
+	}
+
+	store, notif = run(true)
+	if len(notif.sentDeals) != 1 {
+		t.Fatalf("sent %d notifications, want the repost merged instead of posted", len(notif.sentDeals))
+	}
+	if len(store.deals) != 1 {
+		t.Fatalf("got %d stored deals, want the repost folded into the original", len(store.deals))
+	}
+	original := store.deals[generateDealID(testTime1)]
+	if original == nil || len(original.Threads) != 2 {
+		t.Fatalf("original = %+v, want it to carry both threads", original)
+	}
+	if likes, _, _, _ := original.EngagementStats(); likes < 30 {
+		t.Errorf("original likes = %d, want the repost's engagement included", likes)
+	}
+}
+
+func TestProcessDeals_RetimedThreadMergesIntoStoredDeal(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	postURL := "https://forums.redflagdeals.com/great-deal-2806520/"
+
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Great Deal", PostURL: postURL, PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: postURL, LikeCount: 2}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sent := len(notif.sentDeals)
+
+	// RFD bumps the thread: same URL (with a new slug), new timestamp.
+	bumpedURL := "https://forums.redflagdeals.com/great-deal-now-cheaper-2806520/"
+	scraper.deals = []models.DealInfo{
+		{Title: "Great Deal", PostURL: bumpedURL, PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{PostURL: bumpedURL, LikeCount: 9}}},
+	}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.deals) != 1 {
+		t.Fatalf("got %d stored deals, want 1 (re-timed thread should update, not duplicate)", len(store.deals))
+	}
+	deal := store.deals[generateDealID(testTime1)]
+	if deal == nil {
+		t.Fatalf("deal not stored under its original ID %s", generateDealID(testTime1))
+	}
+	if likes, _, _ := deal.Stats(); likes != 9 {
+		t.Errorf("likes = %d, want 9 from the re-timed scrape", likes)
+	}
+	if !deal.PublishedTimestamp.Equal(testTime1) {
+		t.Errorf("PublishedTimestamp = %v, want the original %v", deal.PublishedTimestamp, testTime1)
+	}
+	if len(notif.sentDeals) != sent {
+		t.Errorf("re-timed thread sent %d new notifications, want 0", len(notif.sentDeals)-sent)
+	}
+}
+
+func TestProcessDeals_SnapshotsAtIntervalAndCapsHistory(t *testing.T) {
+	store := newMockStore()
+	clock := util.NewFakeClock(testTime1.Add(time.Minute))
+	postURL := "https://forums.redflagdeals.com/deal-1"
+	scrape := func(likes int) []models.DealInfo {
+		return []models.DealInfo{{Title: "Deal", Price: "$10", PostURL: postURL, PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: postURL, LikeCount: likes}}}}
+	}
+	scraper := &mockScraper{deals: scrape(1)}
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+	p.SetClock(clock)
+	p.config.SnapshotInterval = 30 * time.Minute
+	history := func() []models.DealSnapshot { return store.deals[generateDealID(testTime1)].History }
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Runs every 10 minutes with rising likes: only every third run is a
+	// full interval after the last snapshot.
+	for run := 1; run <= 3; run++ {
+		clock.Advance(10 * time.Minute)
+		scraper.deals = scrape(1 + run)
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := history(); len(got) != 2 || got[0].Likes != 1 || got[1].Likes != 4 || !got[1].At.Equal(clock.Now()) {
+		t.Fatalf("History = %+v, want snapshots at 1 like and, 30m later, 4 likes", got)
+	}
+
+	// An interval passing without any change adds nothing.
+	clock.Advance(time.Hour)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(history()); got != 2 {
+		t.Fatalf("len(History) = %d after an unchanged run, want 2", got)
+	}
+
+	for likes := 5; likes < models.MaxDealHistory+10; likes++ {
+		clock.Advance(30 * time.Minute)
+		scraper.deals = scrape(likes)
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := history()
+	if len(got) != models.MaxDealHistory {
+		t.Fatalf("len(History) = %d, want capped at %d", len(got), models.MaxDealHistory)
+	}
+	if first, last := got[0].Likes, got[len(got)-1].Likes; last != models.MaxDealHistory+9 || first != last-models.MaxDealHistory+1 {
+		t.Errorf("History spans %d..%d likes, want the %d newest snapshots", first, last, models.MaxDealHistory)
+	}
+}
+
+func TestProcessDeals_TitleChangedDealsUpdated(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Original Title", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Second run: same timestamp but title changed
+	scraper.deals = []models.DealInfo{
+		{Title: "Updated Title - Price Drop!", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+	}
+	store.updateCount = 0
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.deals) != 1 {
+		t.Errorf("Expected 1 deal (title change should update, not duplicate), got %d", len(store.deals))
+	}
+	if store.updateCount == 0 {
+		t.Error("Expected UpdateDeal to be called when Title changed")
+	}
+
+	for _, deal := range store.deals {
+		if deal.Title != "Updated Title - Price Drop!" {
+			t.Errorf("Expected Title to be updated, got %q", deal.Title)
+		}
+	}
+}
+
+func TestProcessDeals_NotifyUpdatesToggle(t *testing.T) {
+	for _, suppress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("suppress=%v", suppress), func(t *testing.T) {
+			store := newMockStore()
+			notif := newMockNotifier()
+			published := time.Now().Add(-10 * time.Minute)
+
+			scraper := &mockScraper{
+				deals: []models.DealInfo{
+					{Title: "Original Title", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+				},
+			}
+			p := newTestProcessor(store, notif, scraper)
+			p.config.SuppressDealUpdates = suppress
+			p.updateInterval = 0
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			for _, deal := range store.deals {
+				deal.DiscordMessageIDs = map[string]string{"channel1": "msg-1"}
+			}
+
+			scraper.deals = []models.DealInfo{
+				{Title: "Updated Title - Price Drop!", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+			}
+			store.updateCount = 0
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if store.updateCount == 0 {
+				t.Error("Expected the changed deal to be persisted")
+			}
+			if suppress && len(notif.updatedIDs) != 0 {
+				t.Errorf("Expected no Discord edits with updates disabled, got %v", notif.updatedIDs)
+			}
+			if !suppress && len(notif.updatedIDs) == 0 {
+				t.Error("Expected a Discord edit with updates enabled")
+			}
+		})
+	}
+}
+
+func TestProcessDeals_NotifyOrder(t *testing.T) {
+	scraped := func() []models.DealInfo {
+		deal := func(title, slug string, published time.Time, likes int) models.DealInfo {
+			postURL := "https://forums.redflagdeals.com/" + slug
+			return models.DealInfo{Title: title, PostURL: postURL, PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: postURL, LikeCount: likes}}}
+		}
+		// List order is newest first, as on RFD.
+		return []models.DealInfo{
+			deal("Newest Deal", "newest-3", testTime1.Add(2*time.Minute), 5),
+			deal("Middle Deal", "middle-2", testTime1.Add(time.Minute), 40),
+			deal("Oldest Deal", "oldest-1", testTime1, 1),
+		}
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"oldest", []string{"Oldest Deal", "Middle Deal", "Newest Deal"}},
+		{"hottest", []string{"Oldest Deal", "Newest Deal", "Middle Deal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			notif := newMockNotifier()
+			p := newTestProcessor(newMockStore(), notif, &mockScraper{deals: scraped()})
+			p.config.NotifyOrder = tt.order
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, d := range notif.sentDeals {
+				got = append(got, d.Title)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("send order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessDeals_UpdateIntervalWithFakeClock(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier()
+	clock := util.NewFakeClock(testTime1.Add(5 * time.Minute))
+	postURL := "https://forums.redflagdeals.com/deal-1"
+	dealWithTitle := func(title string) []models.DealInfo {
+		return []models.DealInfo{{Title: title, PostURL: postURL, PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: postURL}}}}
+	}
+	scraper := &mockScraper{deals: dealWithTitle("Original Title")}
+	p := newTestProcessor(store, notif, scraper)
+	p.SetClock(clock)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 1 {
+		t.Fatalf("Expected the new deal to be sent, got %d sends", len(notif.sentDeals))
+	}
+
+	// One minute later the 10m update interval hasn't elapsed.
+	clock.Advance(time.Minute)
+	scraper.deals = dealWithTitle("Edited Title")
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.updatedIDs) != 0 {
+		t.Fatalf("Expected no Discord edit inside the update interval, got %v", notif.updatedIDs)
+	}
+
+	clock.Advance(10 * time.Minute)
+	scraper.deals = dealWithTitle("Edited Title - Price Drop!")
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.updatedIDs) == 0 {
+		t.Fatal("Expected a Discord edit once the update interval elapsed")
+	}
+	for _, deal := range store.deals {
+		if !deal.DiscordLastUpdatedTime.Equal(clock.Now()) {
+			t.Errorf("DiscordLastUpdatedTime = %v, want the fake clock's %v", deal.DiscordLastUpdatedTime, clock.Now())
+		}
+	}
+}
+
+func TestProcessDealsWithResult_DiffListsChangedFields(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	postURL := "https://forums.redflagdeals.com/deal-1"
+	scrapedDeal := func(title, price string, likes int) []models.DealInfo {
+		return []models.DealInfo{{Title: title, Price: price, PostURL: postURL, PublishedTimestamp: testTime1,
+			Threads: []models.ThreadContext{{PostURL: postURL, LikeCount: likes}}}}
+	}
+	scraper := &mockScraper{deals: scrapedDeal("Headphones", "$99", 5)}
+	p := newTestProcessor(store, notif, scraper)
+
+	result, err := p.ProcessDealsWithResult(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := generateDealID(testTime1)
+	if !slices.Equal(result.Diff.New, []string{id}) || len(result.Diff.Changed) != 0 {
+		t.Fatalf("first run diff = %+v, want only new deal %s", result.Diff, id)
+	}
+
+	scraper.deals = scrapedDeal("Headphones", "$79", 12)
+	result, err = p.ProcessDealsWithResult(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Diff.New) != 0 || len(result.Diff.Changed) != 1 {
+		t.Fatalf("second run diff = %+v, want one changed deal", result.Diff)
+	}
+	change := result.Diff.Changed[0]
+	if change.ID != id {
+		t.Errorf("changed ID = %q, want %q", change.ID, id)
+	}
+	for _, want := range []string{"Price", "Threads.LikeCount"} {
+		if !slices.Contains(change.Fields, want) {
+			t.Errorf("changed fields = %v, want %s", change.Fields, want)
+		}
+	}
+	for _, unwanted := range []string{"Title", "LastUpdated", "ExpiresAt", "Threads.CommentCount"} {
+		if slices.Contains(change.Fields, unwanted) {
+			t.Errorf("changed fields = %v, should not include %s", change.Fields, unwanted)
+		}
+	}
+}
+
+func TestBuildRunDiff_RemovedWhenAllThreadsGone(t *testing.T) {
+	before := map[string]models.DealInfo{
+		"gone": {DocumentID: "gone", Title: "Gone", Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/gone-1"}}},
+		"live": {DocumentID: "live", Title: "Live", Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/live-2"}}},
+	}
+	scraped := []models.DealInfo{
+		{DocumentID: "gone", Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/gone-1", NotFound: true}}},
+		{DocumentID: "live", Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/live-2"}}},
+	}
+
+	diff := buildRunDiff(before, scraped, nil, nil)
+	if !slices.Equal(diff.Removed, []string{"gone"}) {
+		t.Errorf("removed = %v, want [gone]", diff.Removed)
+	}
+}
+
+func TestProcessDeals_CancelledContextStopsBeforeNotifying(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "main", DealType: dealtypes.RFDAll}}
+	notifier := newMockNotifier()
+	p := newTestProcessor(store, notifier, &mockScraper{deals: []models.DealInfo{
+		{Title: "Headphones $99", PostURL: "https://forums.redflagdeals.com/headphones-1", PublishedTimestamp: testTime1},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.ProcessDeals(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ProcessDeals() error = %v, want context.Canceled", err)
+	}
+	if len(notifier.sentDeals) != 0 {
+		t.Errorf("sent %d deals after cancellation, want none", len(notifier.sentDeals))
+	}
+	if store.batchWrites != 0 || len(store.deals) != 0 {
+		t.Errorf("batch writes = %d, stored = %d after cancellation, want none", store.batchWrites, len(store.deals))
+	}
+}
+
+func TestProcessDeals_ExpiredDealsStoredButNotNotifiedToMainChannel(t *testing.T) {
+	scrapedDeals := func() []models.DealInfo {
+		return []models.DealInfo{
+			{Title: "Headphones $99", PostURL: "https://forums.redflagdeals.com/headphones-1", PublishedTimestamp: testTime1},
+			{Title: "Price error 65in TV $49", PostURL: "https://forums.redflagdeals.com/tv-2", PublishedTimestamp: testTime1.Add(time.Minute), Expired: true},
+		}
+	}
+	activeID, expiredID := generateDealID(testTime1), generateDealID(testTime1.Add(time.Minute))
+
+	t.Run("no expired channel", func(t *testing.T) {
+		store := newMockStore()
+		store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "main", DealType: dealtypes.RFDAll}}
+		p := newTestProcessor(store, newMockNotifier(), &mockScraper{deals: scrapedDeals()})
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		expired := store.deals[expiredID]
+		if expired == nil || !expired.Expired {
+			t.Fatalf("expired deal = %+v, want it stored and marked expired", expired)
+		}
+		if len(expired.DiscordMessageIDs) != 0 {
+			t.Errorf("expired deal posted to %v, want no notification", expired.DiscordMessageIDs)
+		}
+		if expired.PriceError {
+			t.Error("expired deal flagged as a price error, want never flagged")
+		}
+		if _, ok := store.deals[activeID].DiscordMessageIDs["main"]; !ok {
+			t.Error("active deal not posted to the main channel")
+		}
+	})
+
+	t.Run("expired channel", func(t *testing.T) {
+		store := newMockStore()
+		store.subs = []models.Subscription{
+			{GuildID: "guild1", ChannelID: "main", DealType: dealtypes.RFDAll},
+			{GuildID: "guild1", ChannelID: "archive", DealType: dealtypes.RFDAll},
+		}
+		p := newTestProcessor(store, newMockNotifier(), &mockScraper{deals: scrapedDeals()})
+		p.config.ExpiredDealsChannel = "archive"
+		if err := p.ProcessDeals(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := store.deals[expiredID].DiscordMessageIDs; len(got) != 1 || got["archive"] == "" {
+			t.Errorf("expired deal posted to %v, want only the archive channel", got)
+		}
+		if got := store.deals[activeID].DiscordMessageIDs; len(got) != 1 || got["main"] == "" {
+			t.Errorf("active deal posted to %v, want only the main channel", got)
+		}
+	})
+}
+
+func TestProcessDeals_MaxNotifyPerRun(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier()
+	var deals []models.DealInfo
+	for i := 0; i < 20; i++ {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	scraper := &mockScraper{deals: deals}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.MaxNotifyPerRun = 5
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 5 {
+		t.Fatalf("Expected exactly 5 notifications, got %d", len(notif.sentDeals))
+	}
+	if len(store.deals) != 20 {
+		t.Fatalf("Expected all 20 deals stored, got %d", len(store.deals))
+	}
+	capped := 0
+	for _, deal := range store.deals {
+		if deal.NotifyCapped {
+			capped++
+		}
+	}
+	if capped != 15 {
+		t.Errorf("Expected 15 deals flagged NotifyCapped, got %d", capped)
+	}
+
+	// Capped deals are never picked up by the missing-channel catch-up.
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 5 {
+		t.Errorf("Expected capped deals to stay unposted on the next run, got %d sends", len(notif.sentDeals))
+	}
+}
+
+func TestProcessDeals_MaxNotifyPerRunCountsOnlyPostedDeals(t *testing.T) {
+	var deals []models.DealInfo
+	for i, title := range []string{"Cold 1", "Cold 2", "Cold 3", "Hot 1", "Cold 4", "Hot 2"} {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              title,
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	for _, batchEmbeds := range []bool{false, true} {
+		t.Run(fmt.Sprintf("batch=%v", batchEmbeds), func(t *testing.T) {
+			store := newMockStore()
+			store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "hot-channel", DealType: dealtypes.RFDHot}}
+			notif := newMockNotifier()
+			notif.hot = func(deal models.DealInfo) bool { return strings.HasPrefix(deal.Title, "Hot") }
+			p := newTestProcessor(store, notif, &mockScraper{deals: deals})
+			p.config.MaxNotifyPerRun = 2
+			p.config.BatchEmbeds = batchEmbeds
+
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			for _, deal := range store.deals {
+				if deal.NotifyCapped {
+					t.Errorf("%q flagged NotifyCapped; cold deals no channel wants must not count toward the cap", deal.Title)
+				}
+				posted := deal.DiscordMessageIDs["hot-channel"] != ""
+				if wantPosted := strings.HasPrefix(deal.Title, "Hot"); posted != wantPosted {
+					t.Errorf("%q posted = %v, want %v", deal.Title, posted, wantPosted)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessDeals_SnoozedDealSkipsEdits(t *testing.T) {
+	const postURL = "https://forums.redflagdeals.com/deal-1"
+	store := newMockStore()
+	notif := newMockNotifier()
+	published := time.Now().Add(-10 * time.Minute)
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Original Title", PostURL: postURL, PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: postURL}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	p.updateInterval = 0
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, deal := range store.deals {
+		deal.DiscordMessageIDs = map[string]string{"channel1": "msg-1"}
+		deal.SnoozeUntil = time.Now().Add(time.Hour)
+	}
+
+	scraper.deals = []models.DealInfo{
+		{Title: "Updated Title - Price Drop!", PostURL: postURL, PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: postURL}}},
+	}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notif.updatedIDs) != 0 {
+		t.Errorf("Expected no Discord edits while snoozed, got %v", notif.updatedIDs)
+	}
+	for _, deal := range store.deals {
+		if deal.Title != "Updated Title - Price Drop!" {
+			t.Errorf("Expected the content change to persist while snoozed, got title %q", deal.Title)
+		}
+		deal.SnoozeUntil = time.Now().Add(-time.Minute)
+	}
+
+	scraper.deals[0].Title = "Updated Again"
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.updatedIDs) == 0 {
+		t.Error("Expected Discord edits to resume once the snooze expired")
+	}
+}
+
+func TestProcessDeals_UpdateMinDelta(t *testing.T) {
+	const postURL = "https://forums.redflagdeals.com/deal-1"
+	published := time.Now().Add(-10 * time.Minute)
+	scrapedWithLikes := func(likes int) []models.DealInfo {
+		return []models.DealInfo{
+			{Title: "Deal", PostURL: postURL, PublishedTimestamp: published, Threads: []models.ThreadContext{{PostURL: postURL, LikeCount: likes}}},
+		}
+	}
+
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{deals: scrapedWithLikes(5)}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.UpdateMinDelta = 10
+	p.updateInterval = 0
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, deal := range store.deals {
+		deal.DiscordMessageIDs = map[string]string{"channel1": "msg-1"}
+		deal.DiscordEngagement = 5
+	}
+
+	// Small change: persisted, but no Discord edit.
+	scraper.deals = scrapedWithLikes(8)
+	store.updateCount = 0
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.updateCount == 0 {
+		t.Error("Expected the small engagement change to be persisted")
+	}
+	if len(notif.updatedIDs) != 0 {
+		t.Fatalf("Expected no Discord edit below UPDATE_MIN_DELTA, got %v", notif.updatedIDs)
+	}
+	for _, deal := range store.deals {
+		if likes, _, _ := deal.Stats(); likes != 8 {
+			t.Errorf("Expected stored likes 8, got %d", likes)
+		}
+	}
+
+	// Large change relative to the last edit: Discord is edited.
+	scraper.deals = scrapedWithLikes(20)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.updatedIDs) == 0 {
+		t.Fatal("Expected a Discord edit once the engagement delta reached UPDATE_MIN_DELTA")
+	}
+	for _, deal := range store.deals {
+		if deal.DiscordEngagement != 20 {
+			t.Errorf("Expected DiscordEngagement 20 after edit, got %d", deal.DiscordEngagement)
+		}
+	}
+}
+
+func TestEngagementDeltaMet_Percentage(t *testing.T) {
+	p := newTestProcessor(newMockStore(), newMockNotifier(), &mockScraper{})
+	p.config.UpdateMinDeltaPct = 10
+
+	deal := models.DealInfo{DiscordEngagement: 100, Threads: []models.ThreadContext{{LikeCount: 105}}}
+	if p.engagementDeltaMet(deal) {
+		t.Error("Expected a 5% change to stay below a 10% gate")
+	}
+	deal.Threads[0].LikeCount = 110
+	if !p.engagementDeltaMet(deal) {
+		t.Error("Expected a 10% change to meet a 10% gate")
+	}
+}
+
+func TestProcessDeals_BlockedAuthorNotNotified(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Spam Deal", AuthorName: "SpamBot", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+			{Title: "Real Deal", AuthorName: "regular", PostURL: "https://forums.redflagdeals.com/deal-2", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-2"}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.BlockAuthors = []string{"spambot"}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notif.sentDeals) != 1 || notif.sentDeals[0].Title != "Real Deal" {
+		t.Fatalf("Expected only the unblocked deal to be sent, got %d sends", len(notif.sentDeals))
+	}
+	if len(store.deals) != 2 {
+		t.Errorf("Expected blocked deal to still be stored, got %d deals", len(store.deals))
+	}
+	if p.isDealEligibleForSubscription(models.DealInfo{AuthorName: "SPAMBOT"}, models.Subscription{DealType: dealtypes.RFDAll}) {
+		t.Error("Expected blocked author to be ineligible for every subscription")
+	}
+}
+
+func TestProcessDeals_CategoryChannelRouting(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{
+		{GuildID: "guild1", ChannelID: "computers-channel", DealType: dealtypes.RFDAll},
+		{GuildID: "guild1", ChannelID: "default-channel", DealType: dealtypes.RFDAll},
+	}
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "GPU Deal", Category: "Computers & Electronics", PostURL: "https://forums.redflagdeals.com/gpu-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/gpu-1"}}},
+			{Title: "Grocery Deal", Category: "Groceries", PostURL: "https://forums.redflagdeals.com/food-2", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/food-2"}}},
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.CategoryChannels = map[string]string{"computers & electronics": "computers-channel"}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	channels := make(map[string][]string)
+	for _, deal := range store.deals {
+		for channelID := range deal.DiscordMessageIDs {
+			channels[deal.Title] = append(channels[deal.Title], channelID)
+		}
+	}
+	if got := channels["GPU Deal"]; len(got) != 1 || got[0] != "computers-channel" {
+		t.Errorf("GPU Deal posted to %v, want only computers-channel", got)
+	}
+	if got := channels["Grocery Deal"]; len(got) != 1 || got[0] != "default-channel" {
+		t.Errorf("Grocery Deal posted to %v, want only default-channel", got)
+	}
+}
+
+func TestProcessDeals_BatchesStoreReadsAndWrites(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	var deals []models.DealInfo
+	for i := 0; i < 10; i++ {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL, LikeCount: 1}},
+		})
+	}
+	scraper := &mockScraper{deals: deals}
+	p := newTestProcessor(store, notif, scraper)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The second run sees every deal as existing, with changed stats.
+	for i := range scraper.deals {
+		scraper.deals[i].Threads = []models.ThreadContext{{PostURL: scraper.deals[i].PostURL, LikeCount: 5}}
+	}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if store.getByIDCalls != 0 {
+		t.Errorf("GetDealByID called %d times, want 0 (existing deals are loaded in one batch)", store.getByIDCalls)
+	}
+	if store.getByIDsCalls != 2 {
+		t.Errorf("GetDealsByIDs called %d times, want once per run", store.getByIDsCalls)
+	}
+	if store.batchWrites != 2 {
+		t.Errorf("BatchWrite called %d times, want once per run", store.batchWrites)
+	}
+}
+
+func TestProcessDeals_MaxDealsPerRun(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	var deals []models.DealInfo
+	for i := 0; i < 10; i++ {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	scraper := &mockScraper{deals: deals}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.MaxDealsPerRun = 4
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.deals) != 4 {
+		t.Fatalf("Expected 4 deals stored, got %d", len(store.deals))
+	}
+	for _, deal := range store.deals {
+		if deal.PublishedTimestamp.Before(testTime1.Add(6 * time.Minute)) {
+			t.Errorf("Expected only the newest deals kept, got %q", deal.Title)
+		}
+	}
+}
+
+type captureAlerter struct {
+	msgs []string
+}
+
+func (a *captureAlerter) Alert(_ context.Context, _ slog.Level, msg string, _ ...any) {
+	a.msgs = append(a.msgs, msg)
+}
+
+func TestProcessDeals_DetailFieldsReachStore(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	postURL := "https://forums.redflagdeals.com/deal-1"
+	scraper := &mockScraper{
+		deals: []models.DealInfo{{Title: "Echo Dot", PostURL: postURL, PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: postURL}}}},
+		mutateDetails: func(deals []*models.DealInfo) {
+			for _, d := range deals {
+				d.Price = "$29.99"
+				d.Retailer = "Amazon"
+				d.Description = "Lowest price yet"
+				d.Comments = "- in stock"
+				d.Summary = "Ships free"
+			}
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := store.GetDealByID(context.Background(), generateDealID(testTime1))
+	if stored == nil {
+		t.Fatal("deal was not stored")
+	}
+	if stored.Price != "$29.99" || stored.Retailer != "Amazon" {
+		t.Errorf("stored price/retailer = %q/%q, want the detail-page values", stored.Price, stored.Retailer)
+	}
+	if stored.Description != "Lowest price yet" || stored.Comments != "- in stock" || stored.Summary != "Ships free" {
+		t.Errorf("stored content = %q/%q/%q, want the detail-page values", stored.Description, stored.Comments, stored.Summary)
+	}
+	if len(notif.sentDeals) != 1 || notif.sentDeals[0].Price != "$29.99" || notif.sentDeals[0].Retailer != "Amazon" {
+		t.Errorf("notified deals = %+v, want the detail-page price and retailer", notif.sentDeals)
+	}
+}
+
+func TestProcessDeals_WatchKeywordsSendDMs(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "[Costco] Dyson V15 $499", PostURL: "https://forums.redflagdeals.com/dyson-1", PublishedTimestamp: testTime1},
+		{Title: "Echo Dot $29", Retailer: "Amazon", PostURL: "https://forums.redflagdeals.com/echo-2", PublishedTimestamp: testTime2},
+		{Title: "Expired Dyson V8", PostURL: "https://forums.redflagdeals.com/dyson-3", PublishedTimestamp: testTime2.Add(time.Hour), Expired: true},
+	}}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.WatchKeywords = map[string][]string{
+		"dyson":  {"111", "222"},
+		"costco": {"111"},
+		"laptop": {"333"},
+	}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"111:[Costco] Dyson V15 $499", "222:[Costco] Dyson V15 $499"}
+	if !slices.Equal(notif.dms, want) {
+		t.Errorf("DMs = %q, want %q", notif.dms, want)
+	}
+
+	notif.dms = nil
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.dms) != 0 {
+		t.Errorf("DMs on a repeat run = %q, want none for already stored deals", notif.dms)
+	}
+}
+
+func TestProcessDeals_ReheatTTLRepostsOldDeal(t *testing.T) {
+	postURL := "https://forums.redflagdeals.com/deal-1"
+	id := generateDealID(testTime1)
+	for _, ttl := range []time.Duration{0, 24 * time.Hour} {
+		t.Run(ttl.String(), func(t *testing.T) {
+			store := newMockStore()
+			store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDHot}}
+			store.deals[id] = &models.DealInfo{
+				DocumentID:             id,
+				Title:                  "Old Deal",
+				PostURL:                postURL,
+				PublishedTimestamp:     testTime1,
+				Threads:                []models.ThreadContext{{PostURL: postURL, LikeCount: 5}},
+				DiscordMessageIDs:      map[string]string{"channel1": "old-msg"},
+				DiscordLastUpdatedTime: time.Now().Add(-48 * time.Hour),
+				DiscordEngagement:      5,
+			}
+			notif := newMockNotifier()
+			scraper := &mockScraper{deals: []models.DealInfo{{
+				Title:              "Old Deal",
+				PostURL:            postURL,
+				PublishedTimestamp: testTime1,
+				Threads:            []models.ThreadContext{{PostURL: postURL, LikeCount: 60}},
+			}}}
+			p := newTestProcessor(store, notif, scraper)
+			p.config.ReheatTTL = ttl
+
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			stored := store.deals[id]
+			if ttl == 0 {
+				if len(notif.sentDeals) != 0 || stored.DiscordMessageIDs["channel1"] != "old-msg" {
+					t.Errorf("sent %d deals, message %q; want no repost when REHEAT_TTL is off", len(notif.sentDeals), stored.DiscordMessageIDs["channel1"])
+				}
+				return
+			}
+			if len(notif.sentDeals) != 1 || notif.sentDeals[0].ReheatedAt.IsZero() {
+				t.Fatalf("sent deals = %+v, want one back-on-the-hot-list repost", notif.sentDeals)
+			}
+			if stored.DiscordMessageIDs["channel1"] != "msg-123-channel1" || stored.ReheatedAt.IsZero() || stored.DiscordEngagement != 60 {
+				t.Errorf("stored deal = %+v, want the new message, ReheatedAt and engagement recorded", stored)
+			}
+
+			// The repost resets the clock, so the next run doesn't repost again.
+			scraper.deals[0].Threads[0].LikeCount = 80
+			if err := p.ProcessDeals(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if len(notif.sentDeals) != 1 {
+				t.Errorf("sent %d deals after a second run, want no second repost", len(notif.sentDeals))
+			}
+		})
+	}
+}
+
+func TestProcessDeals_SetsPageFloorToNewestStoredDeal(t *testing.T) {
+	store := newMockStore()
+	for _, ts := range []time.Time{testTime1, testTime2} {
+		id := generateDealID(ts)
+		store.deals[id] = &models.DealInfo{DocumentID: id, Title: "Stored", PostURL: "https://forums.redflagdeals.com/" + id, PublishedTimestamp: ts}
+	}
+	scraper := &mockScraper{}
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !scraper.pageFloor.Equal(testTime2) {
+		t.Errorf("page floor = %v, want the newest stored deal's time %v", scraper.pageFloor, testTime2)
+	}
+}
+
+func TestProcessDeals_BatchEmbeds(t *testing.T) {
+	var deals []models.DealInfo
+	for i := range 12 {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier()
+	p := newTestProcessor(store, notif, &mockScraper{deals: deals})
+	p.config.BatchEmbeds = true
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 0 || !slices.Equal(notif.batches, []int{10, 2}) {
+		t.Fatalf("single sends = %d, batches = %v, want no single sends and batches of 10 and 2", len(notif.sentDeals), notif.batches)
+	}
+	first := store.deals[generateDealID(testTime1)]
+	eleventh := store.deals[generateDealID(testTime1.Add(10*time.Minute))]
+	if first.DiscordMessageIDs["channel1"] != "batch-1-channel1" || first.DiscordEmbedIndex["channel1"] != 0 {
+		t.Errorf("first deal message = %v, index %v, want embed 0 of batch 1", first.DiscordMessageIDs, first.DiscordEmbedIndex)
+	}
+	if eleventh.DiscordMessageIDs["channel1"] != "batch-2-channel1" || eleventh.DiscordEmbedIndex["channel1"] != 0 {
+		t.Errorf("eleventh deal message = %v, index %v, want embed 0 of batch 2", eleventh.DiscordMessageIDs, eleventh.DiscordEmbedIndex)
+	}
+	if tenth := store.deals[generateDealID(testTime1.Add(9*time.Minute))]; tenth.DiscordEmbedIndex["channel1"] != 9 {
+		t.Errorf("tenth deal index = %v, want embed 9", tenth.DiscordEmbedIndex)
+	}
+}
+
+type captureSink struct {
+	events []string // "event:title" per SendDeal call
+	deals  []models.DealInfo
+}
+
+func (s *captureSink) SendDeal(_ context.Context, event string, deal models.DealInfo) error {
+	s.events = append(s.events, event+":"+deal.Title)
+	s.deals = append(s.deals, deal)
+	return errors.New("sink down")
+}
+
+func TestProcessDeals_SendsSavedDealsToSinks(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "Deal A", PostURL: "https://forums.redflagdeals.com/deal-a", PublishedTimestamp: testTime1, Description: "Half price"},
+	}}
+	p := newTestProcessor(store, notif, scraper)
+	sink := &captureSink{}
+	p.AddSink(sink)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v, want sink failures ignored", err)
+	}
+	if !slices.Equal(sink.events, []string{"new:Deal A"}) {
+		t.Fatalf("sink events = %v, want the new deal", sink.events)
+	}
+	if sink.deals[0].DocumentID != generateDealID(testTime1) || sink.deals[0].Description != "Half price" {
+		t.Errorf("sink deal = %+v, want the saved deal with its description", sink.deals[0])
+	}
+
+	scraper.deals[0].Threads = []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-a", LikeCount: 25}}
+	p.config.DiscordUpdateInterval = 0
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("second ProcessDeals() error = %v", err)
+	}
+	if len(sink.events) != 2 || sink.events[1] != "updated:Deal A" {
+		t.Errorf("sink events = %v, want an update after the likes changed", sink.events)
+	}
+}
+
+// slowSink blocks each delivery until its context is done.
+type slowSink struct {
+	calls int
+}
+
+func (s *slowSink) SendDeal(ctx context.Context, _ string, _ models.DealInfo) error {
+	s.calls++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestProcessDeals_SinkTimeBudget(t *testing.T) {
+	store := newMockStore()
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "Deal A", PostURL: "https://forums.redflagdeals.com/deal-a", PublishedTimestamp: testTime1},
+		{Title: "Deal B", PostURL: "https://forums.redflagdeals.com/deal-b", PublishedTimestamp: testTime2},
+	}}
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+	p.sinkTimeout = 50 * time.Millisecond
+	sink := &slowSink{}
+	p.AddSink(sink)
+
+	start := time.Now()
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("run took %s, want sink deliveries cut off after the 50ms budget", elapsed)
+	}
+	if sink.calls != 1 || len(store.deals) != 2 {
+		t.Errorf("sink calls = %d, stored = %d; want one delivery before the budget ran out and both deals saved", sink.calls, len(store.deals))
+	}
+}
+
+func TestProcessDeals_MinExpectedDeals(t *testing.T) {
+	shortList := []models.DealInfo{
+		{Title: "Deal A", PostURL: "https://forums.redflagdeals.com/deal-a", PublishedTimestamp: testTime1},
+		{Title: "Deal B", PostURL: "https://forums.redflagdeals.com/deal-b", PublishedTimestamp: testTime2},
+	}
+
+	for _, skip := range []bool{true, false} {
+		t.Run(fmt.Sprintf("skip=%v", skip), func(t *testing.T) {
+			store := newMockStore()
+			notif := newMockNotifier()
+			p := newTestProcessor(store, notif, &mockScraper{deals: slices.Clone(shortList)})
+			p.config.MinExpectedDeals = 40
+			p.config.MinExpectedDealsSkip = skip
+			alerter := &captureAlerter{}
+			p.SetAlerter(alerter)
+
+			err := p.ProcessDeals(context.Background())
+			if len(alerter.msgs) != 1 {
+				t.Errorf("alerts = %q, want one", alerter.msgs)
+			}
+			if skip {
+				if !errors.Is(err, ErrTooFewDeals) {
+					t.Fatalf("ProcessDeals() error = %v, want ErrTooFewDeals", err)
+				}
+				if len(store.deals) != 0 || len(notif.sentDeals) != 0 {
+					t.Errorf("stored %d and notified %d deals, want the run skipped", len(store.deals), len(notif.sentDeals))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(store.deals) != 2 {
+				t.Errorf("stored %d deals, want the short list processed", len(store.deals))
+			}
+		})
+	}
+}
+
+func TestProcessDeals_TruncatedListSkipsCleanup(t *testing.T) {
+	var deals []models.DealInfo
+	for i := 0; i < 10; i++ {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	store := newMockStore()
+	scraper := &mockScraper{deals: deals}
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.trimCalled {
+		t.Fatal("expected the first full run to trim old deals")
+	}
+
+	store.trimCalled = false
+	scraper.deals = []models.DealInfo{{
+		Title:              "Deal 99",
+		PostURL:            "https://forums.redflagdeals.com/deal-99",
+		PublishedTimestamp: testTime2,
+		Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-99"}},
+	}}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.trimCalled {
+		t.Error("a one-deal list after a ten-deal run should not trim stored deals")
+	}
+	if _, ok := store.deals[generateDealID(testTime2)]; !ok {
+		t.Error("the new deal from the short list should still be saved")
+	}
+
+	// A block page lasting a second run is still compared against the last
+	// full list, not the first short one.
+	scraper.deals = []models.DealInfo{{
+		Title:              "Deal 100",
+		PostURL:            "https://forums.redflagdeals.com/deal-100",
+		PublishedTimestamp: testTime2.Add(time.Minute),
+		Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-100"}},
+	}}
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.trimCalled {
+		t.Error("a second consecutive short list should not trim stored deals")
+	}
+
+	// A full list again resumes cleanup.
+	scraper.deals = append(slices.Clone(deals), models.DealInfo{
+		Title:              "Deal 101",
+		PostURL:            "https://forums.redflagdeals.com/deal-101",
+		PublishedTimestamp: testTime2.Add(2 * time.Minute),
+		Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-101"}},
+	})
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.trimCalled {
+		t.Error("expected a full list to trim old deals again")
+	}
+}
+
+func TestProcessDeals_TruncatedListUsesStoredRunsOnFirstRun(t *testing.T) {
+	store := newMockStore()
+	store.runs = []models.RunSummary{{Scraped: 3}, {Scraped: 10}, {Scraped: 9}}
+	scraper := &mockScraper{deals: []models.DealInfo{{
+		Title:              "Deal 99",
+		PostURL:            "https://forums.redflagdeals.com/deal-99",
+		PublishedTimestamp: testTime2,
+		Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-99"}},
+	}}}
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.trimCalled {
+		t.Error("a fresh processor should compare a one-deal list against the stored runs and skip cleanup")
+	}
+}
+
+func TestProcessDeals_QuietHoursDefersNewDeals(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier()
+	notif.notHot = true
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "Olive oil $15", PostURL: "https://forums.redflagdeals.com/oil-1", PublishedTimestamp: testTime1},
+		{Title: "[Walmart] Price error? 65in TV $49", PostURL: "https://forums.redflagdeals.com/tv-2", PublishedTimestamp: testTime1.Add(time.Minute)},
+	}}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.QuietHours = &config.QuietHours{Start: 0, End: 24 * time.Hour, Location: time.UTC} // always quiet
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 1 || !strings.Contains(notif.sentDeals[0].Title, "Price error") {
+		t.Fatalf("Expected only the price-error deal to bypass quiet hours, got %d sends", len(notif.sentDeals))
+	}
+	var deferred *models.DealInfo
+	for _, deal := range store.deals {
+		if deal.Title == "Olive oil $15" {
+			deferred = deal
+		}
+	}
+	if deferred == nil || !deferred.NotifyDeferred || len(deferred.DiscordMessageIDs) != 0 {
+		t.Fatalf("Expected the regular deal stored as deferred without a post, got %+v", deferred)
+	}
+
+	// Once quiet hours end, the deferred deal is posted on the next run.
+	p.config.QuietHours = nil
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 2 || notif.sentDeals[1].Title != "Olive oil $15" {
+		t.Fatalf("Expected the deferred deal to be sent after quiet hours, got %d sends", len(notif.sentDeals))
+	}
+	if deal := store.deals[deferred.DocumentID]; deal.NotifyDeferred || deal.DiscordMessageIDs["channel1"] == "" {
+		t.Errorf("Expected the deferred flag cleared and message ID saved, got %+v", deal)
+	}
+}
+
+func TestProcessDeals_QuietHoursReleasesDealsMissingFromScrape(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier()
+	notif.notHot = true
+	published := time.Now().Add(-8 * time.Hour)
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "Olive oil $15", PostURL: "https://forums.redflagdeals.com/oil-1", PublishedTimestamp: published},
+	}}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.QuietHours = &config.QuietHours{Start: 0, End: 24 * time.Hour, Location: time.UTC} // always quiet
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 0 {
+		t.Fatalf("Expected nothing sent during quiet hours, got %d sends", len(notif.sentDeals))
+	}
+
+	// The deal drops off the list before quiet hours end.
+	scraper.deals = []models.DealInfo{
+		{Title: "Coffee $8", PostURL: "https://forums.redflagdeals.com/coffee-2", PublishedTimestamp: published.Add(time.Hour)},
+	}
+	p.config.QuietHours = nil
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var released *models.DealInfo
+	for _, deal := range store.deals {
+		if deal.Title == "Olive oil $15" {
+			released = deal
+		}
+	}
+	if released == nil || released.NotifyDeferred || released.DiscordMessageIDs["channel1"] == "" {
+		t.Fatalf("Expected the unscraped deferred deal posted and its flag cleared, got %+v", released)
+	}
+	if len(notif.sentDeals) != 2 {
+		t.Errorf("Expected the deferred and the new deal sent once each, got %d sends", len(notif.sentDeals))
+	}
+
+	// Released deals are not sent again.
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 2 {
+		t.Errorf("Expected no repeat sends, got %d", len(notif.sentDeals))
+	}
+}
+
+func TestProcessDeals_QuietHoursHotDealBypasses(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	notif := newMockNotifier() // IsHot reports every deal as hot
+	scraper := &mockScraper{deals: []models.DealInfo{
+		{Title: "Lava hot deal", PostURL: "https://forums.redflagdeals.com/hot-1", PublishedTimestamp: testTime1},
+	}}
+	p := newTestProcessor(store, notif, scraper)
+	p.config.QuietHours = &config.QuietHours{Start: 0, End: 24 * time.Hour, Location: time.UTC}
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notif.sentDeals) != 1 {
+		t.Fatalf("Expected the hot deal to bypass quiet hours, got %d sends", len(notif.sentDeals))
 	}
+}
 
-	// Now update the scraper with changed data (same timestamp = same deal)
-	scraper.deals = []models.DealInfo{
-		{
-			Title:              "Updated Title",
-			PostURL:            "https://forums.redflagdeals.com/deal-1",
-			PublishedTimestamp: testTime1,
-			Threads:            []models.ThreadContext{{LikeCount: 20, PostURL: "https://forums.redflagdeals.com/deal-1"}},
-		},
+func TestRecoverMissingNotifications(t *testing.T) {
+	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "channel1", DealType: dealtypes.RFDAll}}
+	now := time.Now()
+	store.deals = map[string]*models.DealInfo{
+		"posted":  {DocumentID: "posted", Title: "Posted Deal", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-1"}},
+		"missing": {DocumentID: "missing", Title: "Missing Deal", PublishedTimestamp: now.Add(-time.Hour)},
+		"expired": {DocumentID: "expired", Title: "Expired Deal", PublishedTimestamp: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
 	}
-	store.updateCount = 0
+	notif := newMockNotifier()
+	p := newTestProcessor(store, notif, &mockScraper{})
 
-	err = p.ProcessDeals(context.Background())
+	recovered, err := p.RecoverMissingNotifications(context.Background())
 	if err != nil {
-		t.Fatalf("Second ProcessDeals() error = %v", err)
+		t.Fatalf("RecoverMissingNotifications() error = %v", err)
 	}
 
-	if store.updateCount == 0 {
-		t.Error("Expected UpdateDeal to be called for changed deal")
+	if recovered != 1 {
+		t.Errorf("recovered = %d, want 1", recovered)
+	}
+	if len(notif.sentDeals) != 1 || notif.sentDeals[0].Title != "Missing Deal" {
+		t.Fatalf("Expected only the deal missing a message ID to be sent, got %d sends", len(notif.sentDeals))
+	}
+	if got := store.deals["missing"].DiscordMessageIDs["channel1"]; got == "" {
+		t.Error("Expected recovered message ID to be persisted")
+	}
+	if got := store.deals["posted"].DiscordMessageIDs["channel1"]; got != "msg-1" {
+		t.Errorf("posted deal message ID = %q, want unchanged msg-1", got)
 	}
 }
 
-func TestProcessDeals_URLChangedDealsUpdated(t *testing.T) {
+func TestRefreshEmbeds(t *testing.T) {
 	store := newMockStore()
+	now := time.Now()
+	store.deals = map[string]*models.DealInfo{
+		"posted-1": {DocumentID: "posted-1", Title: "Posted 1", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-1"}},
+		"posted-2": {DocumentID: "posted-2", Title: "Posted 2", PublishedTimestamp: now.Add(-2 * time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-2", "channel2": "msg-3"}},
+		"unposted": {DocumentID: "unposted", Title: "Unposted", PublishedTimestamp: now.Add(-time.Hour)},
+		"snoozed":  {DocumentID: "snoozed", Title: "Snoozed", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-4"}, SnoozeUntil: now.Add(time.Hour)},
+	}
 	notif := newMockNotifier()
+	p := newTestProcessor(store, notif, &mockScraper{})
 
-	// First run: create the deal
-	scraper := &mockScraper{
-		deals: []models.DealInfo{
-			{Title: "Great Deal", PostURL: "https://forums.redflagdeals.com/deal-old-url", ActualDealURL: "https://amazon.ca/old-url", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-old-url"}}},
-		},
+	refreshed, err := p.RefreshEmbeds(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshEmbeds() error = %v", err)
 	}
-	p := newTestProcessor(store, notif, scraper)
-	if err := p.ProcessDeals(context.Background()); err != nil {
-		t.Fatal(err)
+	if refreshed != 2 {
+		t.Errorf("refreshed = %d, want 2", refreshed)
 	}
-
-	// Verify 1 deal created
-	if len(store.deals) != 1 {
-		t.Fatalf("Expected 1 deal, got %d", len(store.deals))
+	got := append([]string(nil), notif.updatedIDs...)
+	sort.Strings(got)
+	if strings.Join(got, ",") != "msg-1,msg-2,msg-3" {
+		t.Errorf("updated message IDs = %v, want msg-1, msg-2 and msg-3", got)
 	}
+}
 
-	// Second run: same timestamp but URL changed (user edited the post)
-	scraper.deals = []models.DealInfo{
-		{Title: "Great Deal", PostURL: "https://forums.redflagdeals.com/deal-new-url", ActualDealURL: "https://amazon.ca/new-url", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-new-url"}}},
+func TestReprocessRetailer_OnlyMatchingRetailer(t *testing.T) {
+	store := newMockStore()
+	now := time.Now()
+	store.deals = map[string]*models.DealInfo{
+		"amazon-1":  {DocumentID: "amazon-1", Title: "[Amazon] Headphones $99", Retailer: "Amazon", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-1"}},
+		"amazon-2":  {DocumentID: "amazon-2", Title: "[Amazon] SSD $60", Retailer: " amazon ", PublishedTimestamp: now.Add(-2 * time.Hour)},
+		"amazon-3":  {DocumentID: "amazon-3", Title: "[Amazon] Kettle $20", Retailer: "Amazon", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-3"}, SnoozeUntil: now.Add(time.Hour)},
+		"walmart-1": {DocumentID: "walmart-1", Title: "[Walmart] TV $299", Retailer: "Walmart", PublishedTimestamp: now.Add(-time.Hour), DiscordMessageIDs: map[string]string{"channel1": "msg-2"}},
 	}
-	store.updateCount = 0
+	notif := newMockNotifier()
+	scraper := &mockScraper{}
+	p := newTestProcessor(store, notif, scraper)
 
-	if err := p.ProcessDeals(context.Background()); err != nil {
-		t.Fatal(err)
+	reprocessed, err := p.ReprocessRetailer(context.Background(), "amazon")
+	if err != nil {
+		t.Fatalf("ReprocessRetailer() error = %v", err)
+	}
+	if reprocessed != 3 {
+		t.Errorf("reprocessed = %d, want 3", reprocessed)
 	}
 
-	// Should still be 1 deal (updated, not duplicated)
-	if len(store.deals) != 1 {
-		t.Errorf("Expected 1 deal (URL change should update, not duplicate), got %d", len(store.deals))
+	var fetched []string
+	for _, d := range scraper.fetchedDetails {
+		fetched = append(fetched, d.DocumentID)
 	}
-	if store.updateCount == 0 {
-		t.Error("Expected UpdateDeal to be called when ActualDealURL changed")
+	sort.Strings(fetched)
+	if strings.Join(fetched, ",") != "amazon-1,amazon-2,amazon-3" {
+		t.Errorf("fetched details for %v, want only the Amazon deals", fetched)
 	}
+	if strings.Join(notif.updatedIDs, ",") != "msg-1" {
+		t.Errorf("updated message IDs = %v, want only msg-1 (unposted and snoozed deals are not edited)", notif.updatedIDs)
+	}
+	if store.deals["walmart-1"].LastUpdated.After(now) {
+		t.Error("Walmart deal was saved, want it left untouched")
+	}
+}
 
-	// Verify the stored deal has the new URL
-	for _, deal := range store.deals {
-		if deal.ActualDealURL != "https://amazon.ca/new-url" {
-			t.Errorf("Expected ActualDealURL to be updated, got %q", deal.ActualDealURL)
-		}
+func TestReprocessRetailer_RequiresRetailer(t *testing.T) {
+	p := newTestProcessor(newMockStore(), newMockNotifier(), &mockScraper{})
+	if _, err := p.ReprocessRetailer(context.Background(), "  "); err == nil {
+		t.Fatal("ReprocessRetailer() with blank retailer succeeded, want error")
 	}
 }
 
-func TestProcessDeals_TitleChangedDealsUpdated(t *testing.T) {
+func TestProcessDeals_PriceErrorTreatedAsHot(t *testing.T) {
 	store := newMockStore()
+	store.subs = []models.Subscription{{GuildID: "guild1", ChannelID: "hot-channel", DealType: dealtypes.RFDHot}}
 	notif := newMockNotifier()
-
+	notif.notHot = true
 	scraper := &mockScraper{
 		deals: []models.DealInfo{
-			{Title: "Original Title", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+			{Title: "[Walmart] Price error? 65in TV $49", PostURL: "https://forums.redflagdeals.com/tv-1", PublishedTimestamp: testTime1},
+			{Title: "Dyson V15 $150", PostURL: "https://forums.redflagdeals.com/dyson-2", PublishedTimestamp: testTime1.Add(time.Minute)},
+			{Title: "Olive oil $15", PostURL: "https://forums.redflagdeals.com/oil-3", PublishedTimestamp: testTime1.Add(2 * time.Minute)},
+		},
+		mutateDetails: func(deals []*models.DealInfo) {
+			for _, d := range deals {
+				if strings.Contains(d.Title, "Dyson") {
+					d.Comments = "YMMV, surely a mistake - ordered two"
+				}
+			}
 		},
 	}
 	p := newTestProcessor(store, notif, scraper)
@@ -432,26 +1906,54 @@ func TestProcessDeals_TitleChangedDealsUpdated(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Second run: same timestamp but title changed
-	scraper.deals = []models.DealInfo{
-		{Title: "Updated Title - Price Drop!", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/deal-1"}}},
+	for _, deal := range store.deals {
+		wantPriceError := !strings.Contains(deal.Title, "Olive")
+		if deal.PriceError != wantPriceError || deal.HasBeenHot != wantPriceError {
+			t.Errorf("%q: PriceError=%v HasBeenHot=%v, want %v", deal.Title, deal.PriceError, deal.HasBeenHot, wantPriceError)
+		}
+		if _, posted := deal.DiscordMessageIDs["hot-channel"]; posted != wantPriceError {
+			t.Errorf("%q: posted to hot channel = %v, want %v", deal.Title, posted, wantPriceError)
+		}
 	}
-	store.updateCount = 0
+}
 
+func TestProcessDeals_DetectsYMMVAndRegion(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "[ON] Free Slurpee at 7-Eleven", PostURL: "https://forums.redflagdeals.com/slurpee-1", PublishedTimestamp: testTime1},
+			{Title: "[Walmart] YMMV LEGO clearance 50% off", PostURL: "https://forums.redflagdeals.com/lego-2", PublishedTimestamp: testTime1.Add(time.Minute)},
+			{Title: "Metro Coffee $5", PostURL: "https://forums.redflagdeals.com/coffee-3", PublishedTimestamp: testTime1.Add(2 * time.Minute)},
+		},
+		mutateDetails: func(deals []*models.DealInfo) {
+			for _, d := range deals {
+				if strings.Contains(d.Title, "Coffee") {
+					d.Description = "In store at select locations [QC]"
+				}
+			}
+		},
+	}
+	p := newTestProcessor(store, notif, scraper)
 	if err := p.ProcessDeals(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
-	if len(store.deals) != 1 {
-		t.Errorf("Expected 1 deal (title change should update, not duplicate), got %d", len(store.deals))
-	}
-	if store.updateCount == 0 {
-		t.Error("Expected UpdateDeal to be called when Title changed")
+	want := map[string]struct {
+		ymmv   bool
+		region string
+	}{
+		"[ON] Free Slurpee at 7-Eleven":         {region: "ON"},
+		"[Walmart] YMMV LEGO clearance 50% off": {ymmv: true},
+		"Metro Coffee $5":                       {ymmv: true, region: "QC"},
 	}
-
 	for _, deal := range store.deals {
-		if deal.Title != "Updated Title - Price Drop!" {
-			t.Errorf("Expected Title to be updated, got %q", deal.Title)
+		w, ok := want[deal.Title]
+		if !ok {
+			t.Fatalf("unexpected stored deal %q", deal.Title)
+		}
+		if deal.YMMV != w.ymmv || deal.Region != w.region {
+			t.Errorf("%q: YMMV=%v Region=%q, want %v, %q", deal.Title, deal.YMMV, deal.Region, w.ymmv, w.region)
 		}
 	}
 }
@@ -637,12 +2139,244 @@ func TestGenerateDealID_Stable(t *testing.T) {
 
 // --- New Unit Tests for Helper Functions ---
 
+func TestProcessDeals_SameTimestampDifferentDealsStoredSeparately(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{
+				Title:              "Costco Kirkland Olive Oil 3L $19.99",
+				PostURL:            "https://forums.redflagdeals.com/costco-kirkland-olive-oil-2800001",
+				PublishedTimestamp: testTime1,
+				Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/costco-kirkland-olive-oil-2800001"}},
+			},
+			{
+				Title:              "Canada Computers Samsung 990 Pro 2TB SSD $159",
+				PostURL:            "https://forums.redflagdeals.com/canada-computers-samsung-990-pro-2800002",
+				PublishedTimestamp: testTime1,
+				Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/canada-computers-samsung-990-pro-2800002"}},
+			},
+		},
+	}
+
+	p := newTestProcessor(store, notif, scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+
+	if len(store.deals) != 2 {
+		t.Fatalf("expected 2 stored deals, got %d", len(store.deals))
+	}
+	baseID := generateDealID(testTime1)
+	if store.deals[baseID] == nil || !strings.Contains(store.deals[baseID].Title, "Olive Oil") {
+		t.Fatalf("expected first deal to keep the timestamp ID, got %+v", store.deals[baseID])
+	}
+	collisionID := models.CollisionDealID(baseID, "rfd:2800002")
+	if store.deals[collisionID] == nil {
+		t.Fatalf("expected second deal under %s", collisionID)
+	}
+	if !models.IsDealIDFor(collisionID, testTime1) {
+		t.Errorf("IsDealIDFor(%s) = false, want the migration to keep the disambiguated ID", collisionID)
+	}
+	if len(notif.sentDeals) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notif.sentDeals))
+	}
+
+	// A second run recognises both records instead of creating new ones.
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("second ProcessDeals() error = %v", err)
+	}
+	if len(store.deals) != 2 || len(notif.sentDeals) != 2 {
+		t.Fatalf("second run: stored=%d sent=%d, want 2 and 2", len(store.deals), len(notif.sentDeals))
+	}
+
+	// Editing the disambiguated deal's title keeps its ID, so it isn't re-sent.
+	scraper.deals[1].Title = "Canada Computers Samsung 990 Pro 2TB SSD $149"
+	scraper.deals[1].PostURL = "https://forums.redflagdeals.com/canada-computers-samsung-990-pro-149-2800002"
+	scraper.deals[1].Threads[0].PostURL = scraper.deals[1].PostURL
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("third ProcessDeals() error = %v", err)
+	}
+	if len(store.deals) != 2 || len(notif.sentDeals) != 2 {
+		t.Fatalf("after title edit: stored=%d sent=%d, want 2 and 2", len(store.deals), len(notif.sentDeals))
+	}
+}
+
+func TestResolveTimestampCollisions_TitleEditOnSameThreadKeepsID(t *testing.T) {
+	store := newMockStore()
+	p := newTestProcessor(store, newMockNotifier(), &mockScraper{})
+	baseID := generateDealID(testTime1)
+	existing := map[string]*models.DealInfo{
+		baseID: {
+			DocumentID: baseID,
+			Title:      "Old Title",
+			Threads:    []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/old-title-2800001"}},
+		},
+	}
+	deals := []models.DealInfo{{
+		DocumentID: baseID,
+		Title:      "Completely New Title",
+		PostURL:    "https://forums.redflagdeals.com/completely-new-title-2800001",
+		Threads:    []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/completely-new-title-2800001"}},
+	}}
+
+	if err := p.resolveTimestampCollisions(context.Background(), deals, existing, slog.Default()); err != nil {
+		t.Fatalf("resolveTimestampCollisions() error = %v", err)
+	}
+	if deals[0].DocumentID != baseID {
+		t.Fatalf("title edit on the same thread changed ID to %s", deals[0].DocumentID)
+	}
+}
+
+// coldNotifier reports every deal as neither warm nor hot.
+type coldNotifier struct {
+	*mockNotifier
+}
+
+func (coldNotifier) IsWarm(models.DealInfo) bool { return false }
+func (coldNotifier) IsHot(models.DealInfo) bool  { return false }
+
+func TestAlwaysNotifyKeywordBypassesHeatGate(t *testing.T) {
+	p := newTestProcessor(newMockStore(), coldNotifier{newMockNotifier()}, &mockScraper{})
+	p.config.AlwaysNotifyKeywords = []string{"Price Error", "costco"}
+
+	hotSub := models.Subscription{GuildID: "g", ChannelID: "c", DealType: dealtypes.RFDHot}
+	hotTechSub := models.Subscription{GuildID: "g", ChannelID: "t", DealType: dealtypes.RFDHotTech}
+
+	priceError := models.DealInfo{Title: "[Walmart] PRICE ERROR LG 65\" OLED $499", Category: "Home & Garden"}
+	if !p.isDealEligibleForSubscription(priceError, hotSub) {
+		t.Error("expected always-notify title keyword to bypass the hot gate")
+	}
+	if p.isDealEligibleForSubscription(priceError, hotTechSub) {
+		t.Error("expected always-notify deal to still respect the tech filter")
+	}
+
+	costco := models.DealInfo{Title: "Kirkland Olive Oil 3L", Retailer: "Costco"}
+	if !p.isDealEligibleForSubscription(costco, hotSub) {
+		t.Error("expected always-notify retailer keyword to bypass the hot gate")
+	}
+
+	ordinary := models.DealInfo{Title: "Sub-threshold deal", Retailer: "Amazon"}
+	if p.isDealEligibleForSubscription(ordinary, hotSub) {
+		t.Error("expected cold deal without keyword to stay suppressed")
+	}
+}
+
+func TestProcessDeals_StoresDiscountPct(t *testing.T) {
+	store := newMockStore()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Discounted", PostURL: "https://forums.redflagdeals.com/discounted-1", PublishedTimestamp: testTime1},
+			{Title: "Price only", PostURL: "https://forums.redflagdeals.com/price-only-2", PublishedTimestamp: testTime2},
+		},
+		mutateDetails: func(deals []*models.DealInfo) {
+			for _, d := range deals {
+				d.Price = "$60.00"
+				if d.Title == "Discounted" {
+					d.OriginalPrice = "$80.00"
+				}
+			}
+		},
+	}
+
+	p := newTestProcessor(store, newMockNotifier(), scraper)
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+
+	if got := store.deals[generateDealID(testTime1)].DiscountPct; got != 25 {
+		t.Errorf("DiscountPct = %d, want 25", got)
+	}
+	if got := store.deals[generateDealID(testTime2)].DiscountPct; got != 0 {
+		t.Errorf("DiscountPct without original price = %d, want 0", got)
+	}
+}
+
+func TestProcessDeals_SkipsTitleCleaningNearDeadline(t *testing.T) {
+	var deals []models.DealInfo
+	for i := 0; i < titleBatchSize; i++ {
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("[Store] Deal %d", i),
+			PostURL:            fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i),
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		wantClean bool
+	}{
+		{name: "near deadline", timeout: titleCleanReserve / 2, wantClean: false},
+		{name: "ample deadline", timeout: 4 * time.Minute, wantClean: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			notif := newMockNotifier()
+			ai := &mockDealAnalyzer{}
+			p := New(store, notif, &mockScraper{deals: deals}, validator.New(), &config.Config{MaxStoredDeals: 500}, ai)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			if err := p.ProcessDeals(ctx); err != nil {
+				t.Fatalf("ProcessDeals() error = %v", err)
+			}
+
+			if ai.called != tt.wantClean {
+				t.Errorf("CleanTitles called = %v, want %v", ai.called, tt.wantClean)
+			}
+			if len(notif.sentDeals) != len(deals) {
+				t.Fatalf("Expected all %d deals sent, got %d", len(deals), len(notif.sentDeals))
+			}
+			for _, d := range notif.sentDeals {
+				if d.AIProcessed != tt.wantClean {
+					t.Errorf("%q AIProcessed = %v, want %v", d.Title, d.AIProcessed, tt.wantClean)
+				}
+			}
+			if len(p.titleQueue) != 0 {
+				t.Errorf("title queue holds %d entries, want it cleared", len(p.titleQueue))
+			}
+		})
+	}
+}
+
+func TestProcessDeals_DeterministicTitleCleaningWithoutAI(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "[Amazon.ca] Lava Hot! Echo Dot $29", PostURL: "https://forums.redflagdeals.com/echo-dot-1", PublishedTimestamp: testTime1},
+		},
+	}
+	cfg := &config.Config{DiscordUpdateInterval: 10 * time.Minute, MaxStoredDeals: 500}
+	p := New(store, notif, scraper, validator.New(), cfg, nil)
+
+	if err := p.ProcessDeals(context.Background()); err != nil {
+		t.Fatalf("ProcessDeals() error = %v", err)
+	}
+
+	stored := store.deals[generateDealID(testTime1)]
+	if stored == nil {
+		t.Fatal("expected deal to be stored")
+	}
+	if stored.Title != "[Amazon.ca] Lava Hot! Echo Dot $29" {
+		t.Errorf("Title = %q, want original title preserved", stored.Title)
+	}
+	if stored.CleanTitle != "Echo Dot $29" || stored.AIProcessed {
+		t.Errorf("CleanTitle = %q, AIProcessed = %v; want deterministic clean title", stored.CleanTitle, stored.AIProcessed)
+	}
+	if len(notif.sentDeals) != 1 || notif.sentDeals[0].CleanTitle != "Echo Dot $29" {
+		t.Errorf("expected notification to carry the clean title, got %+v", notif.sentDeals)
+	}
+}
+
 func TestScrapeAndValidate_SubFunction(t *testing.T) {
 	store := newMockStore()
 	notif := newMockNotifier()
 	scraper := &mockScraper{
 		deals: []models.DealInfo{
-			{Title: "Valid Deal", PostURL: "http://example.com/1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
+			{Title: "Valid Deal", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{}}},
 			{Title: "", PostURL: "", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{}}}, // Invalid
 		},
 	}
@@ -665,6 +2399,30 @@ func TestScrapeAndValidate_SubFunction(t *testing.T) {
 	}
 }
 
+func TestScrapeAndValidate_DropsDuplicateThreads(t *testing.T) {
+	pinned := "https://forums.redflagdeals.com/echo-dot-2806520/"
+	scraper := &mockScraper{
+		deals: []models.DealInfo{
+			{Title: "Echo Dot $29", PostURL: pinned, PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{PostURL: pinned, LikeCount: 4}}},
+			{Title: "Other Deal", PostURL: "https://forums.redflagdeals.com/other-2806521/", PublishedTimestamp: testTime2},
+			{Title: "Echo Dot $29", PostURL: pinned + "#unread", PublishedTimestamp: testTime1, Price: "$29", Retailer: "Amazon",
+				Threads: []models.ThreadContext{{PostURL: pinned, LikeCount: 4}}},
+		},
+	}
+	p := newTestProcessor(newMockStore(), newMockNotifier(), scraper)
+
+	validDeals, err := p.scrapeAndValidate(context.Background(), slog.Default(), metrics.NewTracker("rfd"))
+	if err != nil {
+		t.Fatalf("scrapeAndValidate failed: %v", err)
+	}
+	if len(validDeals) != 2 {
+		t.Fatalf("got %d deals, want 2 after dropping the duplicate thread", len(validDeals))
+	}
+	if validDeals[0].Title != "Echo Dot $29" || validDeals[0].Price != "$29" {
+		t.Errorf("kept %+v, want the richer Echo Dot record in first position", validDeals[0])
+	}
+}
+
 func TestEnrichDealsWithDetails_SubFunction(t *testing.T) {
 	store := newMockStore()
 	notif := newMockNotifier()
@@ -723,6 +2481,58 @@ func TestEnrichDealsWithDetails_SubFunction(t *testing.T) {
 	}
 }
 
+func TestEnrichDealsWithDetails_Strategy(t *testing.T) {
+	existingDeals := map[string]*models.DealInfo{
+		"stored": {Title: "Old title", DocumentID: "stored", ActualDealURL: "https://example.com/item", Description: "desc"},
+	}
+	dealsForRun := func() []models.DealInfo {
+		return []models.DealInfo{
+			{Title: "Cold", DocumentID: "cold", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{LikeCount: 1}}},
+			{Title: "Hot", DocumentID: "hot", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{LikeCount: 40, CommentCount: 20}}},
+			{Title: "Warm newer", DocumentID: "warm-new", PublishedTimestamp: testTime2, Threads: []models.ThreadContext{{LikeCount: 10}}},
+			{Title: "Warm older", DocumentID: "warm-old", PublishedTimestamp: testTime1, Threads: []models.ThreadContext{{LikeCount: 10}}},
+			{Title: "New title", DocumentID: "stored", Threads: []models.ThreadContext{{LikeCount: 2}}},
+		}
+	}
+	fetchedTitles := func(scraper *mockScraper) []string {
+		var titles []string
+		for _, d := range scraper.fetchedDetails {
+			titles = append(titles, d.Title)
+		}
+		sort.Strings(titles)
+		return titles
+	}
+
+	t.Run("top-n", func(t *testing.T) {
+		scraper := &mockScraper{}
+		p := newTestProcessor(newMockStore(), newMockNotifier(), scraper)
+		p.config.DetailFetch = "top-n"
+		p.config.DetailFetchTopN = 2
+		validDeals := dealsForRun()
+
+		p.enrichDealsWithDetails(context.Background(), validDeals, existingDeals, slog.Default())
+
+		if got, want := fetchedTitles(scraper), []string{"Hot", "Warm newer"}; !slices.Equal(got, want) {
+			t.Errorf("fetched details for %v, want the 2 hottest %v", got, want)
+		}
+		if validDeals[4].ActualDealURL != "https://example.com/item" {
+			t.Errorf("stored deal left out of top-n lost its details: %+v", validDeals[4])
+		}
+	})
+
+	t.Run("new-only", func(t *testing.T) {
+		scraper := &mockScraper{}
+		p := newTestProcessor(newMockStore(), newMockNotifier(), scraper)
+		p.config.DetailFetch = "new-only"
+
+		p.enrichDealsWithDetails(context.Background(), dealsForRun(), existingDeals, slog.Default())
+
+		if got, want := fetchedTitles(scraper), []string{"Cold", "Hot", "Warm newer", "Warm older"}; !slices.Equal(got, want) {
+			t.Errorf("fetched details for %v, want only new deals %v", got, want)
+		}
+	})
+}
+
 func TestDealChanged_IgnoresCanonicalProductURLNoise(t *testing.T) {
 	p := &DealProcessor{}
 	existing := &models.DealInfo{
@@ -1154,3 +2964,76 @@ func TestDeduplicateThreadsByKey_NoDuplicates(t *testing.T) {
 		t.Errorf("Expected 2 threads unchanged, got %d", len(deal.Threads))
 	}
 }
+
+func TestProcessDealsWithResult_GroupsIdenticalErrors(t *testing.T) {
+	var deals []models.DealInfo
+	for i := range 3 {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	notif := newMockNotifier()
+	notif.sendErr = errors.New("discord unavailable")
+	p := newTestProcessor(newMockStore(), notif, &mockScraper{deals: deals})
+
+	result, err := p.ProcessDealsWithResult(context.Background())
+	if !errors.Is(err, ErrPartialRun) {
+		t.Fatalf("err = %v, want ErrPartialRun", err)
+	}
+	want := "new deal error: discord unavailable (x3)"
+	if len(result.Errors) != 3 || !slices.Equal(result.ErrorSummary, []string{want}) {
+		t.Errorf("errors = %q, summary = %q, want 3 errors grouped as %q", result.Errors, result.ErrorSummary, want)
+	}
+	if !strings.HasSuffix(err.Error(), ": "+want) {
+		t.Errorf("err = %q, want the grouped summary", err)
+	}
+}
+
+func TestSummarizeFailures_CapsGroups(t *testing.T) {
+	var failures []dealFailure
+	for i := range maxErrorSummaries + 2 {
+		failures = append(failures, dealFailure{kind: "existing deal", subject: "id", err: fmt.Errorf("failure %d", i)})
+	}
+	failures = append(failures, failures[len(failures)-1])
+
+	summary := summarizeFailures(failures)
+	if len(summary) != maxErrorSummaries+1 || summary[maxErrorSummaries] != "... and 3 more errors" {
+		t.Errorf("summary = %q, want %d groups then the remaining count", summary, maxErrorSummaries)
+	}
+}
+
+func TestProcessDealsWithResult_Counts(t *testing.T) {
+	store := newMockStore()
+	notif := newMockNotifier()
+	var deals []models.DealInfo
+	for i := 0; i < 3; i++ {
+		postURL := fmt.Sprintf("https://forums.redflagdeals.com/deal-%d", i)
+		deals = append(deals, models.DealInfo{
+			Title:              fmt.Sprintf("Deal %d", i),
+			PostURL:            postURL,
+			PublishedTimestamp: testTime1.Add(time.Duration(i) * time.Minute),
+			Threads:            []models.ThreadContext{{PostURL: postURL}},
+		})
+	}
+	p := newTestProcessor(store, notif, &mockScraper{deals: deals})
+
+	result, err := p.ProcessDealsWithResult(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scraped != 3 || result.New != 3 || result.Updated != 0 || result.Skipped != 0 || len(result.Errors) != 0 {
+		t.Fatalf("first run = %+v, want 3 scraped and new", result)
+	}
+
+	result, err = p.ProcessDealsWithResult(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.New != 0 || result.Updated+result.Skipped != 3 {
+		t.Fatalf("second run = %+v, want every deal updated or skipped", result)
+	}
+}
@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+// RunDiff reports what a run changed in storage: deals created, deals updated
+// with the fields that differ from the stored copy, and stored deals whose
+// RFD threads were all gone this run. IDs are deal document IDs.
+type RunDiff struct {
+	New     []string     `json:"new"`
+	Changed []DealChange `json:"changed"`
+	Removed []string     `json:"removed"`
+}
+
+// DealChange lists the DealInfo fields an update changed. Thread fields are
+// reported per stat, e.g. "Threads.LikeCount"; "Threads" alone means threads
+// were added or removed.
+type DealChange struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Fields []string `json:"fields"`
+}
+
+// diffIgnoredFields change on every save (or, for History, with every stats
+// change) and would drown out the real changes.
+var diffIgnoredFields = map[string]bool{
+	"LastUpdated": true,
+	"ExpiresAt":   true,
+	"History":     true,
+}
+
+// snapshotDeals copies the stored deals before processing mutates them in
+// place (thread stats, Discord message IDs).
+func snapshotDeals(deals map[string]*models.DealInfo) map[string]models.DealInfo {
+	snapshot := make(map[string]models.DealInfo, len(deals))
+	for id, deal := range deals {
+		if deal == nil {
+			continue
+		}
+		copied := *deal
+		copied.DiscordMessageIDs = maps.Clone(deal.DiscordMessageIDs)
+		copied.Threads = slices.Clone(deal.Threads)
+		copied.SearchTokens = slices.Clone(deal.SearchTokens)
+		copied.Labels = slices.Clone(deal.Labels)
+		copied.History = slices.Clone(deal.History)
+		snapshot[id] = copied
+	}
+	return snapshot
+}
+
+// buildRunDiff compares the deals a run is about to save with the stored
+// copies taken before processing.
+func buildRunDiff(before map[string]models.DealInfo, validDeals, newDeals, updatedDeals []models.DealInfo) RunDiff {
+	diff := RunDiff{New: []string{}, Changed: []DealChange{}, Removed: []string{}}
+	for _, deal := range newDeals {
+		diff.New = append(diff.New, deal.DocumentID)
+	}
+	for _, deal := range updatedDeals {
+		stored, ok := before[deal.DocumentID]
+		if !ok {
+			continue
+		}
+		if fields := changedDealFields(stored, deal); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, DealChange{ID: deal.DocumentID, Title: deal.Title, Fields: fields})
+		}
+	}
+
+	grouped := make(map[string][]models.DealInfo)
+	for _, deal := range validDeals {
+		grouped[deal.DocumentID] = append(grouped[deal.DocumentID], deal)
+	}
+	for id, group := range grouped {
+		if _, stored := before[id]; stored && len(liveScrapedDeals(group)) == 0 {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	sort.Strings(diff.New)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ID < diff.Changed[j].ID })
+	return diff
+}
+
+// changedDealFields returns the names of the DealInfo fields that differ,
+// in struct order.
+func changedDealFields(before, after models.DealInfo) []string {
+	var fields []string
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+	rt := bv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if diffIgnoredFields[field.Name] || field.Tag.Get("docstore") == "-" {
+			continue
+		}
+		if field.Name == "Threads" {
+			fields = append(fields, changedThreadFields(before.Threads, after.Threads)...)
+			continue
+		}
+		if !reflect.DeepEqual(bv.Field(i).Interface(), av.Field(i).Interface()) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+func changedThreadFields(before, after []models.ThreadContext) []string {
+	if len(before) != len(after) {
+		return []string{"Threads"}
+	}
+	var fields []string
+	rt := reflect.TypeOf(models.ThreadContext{})
+	for f := 0; f < rt.NumField(); f++ {
+		for i := range before {
+			if reflect.ValueOf(before[i]).Field(f).Interface() != reflect.ValueOf(after[i]).Field(f).Interface() {
+				fields = append(fields, "Threads."+rt.Field(f).Name)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// logRunDiff logs each change in the diff at Info (LOG_RUN_DIFF).
+func logRunDiff(logger *slog.Logger, diff RunDiff) {
+	logger.Info("Run diff", "new", len(diff.New), "changed", len(diff.Changed), "removed", len(diff.Removed))
+	for _, id := range diff.New {
+		logger.Info("Run diff: new deal", "id", id)
+	}
+	for _, change := range diff.Changed {
+		logger.Info("Run diff: changed deal", "id", change.ID, "title", change.Title, "fields", change.Fields)
+	}
+	for _, id := range diff.Removed {
+		logger.Info("Run diff: removed deal", "id", id)
+	}
+}
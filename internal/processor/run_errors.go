@@ -0,0 +1,68 @@
+package processor
+
+import "fmt"
+
+// dealFailure is one deal that failed during a run without aborting it.
+type dealFailure struct {
+	kind    string // "new deal" or "existing deal"
+	subject string // the deal's title or document ID
+	err     error
+}
+
+func (f dealFailure) String() string {
+	return fmt.Sprintf("%s error %s: %v", f.kind, f.subject, f.err)
+}
+
+const (
+	// maxErrorSummaries caps the grouped errors a run reports.
+	maxErrorSummaries = 10
+	// maxErrorSummaryLen caps one grouped error, in runes.
+	maxErrorSummaryLen = 300
+)
+
+// summarizeFailures groups failures with the same kind and error text, in
+// first-seen order, so an outage that fails every deal reads as one line:
+// "new deal error: storage unavailable (x37)". At most maxErrorSummaries
+// groups are listed, then a count of the rest.
+func summarizeFailures(failures []dealFailure) []string {
+	var order []string
+	counts := make(map[string]int)
+	for _, f := range failures {
+		key := fmt.Sprintf("%s error: %v", f.kind, f.err)
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+
+	summary := make([]string, 0, min(len(order), maxErrorSummaries+1))
+	for i, key := range order {
+		if i == maxErrorSummaries {
+			rest := 0
+			for _, key := range order[i:] {
+				rest += counts[key]
+			}
+			summary = append(summary, fmt.Sprintf("... and %d more errors", rest))
+			break
+		}
+		if runes := []rune(key); len(runes) > maxErrorSummaryLen {
+			key = string(runes[:maxErrorSummaryLen]) + "…"
+		}
+		if n := counts[order[i]]; n > 1 {
+			key = fmt.Sprintf("%s (x%d)", key, n)
+		}
+		summary = append(summary, key)
+	}
+	return summary
+}
+
+func failureMessages(failures []dealFailure) []string {
+	if len(failures) == 0 {
+		return nil
+	}
+	messages := make([]string, len(failures))
+	for i, f := range failures {
+		messages[i] = f.String()
+	}
+	return messages
+}
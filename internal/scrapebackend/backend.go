@@ -8,16 +8,20 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/chromedp/chromedp"
 	"github.com/playwright-community/playwright-go"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 )
 
 const (
@@ -78,7 +82,12 @@ func FetchHTML(ctx context.Context, opts FetchOptions) FetchResult {
 	attemptCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	backendResult := fetchBackend(attemptCtx, opts)
+	var backendResult backendFetchResult
+	if err := hostLimiter.Load().Wait(attemptCtx, hostOf(opts.URL)); err != nil {
+		backendResult.err = err
+	} else {
+		backendResult = fetchBackend(attemptCtx, opts)
+	}
 	result := FetchResult{
 		Backend:     opts.Backend,
 		URL:         opts.URL,
@@ -94,6 +103,23 @@ func FetchHTML(ctx context.Context, opts FetchOptions) FetchResult {
 	return result
 }
 
+// hostLimiter paces FetchHTML per target host (HOST_RATE_LIMIT). It is shared
+// by every processor that fetches retailer pages; nil never waits.
+var hostLimiter atomic.Pointer[util.HostLimiter]
+
+// SetHostLimiter paces all later FetchHTML calls with h; nil disables pacing.
+func SetHostLimiter(h *util.HostLimiter) {
+	hostLimiter.Store(h)
+}
+
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
 type backendFetchResult struct {
 	html       string
 	finalURL   string
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 )
 
 func TestDetectBlockSignal(t *testing.T) {
@@ -79,6 +82,26 @@ func TestFetchHTMLPaidTrialRunsAttemptGuardBeforeCommand(t *testing.T) {
 	}
 }
 
+func TestFetchHTMLPacesRequestsPerHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}))
+	defer srv.Close()
+	SetHostLimiter(util.NewHostLimiter(20)) // one request every 50ms per host
+	t.Cleanup(func() { SetHostLimiter(nil) })
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		result := FetchHTML(context.Background(), FetchOptions{Backend: BackendHTTP, URL: srv.URL, Timeout: time.Second})
+		if result.Error != "" {
+			t.Fatalf("FetchHTML() error = %s", result.Error)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 fetches from one host took %s, want them paced ~50ms apart", elapsed)
+	}
+}
+
 func TestCommandArgsFromEnv(t *testing.T) {
 	t.Setenv("SCRAPELAB_EXTERNAL_STEALTH_COMMAND_ARGS", `["python","scripts/fetch.py","{url}","--headless"]`)
 
@@ -0,0 +1,97 @@
+package scraper
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// detailCache is a size-bounded LRU of detail-page results with a TTL, so a
+// deal that hasn't changed between runs can skip its detail HTTP request.
+// Entries hold cachedDetail results only.
+// It is shared by the concurrent detail-fetch goroutines.
+type detailCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	order   *list.List // front = most recently used
+	items   map[string]*list.Element
+	now     func() time.Time
+}
+
+type detailCacheEntry struct {
+	key       string
+	detail    dealDetailResult
+	expiresAt time.Time
+}
+
+func newDetailCache(maxSize int, ttl time.Duration) *detailCache {
+	return &detailCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		items:   make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// cachedDetail strips what changes while a thread is live: comment counts,
+// the post text and comments. A cache hit reuses the resolved link, prices
+// and labels, and leaves counts to the list and text to what the deal holds.
+func cachedDetail(detail dealDetailResult) dealDetailResult {
+	detail.Description = ""
+	detail.Comments = ""
+	detail.JSONLDCommentCount = 0
+	detail.PageCommentCount = 0
+	return detail
+}
+
+// detailCacheKey includes the title so an edited thread is re-fetched.
+func detailCacheKey(postURL, title string) string {
+	return postURL + "\x00" + title
+}
+
+func (c *detailCache) get(key string) (dealDetailResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return dealDetailResult{}, false
+	}
+	entry := elem.Value.(*detailCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return dealDetailResult{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.detail, true
+}
+
+func (c *detailCache) put(key string, detail dealDetailResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*detailCacheEntry)
+		entry.detail = detail
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&detailCacheEntry{key: key, detail: detail, expiresAt: expiresAt})
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*detailCacheEntry).key)
+	}
+}
+
+func (c *detailCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -1,6 +1,9 @@
 package scraper
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // JSONLDDiscussionForumPosting represents the structure of the JSON-LD data
 // embedded in RedFlagDeals topic pages.
@@ -10,10 +13,44 @@ type JSONLDDiscussionForumPosting struct {
 	Headline      string          `json:"headline"`
 	Text          string          `json:"text"` // The main post content
 	DatePublished time.Time       `json:"datePublished"`
+	CommentCount  int             `json:"commentCount"`
+	Author        JSONLDAuthor    `json:"author"`
 	Comment       []JSONLDComment `json:"comment"`
 	About         *JSONLDProduct  `json:"about,omitempty"`
 }
 
+// JSONLDAuthor is the posting's author. Schema.org allows a Person object, a
+// list of them, or a bare name; the first name found is kept and any other
+// shape is ignored rather than failing the whole posting.
+type JSONLDAuthor struct {
+	Name string
+}
+
+func (a *JSONLDAuthor) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		a.Name = name
+		return nil
+	}
+	var person struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &person); err == nil {
+		a.Name = person.Name
+		return nil
+	}
+	var people []JSONLDAuthor
+	if err := json.Unmarshal(data, &people); err == nil {
+		for _, p := range people {
+			if p.Name != "" {
+				a.Name = p.Name
+				break
+			}
+		}
+	}
+	return nil
+}
+
 type JSONLDComment struct {
 	Type          string    `json:"@type"` // Should be "comment"
 	Text          string    `json:"text"`
@@ -45,3 +82,22 @@ type JSONLDAggregateRating struct {
 	RatingValue interface{} `json:"ratingValue"` // Can be string or float
 	RatingCount interface{} `json:"ratingCount"` // Can be string or int
 }
+
+// parseDiscussionPosting finds the DiscussionForumPosting in one JSON-LD
+// script body, which may hold a single object or an array of them.
+func parseDiscussionPosting(text string) (JSONLDDiscussionForumPosting, bool) {
+	var postings []JSONLDDiscussionForumPosting
+	if err := json.Unmarshal([]byte(text), &postings); err != nil {
+		var single JSONLDDiscussionForumPosting
+		if err := json.Unmarshal([]byte(text), &single); err != nil {
+			return JSONLDDiscussionForumPosting{}, false
+		}
+		postings = []JSONLDDiscussionForumPosting{single}
+	}
+	for _, p := range postings {
+		if p.Type == "DiscussionForumPosting" {
+			return p, true
+		}
+	}
+	return JSONLDDiscussionForumPosting{}, false
+}
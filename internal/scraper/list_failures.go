@@ -0,0 +1,77 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+)
+
+// rfdMaxFailureCooldown caps the escalating pause between list scrapes.
+const rfdMaxFailureCooldown = time.Hour
+
+// listFailureTracker remembers consecutive failed list scrapes across runs.
+// Once alertAfter failures have happened in a row it pauses list scrapes for
+// baseCooldown, doubling for each further failure, so a blocked bot stops
+// hammering RFD. A successful scrape resets it.
+type listFailureTracker struct {
+	mu            sync.Mutex
+	alertAfter    int
+	baseCooldown  time.Duration
+	failures      int
+	cooldownUntil time.Time
+	now           func() time.Time
+}
+
+func newListFailureTracker(alertAfter int, baseCooldown time.Duration) *listFailureTracker {
+	return &listFailureTracker{
+		alertAfter:   alertAfter,
+		baseCooldown: baseCooldown,
+		now:          time.Now,
+	}
+}
+
+// coolingDown reports whether list scrapes are paused, and until when.
+func (t *listFailureTracker) coolingDown() (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cooldownUntil, t.now().Before(t.cooldownUntil)
+}
+
+// recordFailure counts a failed scrape and returns the consecutive failure
+// count, the cooldown it started (0 for none) and whether this failure is the
+// one that crossed the alert threshold.
+func (t *listFailureTracker) recordFailure() (failures int, cooldown time.Duration, alert bool) {
+	if t == nil {
+		return 0, 0, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures++
+	if t.alertAfter <= 0 || t.failures < t.alertAfter {
+		return t.failures, 0, false
+	}
+	if t.baseCooldown > 0 {
+		cooldown = t.baseCooldown
+		for i := t.alertAfter; i < t.failures && cooldown < rfdMaxFailureCooldown; i++ {
+			cooldown *= 2
+		}
+		cooldown = min(cooldown, rfdMaxFailureCooldown)
+		t.cooldownUntil = t.now().Add(cooldown)
+	}
+	return t.failures, cooldown, t.failures == t.alertAfter
+}
+
+// recordSuccess resets the tracker and returns how many failures preceded it.
+func (t *listFailureTracker) recordSuccess() int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures := t.failures
+	t.failures = 0
+	t.cooldownUntil = time.Time{}
+	return failures
+}
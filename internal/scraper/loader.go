@@ -14,34 +14,43 @@ var embeddedSelectors embed.FS
 // LoadConfig tries to load selectors in the following order:
 // 1. Embedded selectors.json
 // 2. External file defined by SELECTORS_CONFIG_PATH (or default "config/selectors.json")
-// 3. Hardcoded defaults (if all else fails, though this function returns error in that case, caller handles fallback)
+// 3. Hardcoded defaults
+// A source that fails to parse or validate falls through to the next one.
 func LoadConfig() (SelectorConfig, error) {
-	// 1. Try embedded
 	data, err := embeddedSelectors.ReadFile("selectors.json")
-	if err == nil {
-		sel, parseErr := LoadSelectorsFromBytes(data)
+	if err != nil {
+		slog.Warn("Embedded selectors missing. Trying file fallback.", "error", err)
+	}
+	return loadSelectorsWithFallback(data, selectorsConfigPath()), nil
+}
+
+func selectorsConfigPath() string {
+	if path := os.Getenv("SELECTORS_CONFIG_PATH"); path != "" {
+		return path
+	}
+	return "config/selectors.json"
+}
+
+func loadSelectorsWithFallback(embedded []byte, configPath string) SelectorConfig {
+	// 1. Try embedded
+	if len(embedded) > 0 {
+		sel, parseErr := LoadSelectorsFromBytes(embedded)
 		if parseErr == nil {
 			slog.Debug("Loaded selectors from embedded config.")
-			return sel, nil
+			return sel
 		}
 		slog.Warn("Embedded selectors failed to parse. Trying file fallback.", "error", parseErr)
 	}
 
 	// 2. Fallback to external file
-	configPath := os.Getenv("SELECTORS_CONFIG_PATH")
-	if configPath == "" {
-		configPath = "config/selectors.json"
-	}
-
-	// Try loading from file
 	if fileSel, err := LoadSelectors(configPath); err == nil {
 		slog.Info("Loaded selectors from external file", "path", configPath)
-		return fileSel, nil
+		return fileSel
 	} else {
 		slog.Warn("Failed to load external selectors, falling back to defaults", "path", configPath, "error", err)
 	}
 
 	// 3. Fallback to hardcoded defaults
 	logger.Notice("Using hardcoded default selectors")
-	return DefaultSelectors(), nil
+	return DefaultSelectors()
 }
@@ -2,16 +2,18 @@ package scraper
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/sync/errgroup"
@@ -27,21 +29,89 @@ const (
 	rfdListDNSMaxRetries      = 6
 	rfdDetailConcurrency      = 2
 	rfdDetailMaxRetries       = 2
+	rfdDetailTimeout          = 10 * time.Second
+	rfdMaxRedirects           = 5
 )
 
+// httpDoer is the subset of *http.Client the scraper needs, so transports can
+// be wrapped (caching, tracing) or replaced with fixtures in tests.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 type Client struct {
-	httpClient *http.Client
+	httpClient httpDoer
 	config     *config.Config
-	selectors  SelectorConfig
-	baseURL    string // overrides hotDealsURL when set (used for testing)
+	// selectorsMu guards selectors, which ReloadSelectors swaps at runtime.
+	selectorsMu sync.RWMutex
+	selectors   SelectorConfig
+	baseURL     string       // overrides hotDealsURL when set (used for testing)
+	details     *detailCache // nil when RFDDetailCacheSize is 0
+	failures    *listFailureTracker
+	hosts       *util.HostLimiter
+	alerter     logger.Alerter
+	// canonicalHosts is util.DefaultCanonicalHosts plus CANONICAL_HOSTS;
+	// nil means the defaults.
+	canonicalHosts map[string]string
+	// pageFloor is the newest stored deal's publish time; hot-list paging
+	// stops at the page that reaches it. Set by the processor each run.
+	pageFloorMu sync.Mutex
+	pageFloor   time.Time
 }
 
 func New(cfg *config.Config, selectors SelectorConfig) *Client {
-	return &Client{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		config:     cfg,
-		selectors:  selectors,
+	c := &Client{
+		config:    cfg,
+		selectors: selectors,
+		hosts:     util.NewHostLimiter(cfg.HostRateLimit),
+		failures:  newListFailureTracker(cfg.RFDFailureAlertAfter, cfg.RFDFailureCooldown),
+		alerter:   logger.LogAlerter{},
+	}
+	c.httpClient = &http.Client{Timeout: 30 * time.Second, CheckRedirect: c.checkRedirect}
+	if cfg.RFDDetailCacheSize > 0 && cfg.RFDDetailCacheTTL > 0 {
+		c.details = newDetailCache(cfg.RFDDetailCacheSize, cfg.RFDDetailCacheTTL)
+	}
+	if len(cfg.CanonicalHosts) > 0 {
+		c.canonicalHosts = maps.Clone(util.DefaultCanonicalHosts)
+		maps.Copy(c.canonicalHosts, cfg.CanonicalHosts)
+	}
+	return c
+}
+
+// SetAlerter routes scraper alerts (repeated list failures) to alerter
+// instead of the log. nil restores logging.
+func (c *Client) SetAlerter(alerter logger.Alerter) {
+	if alerter == nil {
+		alerter = logger.LogAlerter{}
+	}
+	c.alerter = alerter
+}
+
+// currentSelectors returns the selectors in use; a run that started before a
+// reload keeps whichever copy it read.
+func (c *Client) currentSelectors() SelectorConfig {
+	c.selectorsMu.RLock()
+	defer c.selectorsMu.RUnlock()
+	return c.selectors
+}
+
+// ReloadSelectors re-reads the external selectors file (SELECTORS_CONFIG_PATH)
+// and swaps it in, so broken selectors can be fixed without a deploy. The
+// current selectors stay in place if the file is missing or invalid.
+func (c *Client) ReloadSelectors() (SelectorConfig, error) {
+	return c.reloadSelectorsFrom(selectorsConfigPath())
+}
+
+func (c *Client) reloadSelectorsFrom(path string) (SelectorConfig, error) {
+	selectors, err := LoadSelectors(path)
+	if err != nil {
+		return SelectorConfig{}, fmt.Errorf("reload selectors from %s: %w", path, err)
 	}
+	c.selectorsMu.Lock()
+	c.selectors = selectors
+	c.selectorsMu.Unlock()
+	slog.Info("Reloaded selectors", "processor", "rfd", "path", path)
+	return selectors, nil
 }
 
 // NewWithBaseURL creates a scraper Client that uses the given base URL
@@ -52,12 +122,72 @@ func NewWithBaseURL(cfg *config.Config, selectors SelectorConfig, baseURL string
 	return c
 }
 
+// rfdSortKeys maps RFD_SORT modes to the forum's sort key (sk/rfd_sk).
+var rfdSortKeys = map[string]string{
+	"newest":  "tt",
+	"replies": "r",
+	"views":   "v",
+}
+
+// SetPageFloor records the newest stored deal's publish time, so paging
+// through the hot list (RFD_MAX_PAGES) can stop once it reaches known deals.
+// A zero time pages up to the limit.
+func (c *Client) SetPageFloor(t time.Time) {
+	c.pageFloorMu.Lock()
+	defer c.pageFloorMu.Unlock()
+	c.pageFloor = t
+}
+
+func (c *Client) currentPageFloor() time.Time {
+	c.pageFloorMu.Lock()
+	defer c.pageFloorMu.Unlock()
+	return c.pageFloor
+}
+
+// Forum slugs of the lists scraped every run; deals are tagged with them.
+const (
+	hotDealsForum     = "hot-deals-f9"
+	expiredDealsForum = "expired-hot-deals-f68"
+)
+
+// hotDealsListURL composes the hot-deals list URL for a sort mode, always
+// descending; unknown modes fall back to newest.
+func hotDealsListURL(baseURL, sort string) string {
+	return forumListURL(baseURL, hotDealsForum, sort)
+}
+
+// expiredDealsListURL is the Expired Hot Deals forum, sorted like the hot list.
+func expiredDealsListURL(baseURL, sort string) string {
+	return forumListURL(baseURL, expiredDealsForum, sort)
+}
+
+func forumListURL(baseURL, forum, sort string) string {
+	key, ok := rfdSortKeys[sort]
+	if !ok {
+		key = rfdSortKeys["newest"]
+	}
+	return fmt.Sprintf("%s/%s/?sk=%s&rfd_sk=%s&sd=d", baseURL, forum, key, key)
+}
+
+// listPageURL adds the forum's p=N pagination parameter to a list URL.
+func listPageURL(listURL string, page int) string {
+	sep := "?"
+	if strings.Contains(listURL, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%sp=%d", listURL, sep, page)
+}
+
 func (c *Client) ScrapeDealList(ctx context.Context) ([]models.DealInfo, error) {
-	targetURL := c.config.RFDBaseURL + "/hot-deals-f9/?sk=tt&rfd_sk=tt&sd=d"
+	targetURL := hotDealsListURL(c.config.RFDBaseURL, c.config.RFDSort)
 	if c.baseURL != "" {
 		targetURL = c.baseURL + "/hot-deals"
 	}
 
+	if until, paused := c.failures.coolingDown(); paused {
+		return nil, fmt.Errorf("skipping hot deals list scrape until %s after repeated failures", until.UTC().Format(time.RFC3339))
+	}
+
 	slog.Info("Scraping RFD Hot Deals list...", "processor", "rfd", "url", targetURL)
 
 	var scrapedDeals []models.DealInfo
@@ -84,14 +214,193 @@ func (c *Client) ScrapeDealList(ctx context.Context) ([]models.DealInfo, error)
 	})
 
 	if err != nil {
-		logger.Critical("All retry attempts failed for ScrapeDealList", "error", err)
+		if ctx.Err() == nil {
+			c.alerter.Alert(ctx, logger.LevelCritical, "All retry attempts failed for ScrapeDealList", "processor", "rfd", "error", err)
+			c.recordListFailure(ctx, err)
+		} else {
+			logger.Critical("All retry attempts failed for ScrapeDealList", "error", err)
+		}
 		return nil, fmt.Errorf("failed to scrape hot deals list: %w", err)
 	}
 
+	if failures := c.failures.recordSuccess(); failures > 0 {
+		logger.Notice("RFD list scrape recovered", "consecutive_failures", failures)
+	}
+	scrapedDeals = c.scrapeNextPages(ctx, targetURL, scrapedDeals)
+	tagForum(scrapedDeals, hotDealsForum)
+	if c.config.RFDIncludeExpired {
+		scrapedDeals = appendForumDeals(scrapedDeals, c.scrapeExpiredList(ctx))
+	}
+	for _, forum := range c.config.RFDExtraForums {
+		scrapedDeals = appendForumDeals(scrapedDeals, c.scrapeExtraForum(ctx, forum))
+	}
 	logger.Notice("Scrape completed", "duration", time.Since(start), "deals", len(scrapedDeals))
 	return scrapedDeals, nil
 }
 
+// scrapeNextPages follows the hot list's pagination after page one, up to
+// RFD_MAX_PAGES in all. Later pages are best-effort: paging stops at the first
+// page that fails or has no topics, and, when sorted by newest, after a page
+// reaching deals no newer than the page floor, since the rest are stored.
+// Detail pages are fetched later for the whole list at once, so paging
+// doesn't raise detail-fetch concurrency.
+func (c *Client) scrapeNextPages(ctx context.Context, targetURL string, deals []models.DealInfo) []models.DealInfo {
+	floor := c.currentPageFloor()
+	byNewest := c.config.RFDSort == "" || c.config.RFDSort == "newest"
+	for page := 2; page <= c.config.RFDMaxPages; page++ {
+		if byNewest && reachesPageFloor(deals, floor) {
+			slog.Info("Stopping hot deals paging at stored deals", "processor", "rfd", "pages", page-1)
+			break
+		}
+		pageDeals, err := c.attemptScrapeList(ctx, listPageURL(targetURL, page))
+		if err != nil {
+			slog.Warn("Failed to scrape hot deals page, keeping earlier pages", "processor", "rfd", "page", page, "error", err)
+			break
+		}
+		if len(pageDeals) == 0 {
+			break
+		}
+		deals = append(deals, pageDeals...)
+	}
+	return deals
+}
+
+// reachesPageFloor reports whether any deal was published at or before floor.
+// A zero floor (nothing stored) is never reached.
+func reachesPageFloor(deals []models.DealInfo, floor time.Time) bool {
+	if floor.IsZero() {
+		return false
+	}
+	for _, deal := range deals {
+		if !deal.PublishedTimestamp.IsZero() && !deal.PublishedTimestamp.After(floor) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapeExpiredList scrapes the Expired Hot Deals forum once and marks its
+// deals Expired. It is best-effort: a failure is logged and the run goes on
+// with the active deals.
+func (c *Client) scrapeExpiredList(ctx context.Context) []models.DealInfo {
+	targetURL := expiredDealsListURL(c.config.RFDBaseURL, c.config.RFDSort)
+	if c.baseURL != "" {
+		targetURL = c.baseURL + "/expired-hot-deals"
+	}
+	deals, err := c.attemptScrapeList(ctx, targetURL)
+	if err != nil {
+		slog.Warn("Failed to scrape expired deals list, continuing without it", "processor", "rfd", "error", err)
+		return nil
+	}
+	for i := range deals {
+		deals[i].Expired = true
+	}
+	tagForum(deals, expiredDealsForum)
+	return deals
+}
+
+// scrapeExtraForum scrapes one RFD_EXTRA_FORUMS list. Like the expired list
+// it is best-effort, so one broken forum doesn't cost the others.
+func (c *Client) scrapeExtraForum(ctx context.Context, forum string) []models.DealInfo {
+	targetURL := forumListURL(c.config.RFDBaseURL, forum, c.config.RFDSort)
+	if c.baseURL != "" {
+		targetURL = c.baseURL + "/" + forum
+	}
+	deals, err := c.attemptScrapeList(ctx, targetURL)
+	if err != nil {
+		slog.Warn("Failed to scrape extra forum, continuing without it", "processor", "rfd", "forum", forum, "error", err)
+		return nil
+	}
+	tagForum(deals, forum)
+	return deals
+}
+
+func tagForum(deals []models.DealInfo, forum string) {
+	for i := range deals {
+		deals[i].Forum = forum
+	}
+}
+
+// appendForumDeals adds another forum's deals, skipping threads an earlier
+// forum already returned this run (PostURLs are normalized when parsed).
+// Repeats within one list are left for the processor's own dedupe.
+func appendForumDeals(deals, forumDeals []models.DealInfo) []models.DealInfo {
+	seen := make(map[string]bool, len(deals))
+	for _, deal := range deals {
+		seen[deal.PostURL] = true
+	}
+	for _, deal := range forumDeals {
+		if seen[deal.PostURL] {
+			slog.Debug("Skipping thread already scraped from another forum", "processor", "rfd", "forum", deal.Forum, "url", deal.PostURL)
+			continue
+		}
+		deals = append(deals, deal)
+	}
+	return deals
+}
+
+// recordListFailure counts a failed list scrape across runs, alerting once the
+// RFD_FAILURE_ALERT_AFTER threshold is crossed and logging each escalation.
+func (c *Client) recordListFailure(ctx context.Context, err error) {
+	failures, cooldown, alert := c.failures.recordFailure()
+	if alert {
+		c.alerter.Alert(ctx, logger.LevelCritical, "RFD list scrape failing repeatedly; RFD may be blocking us",
+			"processor", "rfd",
+			"consecutive_failures", failures,
+			"cooldown", cooldown,
+			"error", err,
+		)
+		return
+	}
+	if cooldown > 0 {
+		slog.Warn("RFD list scrape still failing; extending cooldown",
+			"processor", "rfd",
+			"consecutive_failures", failures,
+			"cooldown", cooldown,
+		)
+	}
+}
+
+// thumbnailJunkHints mark image URLs that are tracking pixels, spacers or
+// sprites rather than product photos. They are specific enough not to catch
+// products like a Google Pixel.
+var thumbnailJunkHints = []string{"pixel.gif", "pixel.png", "spacer.gif", "blank.gif", "transparent.gif", "1x1.", "sprite", "/tracking", "/beacon"}
+
+// minThumbnailSide is the smallest declared width or height accepted for a
+// thumbnail; smaller images are icons or pixels.
+const minThumbnailSide = 32
+
+// bestThreadImage picks the thumbnail from a card's images: only http(s)
+// URLs, lazy-loaded data-src preferred over the src placeholder, junk URLs
+// and images declared smaller than minThumbnailSide skipped, and the largest
+// declared area winning (the first on ties or when no sizes are given).
+func bestThreadImage(images *goquery.Selection) string {
+	best, bestArea := "", -1
+	images.Each(func(_ int, img *goquery.Selection) {
+		src := strings.TrimSpace(img.AttrOr("data-src", ""))
+		if src == "" {
+			src = strings.TrimSpace(img.AttrOr("src", ""))
+		}
+		if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+			return
+		}
+		lower := strings.ToLower(src)
+		for _, hint := range thumbnailJunkHints {
+			if strings.Contains(lower, hint) {
+				return
+			}
+		}
+		width, height := util.SafeAtoi(img.AttrOr("width", "")), util.SafeAtoi(img.AttrOr("height", ""))
+		if (width > 0 && width < minThumbnailSide) || (height > 0 && height < minThumbnailSide) {
+			return
+		}
+		if area := width * height; area > bestArea {
+			best, bestArea = src, area
+		}
+	})
+	return best
+}
+
 func shouldStopRFDListRetry(attempt int, err error) bool {
 	return err != nil && attempt >= rfdListStandardMaxRetries && !isTransientDNSFailure(err)
 }
@@ -153,7 +462,7 @@ func (c *Client) attemptScrapeList(ctx context.Context, targetURL string) ([]mod
 		return nil, fmt.Errorf("failed to fetch or parse hot deals page %s: %w", targetURL, err)
 	}
 
-	ls := c.selectors.HotDealsList
+	ls := c.currentSelectors().HotDealsList
 
 	if doc.Find(ls.Container.Item).Length() == 0 {
 		return nil, fmt.Errorf("no '%s' elements found on %s. Potential block or page structure change", ls.Container.Item, targetURL)
@@ -181,7 +490,9 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 	var thread models.ThreadContext
 	var parseErrors []string
 
-	// Published Timestamp from <time datetime="...">
+	// Published Timestamp from <time datetime="...">, falling back to the
+	// displayed text ("2 hours ago", "Jun 1, 2025") when there is no
+	// machine-readable datetime.
 	timeSelection := s.Find(elems.PostedTime)
 	if timeSelection.Length() > 0 {
 		actualTime := timeSelection
@@ -193,12 +504,20 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 				if parsed, err := time.Parse(time.RFC3339, datetimeStr); err == nil {
 					deal.PublishedTimestamp = parsed
 				} else {
-					parseErrors = append(parseErrors, fmt.Sprintf("failed to parse datetime '%s': %v", datetimeStr, err))
+					parseErrors = append(parseErrors, fmt.Sprintf("posted_time: failed to parse datetime '%s': %v", datetimeStr, err))
 				}
 			}
 		}
+		if deal.PublishedTimestamp.IsZero() {
+			text := strings.TrimSpace(timeSelection.Text())
+			if parsed, ok := util.ParseRelativeTime(text, time.Now()); ok {
+				deal.PublishedTimestamp = parsed
+			} else if text != "" {
+				parseErrors = append(parseErrors, fmt.Sprintf("posted_time: unrecognized time text '%s'", text))
+			}
+		}
 	} else {
-		parseErrors = append(parseErrors, "posted time element not found")
+		parseErrors = append(parseErrors, "posted_time: element not found")
 	}
 
 	// Title & Post URL
@@ -215,7 +534,7 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 	if title != "" {
 		deal.Title = title
 		if postURL != "" {
-			normalized, err := util.NormalizeURL(postURL, c.config.AllowedDomains)
+			normalized, err := util.NormalizeURL(postURL, c.config.AllowedDomains, c.canonicalHosts)
 			if err == nil {
 				postURL = normalized
 			} else {
@@ -225,7 +544,7 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 		deal.PostURL = postURL
 		thread.PostURL = postURL
 	} else {
-		parseErrors = append(parseErrors, "title/post URL element not found")
+		parseErrors = append(parseErrors, "title: title/post URL element not found")
 	}
 
 	// Retailer (Store)
@@ -243,17 +562,21 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 			deal.Retailer = cleanRetailerName(retailerAttr)
 		}
 	}
+	if deal.Retailer == "" {
+		// Last resort: most RFD titles lead with "[Store]".
+		deal.Retailer = cleanRetailerName(util.ExtractStoreFromTitle(deal.Title))
+	}
 
-	// Thread Image — only accept http/https URLs
-	imgSelection := s.Find(elems.ThreadImage)
-	if imgSelection.Length() > 0 {
-		if src, exists := imgSelection.Attr("src"); exists {
-			if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
-				deal.ThreadImageURL = src
-			}
+	// Author
+	if elems.AuthorName != "" {
+		if authorSel := s.Find(elems.AuthorName); authorSel.Length() > 0 {
+			deal.AuthorName = strings.TrimSpace(authorSel.First().Text())
 		}
 	}
 
+	// Thread Image — the best of the card's images
+	deal.ThreadImageURL = bestThreadImage(s.Find(elems.ThreadImage))
+
 	// Like Count
 	likeCountSelection := s.Find(elems.LikeCount)
 	if likeCountSelection.Length() > 0 {
@@ -294,6 +617,7 @@ func (c *Client) parseDealFromSelection(s *goquery.Selection, elems ListElements
 	deal.Threads = []models.ThreadContext{thread}
 
 	if len(parseErrors) > 0 {
+		deal.ParseWarnings = parseErrors
 		slog.Warn("Parsing issues for deal", "processor", "rfd", "title", deal.Title, "url", deal.PrimaryPostURL(), "errors", strings.Join(parseErrors, "; "))
 	}
 	return deal
@@ -307,6 +631,7 @@ func (c *Client) FetchDealDetails(ctx context.Context, deals []*models.DealInfo)
 	var succeeded atomic.Int32
 	var failed atomic.Int32
 	var notFound atomic.Int32
+	var cached atomic.Int32
 
 	for i := range deals {
 		deal := deals[i] // explicit local copy for clarity in the closure
@@ -316,9 +641,27 @@ func (c *Client) FetchDealDetails(ctx context.Context, deals []*models.DealInfo)
 		attempted.Add(1)
 
 		g.Go(func() error {
-			detail, err := c.scrapeDealDetailPageWithRetry(ctx, deal.PrimaryPostURL())
+			cacheKey := detailCacheKey(deal.PrimaryPostURL(), deal.Title)
+			if c.details != nil {
+				if detail, ok := c.details.get(cacheKey); ok {
+					cached.Add(1)
+					succeeded.Add(1)
+					c.applyDealDetail(deal, detail)
+					return nil
+				}
+			}
+
+			// Bound each deal separately so one slow page can't hold a
+			// concurrency slot for the whole HTTP client timeout.
+			dealCtx, cancel := context.WithTimeout(ctx, c.detailTimeout())
+			defer cancel()
+
+			detail, err := c.scrapeDealDetailPageWithRetry(dealCtx, deal.PrimaryPostURL())
 			if err != nil {
-				if strings.Contains(err.Error(), "status code 404") {
+				if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+					failed.Add(1)
+					slog.Warn("Detail page fetch timed out, skipping", "processor", "rfd", "url", deal.PrimaryPostURL(), "timeout", c.detailTimeout())
+				} else if strings.Contains(err.Error(), "status code 404") {
 					notFound.Add(1)
 					markPrimaryThreadNotFound(deal)
 					slog.Info("Failed to fetch detail page (404)", "processor", "rfd", "url", deal.PrimaryPostURL())
@@ -329,32 +672,10 @@ func (c *Client) FetchDealDetails(ctx context.Context, deals []*models.DealInfo)
 				return nil
 			}
 			succeeded.Add(1)
-
-			deal.ActualDealURL = detail.DealLink
-			deal.Description = detail.Description
-			deal.Comments = detail.Comments
-			deal.Summary = detail.Summary
-			deal.Price = detail.Price
-			deal.OriginalPrice = detail.OriginalPrice
-			deal.Savings = detail.Savings
-			if detail.Retailer != "" {
-				deal.Retailer = detail.Retailer
-			}
-			if detail.Category != "" {
-				deal.Category = detail.Category
-			}
-
-			if deal.ActualDealURL != "" {
-				slog.Debug("Original Product URL", "processor", "rfd", "url", deal.ActualDealURL)
-				deal.ActualDealURL = util.CleanProductURL(deal.ActualDealURL)
-				slog.Debug("Cleaned Product URL", "processor", "rfd", "url", deal.ActualDealURL)
-				cleanedURL, changed := util.CleanReferralLink(deal.ActualDealURL, c.config.AmazonAffiliateTag, c.config.BestBuyAffiliatePrefix)
-				if changed {
-					deal.ActualDealURL = cleanedURL
-				}
-			} else {
-				slog.Info("No external deal link found", "processor", "rfd", "postURL", deal.PrimaryPostURL())
+			if c.details != nil {
+				c.details.put(cacheKey, cachedDetail(detail))
 			}
+			c.applyDealDetail(deal, detail)
 			return nil
 		})
 	}
@@ -368,6 +689,9 @@ func (c *Client) FetchDealDetails(ctx context.Context, deals []*models.DealInfo)
 		Failed:    int(failed.Load()),
 		NotFound:  int(notFound.Load()),
 	}
+	if hits := cached.Load(); hits > 0 {
+		slog.Info("Reused cached detail pages", "processor", "rfd", "hits", hits)
+	}
 	if stats.Failed > 0 || stats.NotFound > 0 {
 		slog.Warn("FetchDealDetails summary",
 			"processor", "rfd",
@@ -381,6 +705,91 @@ func (c *Client) FetchDealDetails(ctx context.Context, deals []*models.DealInfo)
 	return stats
 }
 
+// applyDealDetail copies scraped detail fields onto the deal and cleans the
+// external deal link.
+func (c *Client) applyDealDetail(deal *models.DealInfo, detail dealDetailResult) {
+	c.applyMovedThread(deal, detail.FinalURL)
+
+	deal.ActualDealURL = detail.DealLink
+	// Cached details carry no text (cachedDetail); keep what the deal has.
+	if detail.Description != "" {
+		deal.Description = detail.Description
+	}
+	if detail.Comments != "" {
+		deal.Comments = detail.Comments
+	}
+	deal.Summary = detail.Summary
+	deal.Price = detail.Price
+	deal.OriginalPrice = detail.OriginalPrice
+	deal.Savings = detail.Savings
+	if detail.Retailer != "" {
+		deal.Retailer = detail.Retailer
+	}
+	if detail.Category != "" {
+		deal.Category = detail.Category
+	}
+	// JSON-LD names the original poster more reliably than the list card.
+	// The published time only fills a gap: it keys the deal's document ID,
+	// which is assigned before details are fetched.
+	if detail.Author != "" {
+		deal.AuthorName = detail.Author
+	}
+	if deal.PublishedTimestamp.IsZero() && !detail.Published.IsZero() {
+		deal.PublishedTimestamp = detail.Published
+	}
+	if len(deal.Threads) > 0 {
+		deal.Threads[0].CommentCount = preferredCommentCount(c.config.CommentCountSources, deal.Threads[0].CommentCount, detail)
+	}
+
+	if deal.ActualDealURL != "" {
+		slog.Debug("Original Product URL", "processor", "rfd", "url", deal.ActualDealURL)
+		deal.ActualDealURL = util.CleanProductURL(deal.ActualDealURL)
+		slog.Debug("Cleaned Product URL", "processor", "rfd", "url", deal.ActualDealURL)
+		cleanedURL, changed := util.CleanReferralLink(deal.ActualDealURL, util.AffiliateConfig{
+			AmazonTag:          c.config.AmazonAffiliateTag,
+			AmazonTags:         c.config.AmazonAffiliateTags,
+			BestBuyPrefix:      c.config.BestBuyAffiliatePrefix,
+			NoAffiliateDomains: c.config.NoAffiliateDomains,
+		})
+		if changed {
+			deal.ActualDealURL = cleanedURL
+		}
+		deal.ActualDealURL = util.NormalizeDealURL(deal.ActualDealURL)
+	} else {
+		slog.Info("No external deal link found", "processor", "rfd", "postURL", deal.PrimaryPostURL())
+	}
+}
+
+// preferredCommentCount picks the comment count from the first source in
+// sources (COMMENT_COUNT_SOURCES) that reported one. A count of 0 is treated
+// as missing, since a broken selector reads the same as an empty thread; the
+// list count is kept when no source has a value.
+func preferredCommentCount(sources []string, listCount int, detail dealDetailResult) int {
+	for _, source := range sources {
+		var count int
+		switch source {
+		case "list":
+			count = listCount
+		case "jsonld":
+			count = detail.JSONLDCommentCount
+		case "detail":
+			count = detail.PageCommentCount
+		}
+		if count > 0 {
+			return count
+		}
+	}
+	return listCount
+}
+
+// detailTimeout returns the per-deal detail fetch budget.
+func (c *Client) detailTimeout() time.Duration {
+	if c.config.RFDDetailTimeout > 0 {
+		return c.config.RFDDetailTimeout
+	}
+	return rfdDetailTimeout
+}
+
 func (c *Client) scrapeDealDetailPageWithRetry(ctx context.Context, dealURL string) (dealDetailResult, error) {
 	var detail dealDetailResult
 	err := util.RetryWithBackoff(ctx, rfdDetailMaxRetries, func(attempt int) error {
@@ -459,8 +868,82 @@ func isExternalDealLink(raw string) bool {
 	return !strings.Contains(strings.ToLower(parsed.Hostname()), "redflagdeals.com")
 }
 
+// dealLinkAttrs are checked in order on each candidate element; some RFD
+// buttons carry the target in a data attribute instead of href.
+var dealLinkAttrs = []string{"href", "data-deal-url", "data-href", "data-url"}
+
+// firstExternalDealLink tries each selector in order and returns the first
+// external deal link found on any matching element.
+func firstExternalDealLink(doc *goquery.Document, selectors SelectorList) string {
+	for _, sel := range selectors {
+		var link string
+		doc.Find(sel).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			for _, attr := range dealLinkAttrs {
+				if v, ok := s.Attr(attr); ok && isExternalDealLink(v) {
+					link = strings.TrimSpace(v)
+					return false
+				}
+			}
+			return true
+		})
+		if link != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+// redirectLinkHosts are affiliate/tracking wrappers that sit in front of the
+// real retailer URL.
+var redirectLinkHosts = []string{
+	"click.linksynergy.com",
+	"go.redirectingat.com",
+	"bestbuyca.o93x.net",
+}
+
+func isRedirectDealLink(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, h := range redirectLinkHosts {
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseDealLink reconciles the primary and fallback links. When both are
+// present but point at different products the mismatch is logged so selector
+// drift shows up, and policy decides which one wins.
+func chooseDealLink(postURL, policy, primary, fallback string) string {
+	if primary == "" {
+		return fallback
+	}
+	if fallback == "" || util.CleanProductURL(primary) == util.CleanProductURL(fallback) {
+		return primary
+	}
+
+	chosen := primary
+	if policy == LinkPolicyPreferExternal && isRedirectDealLink(primary) && !isRedirectDealLink(fallback) {
+		chosen = fallback
+	}
+	slog.Warn("Primary and fallback deal links disagree",
+		"processor", "rfd",
+		"postURL", postURL,
+		"primary", primary,
+		"fallback", fallback,
+		"policy", policy,
+		"chosen", chosen,
+	)
+	return chosen
+}
+
 // dealDetailResult holds the fields scraped from an RFD deal detail page.
 type dealDetailResult struct {
+	FinalURL      string // post URL after redirects; differs from the request when a thread moved
 	DealLink      string
 	Description   string
 	Comments      string
@@ -470,39 +953,25 @@ type dealDetailResult struct {
 	Savings       string
 	Retailer      string
 	Category      string
+	// Comment counts from the thread page; 0 when the page didn't expose one.
+	JSONLDCommentCount int
+	PageCommentCount   int
+	// Author and Published come from the page's JSON-LD posting, if any.
+	Author    string
+	Published time.Time
 }
 
 func (c *Client) scrapeDealDetailPage(ctx context.Context, dealURL string) (dealDetailResult, error) {
-	doc, err := c.fetchHTMLContent(ctx, dealURL)
+	doc, finalURL, err := c.fetchHTMLPage(ctx, dealURL)
 	if err != nil {
 		return dealDetailResult{}, err
 	}
 
 	// 1. Get Deal Link
-	ds := c.selectors.DealDetails
-	var dealLink string
-
-	// Try primary link first
-	if btn := doc.Find(ds.PrimaryLink); btn.Length() > 0 {
-		if href, found := btn.Attr("href"); found && strings.TrimSpace(href) != "" {
-			trimmed := strings.TrimSpace(href)
-			if isExternalDealLink(trimmed) {
-				dealLink = trimmed
-			}
-		}
-	}
-
-	// Fallback link
-	if dealLink == "" {
-		if link := doc.Find(ds.FallbackLink); link.Length() > 0 {
-			if href, found := link.Attr("href"); found {
-				trimmed := strings.TrimSpace(href)
-				if isExternalDealLink(trimmed) {
-					dealLink = trimmed
-				}
-			}
-		}
-	}
+	ds := c.currentSelectors().DealDetails
+	dealLink := chooseDealLink(dealURL, ds.LinkPolicy,
+		firstExternalDealLink(doc, ds.PrimaryLink),
+		firstExternalDealLink(doc, ds.FallbackLink))
 
 	// No early return — continue extracting metadata (description, category, etc.)
 	// even when no external deal link exists. Many RFD posts (coupons, in-store deals,
@@ -510,47 +979,42 @@ func (c *Client) scrapeDealDetailPage(ctx context.Context, dealURL string) (deal
 
 	var retailer, category string
 
-	// 2. Extract JSON-LD for Description and Comments
+	// 2. Extract JSON-LD for Description, Comments, Author and Date
 	var description, commentsStr string
-	var ldPrice, ldRetailer string
-
-	doc.Find("script[type='application/ld+json']").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		var postings []JSONLDDiscussionForumPosting
-		// Try parsing as array first
-		if err := json.Unmarshal([]byte(text), &postings); err == nil && len(postings) > 0 {
-			for _, p := range postings {
-				if p.Type == "DiscussionForumPosting" { // Case sensitive check might be needed, usually PascalCase
-					description = cleanHTMLText(p.Text)
-
-					var commentTexts []string
-					for _, c := range p.Comment {
-						commentTexts = append(commentTexts, fmt.Sprintf("- %s", cleanHTMLText(c.Text)))
-					}
-					// Truncate comments to avoid huge tokens
-					maxCommentsLen := 2000
-					fullComments := strings.Join(commentTexts, "\n")
-					if len(fullComments) > maxCommentsLen {
-						fullComments = fullComments[:maxCommentsLen] + "...(truncated)"
-					}
-					commentsStr = fullComments
-
-					// Fallback from Product schema in JSON-LD
-					if p.About != nil {
-						if p.About.Offers != nil && p.About.Offers.Price != "" {
-							ldPrice = p.About.Offers.Price
-							if p.About.Offers.PriceCurrency == "CAD" {
-								ldPrice = "$" + ldPrice
-							}
-						}
-						if p.About.Brand != nil && p.About.Brand.Name != "" {
-							ldRetailer = p.About.Brand.Name
-						}
-					}
-					return // Found the main posting
+	var ldPrice, ldRetailer, ldAuthor string
+	var ldCommentCount int
+	var ldPublished time.Time
+
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		p, ok := parseDiscussionPosting(s.Text())
+		if !ok {
+			return true
+		}
+		description = truncateText(cleanHTMLText(p.Text), c.config.MaxDescriptionLen)
+
+		var commentTexts []string
+		for _, c := range p.Comment {
+			commentTexts = append(commentTexts, fmt.Sprintf("- %s", cleanHTMLText(c.Text)))
+		}
+		// Capped to keep stored documents and AI prompts small.
+		commentsStr = truncateText(strings.Join(commentTexts, "\n"), c.config.MaxCommentsLen)
+		ldCommentCount = p.CommentCount
+		ldAuthor = strings.TrimSpace(p.Author.Name)
+		ldPublished = p.DatePublished
+
+		// Fallback from Product schema in JSON-LD
+		if p.About != nil {
+			if p.About.Offers != nil && p.About.Offers.Price != "" {
+				ldPrice = p.About.Offers.Price
+				if p.About.Offers.PriceCurrency == "CAD" {
+					ldPrice = "$" + ldPrice
 				}
 			}
+			if p.About.Brand != nil && p.About.Brand.Name != "" {
+				ldRetailer = p.About.Brand.Name
+			}
 		}
+		return false // Found the main posting
 	})
 
 	// 3. Extract Summary (if available)
@@ -609,19 +1073,55 @@ func (c *Client) scrapeDealDetailPage(ctx context.Context, dealURL string) (deal
 		})
 	}
 
+	var pageCommentCount int
+	if ds.CommentCount != "" {
+		if sel := doc.Find(ds.CommentCount); sel.Length() > 0 {
+			pageCommentCount = util.SafeAtoi(util.CleanNumericString(sel.First().Text()))
+		}
+	}
+
 	return dealDetailResult{
-		DealLink:      dealLink,
-		Description:   description,
-		Comments:      commentsStr,
-		Summary:       summary,
-		Price:         price,
-		OriginalPrice: originalPrice,
-		Savings:       savings,
-		Retailer:      retailer,
-		Category:      category,
+		FinalURL:           finalURL,
+		DealLink:           dealLink,
+		Description:        description,
+		Comments:           commentsStr,
+		Summary:            summary,
+		Price:              price,
+		OriginalPrice:      originalPrice,
+		Savings:            savings,
+		Retailer:           retailer,
+		Category:           category,
+		JSONLDCommentCount: ldCommentCount,
+		PageCommentCount:   pageCommentCount,
+		Author:             ldAuthor,
+		Published:          ldPublished,
 	}, nil
 }
 
+// applyMovedThread records the post URL a thread redirected to, so moved or
+// merged RFD threads are tracked under their current URL.
+func (c *Client) applyMovedThread(deal *models.DealInfo, finalURL string) {
+	if finalURL == "" {
+		return
+	}
+	normalized, err := util.NormalizeURL(finalURL, c.config.AllowedDomains, c.canonicalHosts)
+	if err != nil {
+		return
+	}
+	oldURL := deal.PrimaryPostURL()
+	if normalized == oldURL {
+		return
+	}
+
+	slog.Info("RFD thread redirected", "processor", "rfd", "from", oldURL, "to", normalized)
+	if deal.PostURL == oldURL {
+		deal.PostURL = normalized
+	}
+	if len(deal.Threads) > 0 && deal.Threads[0].PostURL == oldURL {
+		deal.Threads[0].PostURL = normalized
+	}
+}
+
 func cleanRetailerName(raw string) string {
 	retailer := strings.TrimSpace(raw)
 	retailer = strings.Join(strings.Fields(retailer), " ")
@@ -659,6 +1159,16 @@ func collapseRepeatedRetailer(retailer string) string {
 	return retailer
 }
 
+// truncateText cuts s to at most maxLen characters, ending in an ellipsis
+// when cut. maxLen 0 leaves s whole.
+func truncateText(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:maxLen-1]) + "…"
+}
+
 // cleanHTMLText allows stripping HTML tags from a string.
 // It uses goquery to parse the fragment and return text.
 func cleanHTMLText(htmlStr string) string {
@@ -670,44 +1180,72 @@ func cleanHTMLText(htmlStr string) string {
 }
 
 func (c *Client) fetchHTMLContent(ctx context.Context, urlStr string) (*goquery.Document, error) {
+	doc, _, err := c.fetchHTMLPage(ctx, urlStr)
+	return doc, err
+}
+
+// fetchHTMLPage fetches and parses urlStr, also returning the URL the content
+// was finally served from after any redirects (e.g. a moved RFD thread).
+func (c *Client) fetchHTMLPage(ctx context.Context, urlStr string) (*goquery.Document, string, error) {
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL %s: %w", urlStr, err)
+		return nil, "", fmt.Errorf("failed to parse URL %s: %w", urlStr, err)
 	}
 
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return nil, fmt.Errorf("invalid URL scheme %s: only http and https allowed", parsedURL.Scheme)
+		return nil, "", fmt.Errorf("invalid URL scheme %s: only http and https allowed", parsedURL.Scheme)
 	}
 
-	hostname := parsedURL.Hostname()
-	allowed := false
-	for _, domain := range c.config.AllowedDomains {
-		if hostname == domain {
-			allowed = true
-			break
-		}
-	}
-	if !allowed {
-		return nil, fmt.Errorf("security violation: URL hostname %s is not in allowlist", hostname)
+	if hostname := parsedURL.Hostname(); !c.allowedHost(hostname) {
+		return nil, "", fmt.Errorf("security violation: URL hostname %s is not in allowlist", hostname)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for URL %s: %w", urlStr, err)
+		return nil, "", fmt.Errorf("failed to create request for URL %s: %w", urlStr, err)
 	}
 
 	profile := randomProfile()
 	applyStealthHeaders(req, profile)
 
+	if err := c.hosts.Wait(ctx, parsedURL.Hostname()); err != nil {
+		return nil, "", err
+	}
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL %s: %w", urlStr, err)
+		return nil, "", fmt.Errorf("failed to fetch URL %s: %w", urlStr, err)
 	}
 	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch URL %s: status code %d", urlStr, res.StatusCode)
+		return nil, "", fmt.Errorf("failed to fetch URL %s: status code %d", urlStr, res.StatusCode)
 	}
 
-	return goquery.NewDocumentFromReader(res.Body)
+	finalURL := urlStr
+	if res.Request != nil && res.Request.URL != nil {
+		finalURL = res.Request.URL.String()
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	return doc, finalURL, err
+}
+
+func (c *Client) allowedHost(hostname string) bool {
+	for _, domain := range c.config.AllowedDomains {
+		if hostname == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRedirect bounds redirect chains and keeps them on allowlisted hosts.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= rfdMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", rfdMaxRedirects)
+	}
+	if hostname := req.URL.Hostname(); !c.allowedHost(hostname) {
+		return fmt.Errorf("security violation: redirect hostname %s is not in allowlist", hostname)
+	}
+	return c.hosts.Wait(req.Context(), req.URL.Hostname())
 }
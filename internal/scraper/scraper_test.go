@@ -2,19 +2,29 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 
 	"github.com/pauljones0/rfd-discord-bot/internal/config"
+	"github.com/pauljones0/rfd-discord-bot/internal/logger"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 )
 
 func getMockSnippet(t *testing.T, id string) *goquery.Selection {
@@ -108,6 +118,25 @@ func TestParseDealFromSelection_MinimalDeal(t *testing.T) {
 	}
 }
 
+func TestParseDealFromSelection_ParseWarnings(t *testing.T) {
+	defaults := DefaultSelectors()
+	c := &Client{selectors: defaults, config: &config.Config{
+		AllowedDomains: []string{"forums.redflagdeals.com"},
+		RFDBaseURL:     "https://forums.redflagdeals.com",
+	}}
+
+	minimal := c.parseDealFromSelection(getMockSnippet(t, "minimal-deal").Find("li.topic").First(), defaults.HotDealsList.Elements)
+	want := []string{"posted_time: element not found"}
+	if !reflect.DeepEqual(minimal.ParseWarnings, want) {
+		t.Errorf("ParseWarnings = %q, want %q", minimal.ParseWarnings, want)
+	}
+
+	full := c.parseDealFromSelection(getMockSnippet(t, "full-deal").Find("li.topic").First(), defaults.HotDealsList.Elements)
+	if len(full.ParseWarnings) != 0 {
+		t.Errorf("ParseWarnings for full deal = %q, want none", full.ParseWarnings)
+	}
+}
+
 func TestParseDealFromSelection_CurrentCardWithoutViewsStillParsesLikesAndComments(t *testing.T) {
 	html := `<li class="topic-card topic">
 		<a class="topic-card-info thread_info" href="/deal-123">
@@ -152,6 +181,72 @@ func TestParseDealFromSelection_CurrentCardWithoutViewsStillParsesLikesAndCommen
 	}
 }
 
+func TestParseDealFromSelection_RelativeTimeWithoutDatetime(t *testing.T) {
+	html := `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="/deal-123">
+			<h3 class="thread_title">Current Layout Deal</h3>
+			<time class="topic_time">2 hours ago</time>
+		</a>
+	</li>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	defaults := DefaultSelectors()
+	c := &Client{selectors: defaults, config: &config.Config{
+		AllowedDomains: []string{"forums.redflagdeals.com"},
+		RFDBaseURL:     "https://forums.redflagdeals.com",
+	}}
+	deal := c.parseDealFromSelection(doc.Find("li.topic-card.topic").First(), defaults.HotDealsList.Elements)
+
+	// Rounded down to the hour so later runs land on the same deal ID.
+	age := time.Since(deal.PublishedTimestamp)
+	if age < 2*time.Hour || age > 3*time.Hour || deal.PublishedTimestamp.Minute() != 0 || deal.PublishedTimestamp.Second() != 0 {
+		t.Errorf("PublishedTimestamp = %v (%v ago), want 2 hours ago rounded down to the hour", deal.PublishedTimestamp, age)
+	}
+}
+
+func TestParseDealFromSelection_PicksBestThreadImage(t *testing.T) {
+	html := `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="/deal-123">
+			<h3 class="thread_title">Gallery Deal</h3>
+			<time class="topic_time" datetime="2026-04-16T18:00:00Z">Apr 16</time>
+		</a>
+		<div class="thread_image">
+			<img src="https://ads.example.com/track/1x1.gif" width="1" height="1">
+			<img src="data:image/gif;base64,R0lGOD" data-src="https://images.example.com/google-pixel-9-small.jpg" width="80" height="80">
+			<img src="https://images.example.com/google-pixel-9.jpg" width="400" height="300">
+			<img src="https://images.example.com/icons/star.png" width="16" height="16">
+		</div>
+	</li>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	defaults := DefaultSelectors()
+	c := &Client{selectors: defaults, config: &config.Config{
+		AllowedDomains: []string{"forums.redflagdeals.com"},
+		RFDBaseURL:     "https://forums.redflagdeals.com",
+	}}
+	deal := c.parseDealFromSelection(doc.Find("li.topic-card.topic").First(), defaults.HotDealsList.Elements)
+
+	if want := "https://images.example.com/google-pixel-9.jpg"; deal.ThreadImageURL != want {
+		t.Errorf("ThreadImageURL = %q, want %q", deal.ThreadImageURL, want)
+	}
+
+	pixelOnly, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="thread_image"><img src="https://ads.example.com/pixel.gif"></div>`))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+	if got := bestThreadImage(pixelOnly.Find(".thread_image img")); got != "" {
+		t.Errorf("bestThreadImage() = %q, want no thumbnail from a tracking pixel", got)
+	}
+}
+
 func TestParseDealFromSelection_CurrentCardRetailerFromDataDealerName(t *testing.T) {
 	html := `<li class="topic-card topic">
 		<a class="topic-card-info thread_info" href="/deal-123" data-dealer-name="home depot">
@@ -177,6 +272,64 @@ func TestParseDealFromSelection_CurrentCardRetailerFromDataDealerName(t *testing
 	}
 }
 
+func TestParseDealFromSelection_AuthorName(t *testing.T) {
+	html := `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="/deal-125">
+			<div class="author_info thread_meta_text">
+				<div class="author user_link">
+					<span class=author_name> dealsondeals421 </span>
+					<time class="topic_time" datetime="2026-04-16T18:00:00Z">Apr 16</time>
+				</div>
+			</div>
+			<h3 class="thread_title">Author Deal</h3>
+		</a>
+	</li>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	defaults := DefaultSelectors()
+	c := &Client{selectors: defaults, config: &config.Config{
+		AllowedDomains: []string{"forums.redflagdeals.com"},
+		RFDBaseURL:     "https://forums.redflagdeals.com",
+	}}
+	deal := c.parseDealFromSelection(doc.Find("li.topic-card.topic").First(), defaults.HotDealsList.Elements)
+
+	if deal.AuthorName != "dealsondeals421" {
+		t.Errorf("AuthorName = %q, want %q", deal.AuthorName, "dealsondeals421")
+	}
+}
+
+func TestParseDealFromSelection_RetailerFromTitlePrefix(t *testing.T) {
+	html := `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="/deal-124">
+			<h3 class="thread_title">[Best Buy] LG C3 OLED $1499</h3>
+			<time class="topic_time" datetime="2026-04-16T18:00:00Z">Apr 16</time>
+		</a>
+	</li>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+
+	defaults := DefaultSelectors()
+	c := &Client{selectors: defaults, config: &config.Config{
+		AllowedDomains: []string{"forums.redflagdeals.com"},
+		RFDBaseURL:     "https://forums.redflagdeals.com",
+	}}
+	deal := c.parseDealFromSelection(doc.Find("li.topic-card.topic").First(), defaults.HotDealsList.Elements)
+
+	if deal.Retailer != "Best Buy" {
+		t.Errorf("Retailer = %q, want %q", deal.Retailer, "Best Buy")
+	}
+	if deal.Title != "[Best Buy] LG C3 OLED $1499" {
+		t.Errorf("Title = %q, want original title stored", deal.Title)
+	}
+}
+
 func TestParseDealFromSelection_NegativeLikes(t *testing.T) {
 	defaults := DefaultSelectors()
 	c := &Client{selectors: defaults, config: &config.Config{
@@ -300,8 +453,129 @@ func TestLoadSelectorsFromBytes(t *testing.T) {
 	if cfg.HotDealsList.Container.Item != "li.deal" {
 		t.Errorf("Container.Item = %q, want %q", cfg.HotDealsList.Container.Item, "li.deal")
 	}
-	if cfg.DealDetails.PrimaryLink != ".button" {
-		t.Errorf("PrimaryLink = %q, want %q", cfg.DealDetails.PrimaryLink, ".button")
+	if len(cfg.DealDetails.PrimaryLink) != 1 || cfg.DealDetails.PrimaryLink[0] != ".button" {
+		t.Errorf("PrimaryLink = %q, want [%q]", cfg.DealDetails.PrimaryLink, ".button")
+	}
+}
+
+func TestLoadSelectorsFromBytes_EmbeddedConfig(t *testing.T) {
+	data, err := embeddedSelectors.ReadFile("selectors.json")
+	if err != nil {
+		t.Fatalf("embedded selectors.json: %v", err)
+	}
+	if _, err := LoadSelectorsFromBytes(data); err != nil {
+		t.Errorf("LoadSelectorsFromBytes(embedded) error = %v", err)
+	}
+}
+
+func TestLoadSelectorsFromBytes_LinkCandidateList(t *testing.T) {
+	jsonData := []byte(`{
+		"hot_deals_list": {
+			"container": {"item": "li.deal"},
+			"elements": {"title_link": "a.title", "posted_time": "time"}
+		},
+		"deal_details": {
+			"primary_link": [".deal_link a", "", "a.deal_link"],
+			"fallback_link": ""
+		}
+	}`)
+
+	cfg, err := LoadSelectorsFromBytes(jsonData)
+	if err != nil {
+		t.Fatalf("LoadSelectorsFromBytes() error = %v", err)
+	}
+	want := SelectorList{".deal_link a", "a.deal_link"}
+	if !reflect.DeepEqual(cfg.DealDetails.PrimaryLink, want) {
+		t.Errorf("PrimaryLink = %q, want %q", cfg.DealDetails.PrimaryLink, want)
+	}
+	if len(cfg.DealDetails.FallbackLink) != 0 {
+		t.Errorf("FallbackLink = %q, want empty", cfg.DealDetails.FallbackLink)
+	}
+
+	if _, err := LoadSelectorsFromBytes([]byte(`{"deal_details": {"primary_link": 5}}`)); err == nil {
+		t.Error("Expected error for non-string primary_link")
+	}
+}
+
+func TestLoadSelectorsFromBytes_MissingRequiredFields(t *testing.T) {
+	tests := map[string]string{
+		"item": `{
+			"hot_deals_list": {"container": {"item": "  "}, "elements": {"title_link": "a.title", "posted_time": "time"}},
+			"deal_details": {"primary_link": ".button"}
+		}`,
+		"deal link": `{
+			"hot_deals_list": {"container": {"item": "li.deal"}, "elements": {"title_link": "a.title", "posted_time": "time"}},
+			"deal_details": {"primary_link": [], "fallback_link": ""}
+		}`,
+	}
+	for name, jsonData := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadSelectorsFromBytes([]byte(jsonData)); err == nil || !strings.Contains(err.Error(), "missing required selectors") {
+				t.Errorf("LoadSelectorsFromBytes() error = %v, want missing required selectors", err)
+			}
+		})
+	}
+
+	missingItem := []byte(`{"hot_deals_list": {"elements": {"title_link": "a.title", "posted_time": "time"}}, "deal_details": {"primary_link": ".button"}}`)
+	got := loadSelectorsWithFallback(missingItem, t.TempDir()+"/missing.json")
+	if !reflect.DeepEqual(got, DefaultSelectors()) {
+		t.Errorf("loadSelectorsWithFallback() = %+v, want the defaults when every source is invalid", got)
+	}
+}
+
+func TestReloadSelectors_SwapsInChangedFile(t *testing.T) {
+	path := t.TempDir() + "/selectors.json"
+	write := func(item string) {
+		t.Helper()
+		data := fmt.Sprintf(`{
+			"hot_deals_list": {"container": {"item": %q}, "elements": {"title_link": "a.title", "posted_time": "time"}},
+			"deal_details": {"primary_link": ".button"}
+		}`, item)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := New(&config.Config{}, DefaultSelectors())
+	write("li.topic-v2")
+	if _, err := c.reloadSelectorsFrom(path); err != nil {
+		t.Fatalf("reloadSelectorsFrom() error = %v", err)
+	}
+	if got := c.currentSelectors().HotDealsList.Container.Item; got != "li.topic-v2" {
+		t.Errorf("Container.Item = %q, want the reloaded selector", got)
+	}
+
+	write("")
+	if _, err := c.reloadSelectorsFrom(path); err == nil {
+		t.Fatal("reloadSelectorsFrom() accepted a file missing the item selector")
+	}
+	if got := c.currentSelectors().HotDealsList.Container.Item; got != "li.topic-v2" {
+		t.Errorf("Container.Item = %q after a bad reload, want the previous selectors kept", got)
+	}
+}
+
+func TestPreferredCommentCount(t *testing.T) {
+	detail := dealDetailResult{JSONLDCommentCount: 48, PageCommentCount: 51}
+	tests := []struct {
+		name      string
+		sources   []string
+		listCount int
+		detail    dealDetailResult
+		want      int
+	}{
+		{name: "list first", sources: []string{"list", "jsonld", "detail"}, listCount: 40, detail: detail, want: 40},
+		{name: "jsonld first", sources: []string{"jsonld", "detail", "list"}, listCount: 40, detail: detail, want: 48},
+		{name: "detail first", sources: []string{"detail", "jsonld"}, listCount: 40, detail: detail, want: 51},
+		{name: "falls through missing jsonld", sources: []string{"jsonld", "detail"}, listCount: 40, detail: dealDetailResult{PageCommentCount: 51}, want: 51},
+		{name: "zero list count falls through", sources: []string{"list", "jsonld"}, listCount: 0, detail: detail, want: 48},
+		{name: "no source has a value", sources: []string{"jsonld", "detail"}, listCount: 40, want: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferredCommentCount(tt.sources, tt.listCount, tt.detail); got != tt.want {
+				t.Errorf("preferredCommentCount(%q) = %d, want %d", tt.sources, got, tt.want)
+			}
+		})
 	}
 }
 
@@ -320,8 +594,8 @@ func TestDefaultSelectors(t *testing.T) {
 	if sel.HotDealsList.Elements.ViewCount != "" {
 		t.Errorf("Default ViewCount = %q, want empty string", sel.HotDealsList.Elements.ViewCount)
 	}
-	if sel.DealDetails.PrimaryLink != ".deal_link a" {
-		t.Errorf("Default PrimaryLink = %q, want %q", sel.DealDetails.PrimaryLink, ".deal_link a")
+	if len(sel.DealDetails.PrimaryLink) == 0 || sel.DealDetails.PrimaryLink[0] != ".deal_link a" {
+		t.Errorf("Default PrimaryLink = %q, want %q first", sel.DealDetails.PrimaryLink, ".deal_link a")
 	}
 }
 
@@ -351,6 +625,109 @@ func TestRFDListRetryStopsNonDNSFailuresAtStandardBudget(t *testing.T) {
 	}
 }
 
+func TestListFailureTracker_EscalatesAndAlerts(t *testing.T) {
+	tracker := newListFailureTracker(3, 5*time.Minute)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tests := []struct {
+		wantCooldown time.Duration
+		wantAlert    bool
+	}{
+		{wantCooldown: 0},
+		{wantCooldown: 0},
+		{wantCooldown: 5 * time.Minute, wantAlert: true},
+		{wantCooldown: 10 * time.Minute},
+		{wantCooldown: 20 * time.Minute},
+		{wantCooldown: 40 * time.Minute},
+		{wantCooldown: time.Hour},
+		{wantCooldown: time.Hour},
+	}
+	for i, tt := range tests {
+		failures, cooldown, alert := tracker.recordFailure()
+		if failures != i+1 || cooldown != tt.wantCooldown || alert != tt.wantAlert {
+			t.Fatalf("failure %d: recordFailure() = %d, %v, %v, want %d, %v, %v",
+				i+1, failures, cooldown, alert, i+1, tt.wantCooldown, tt.wantAlert)
+		}
+		if _, paused := tracker.coolingDown(); paused != (tt.wantCooldown > 0) {
+			t.Fatalf("failure %d: coolingDown() = %v, want %v", i+1, paused, tt.wantCooldown > 0)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, paused := tracker.coolingDown(); paused {
+		t.Fatal("expected cooldown to lapse")
+	}
+	if failures := tracker.recordSuccess(); failures != len(tests) {
+		t.Fatalf("recordSuccess() = %d, want %d", failures, len(tests))
+	}
+	if failures, cooldown, alert := tracker.recordFailure(); failures != 1 || cooldown != 0 || alert {
+		t.Fatalf("after reset recordFailure() = %d, %v, %v, want 1, 0, false", failures, cooldown, alert)
+	}
+}
+
+func TestListFailureTracker_Disabled(t *testing.T) {
+	tracker := newListFailureTracker(0, 5*time.Minute)
+	for i := 0; i < 10; i++ {
+		if _, cooldown, alert := tracker.recordFailure(); cooldown != 0 || alert {
+			t.Fatalf("disabled tracker escalated: cooldown=%v alert=%v", cooldown, alert)
+		}
+	}
+}
+
+type capturedAlert struct {
+	level slog.Level
+	msg   string
+}
+
+type captureAlerter struct {
+	alerts []capturedAlert
+}
+
+func (a *captureAlerter) Alert(_ context.Context, level slog.Level, msg string, _ ...any) {
+	a.alerts = append(a.alerts, capturedAlert{level: level, msg: msg})
+}
+
+func TestRecordListFailure_RaisesAlertAtThreshold(t *testing.T) {
+	cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, RFDFailureAlertAfter: 2}
+	c := New(cfg, DefaultSelectors())
+	alerter := &captureAlerter{}
+	c.SetAlerter(alerter)
+
+	c.recordListFailure(context.Background(), errors.New("403 Forbidden"))
+	if len(alerter.alerts) != 0 {
+		t.Fatalf("alerts after first failure = %+v, want none", alerter.alerts)
+	}
+	c.recordListFailure(context.Background(), errors.New("403 Forbidden"))
+	if len(alerter.alerts) != 1 || alerter.alerts[0].level != logger.LevelCritical || !strings.Contains(alerter.alerts[0].msg, "failing repeatedly") {
+		t.Fatalf("alerts = %+v, want one critical repeated-failure alert", alerter.alerts)
+	}
+	c.recordListFailure(context.Background(), errors.New("403 Forbidden"))
+	if len(alerter.alerts) != 1 {
+		t.Errorf("alerts = %+v, want no repeat past the threshold", alerter.alerts)
+	}
+}
+
+func TestScrapeDealList_SkipsRequestsDuringCooldown(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "blocked", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, RFDFailureAlertAfter: 1, RFDFailureCooldown: time.Hour}
+	c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+	c.recordListFailure(context.Background(), errors.New("403 Forbidden"))
+
+	if _, err := c.ScrapeDealList(context.Background()); err == nil || !strings.Contains(err.Error(), "repeated failures") {
+		t.Fatalf("ScrapeDealList() error = %v, want cooldown error", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("RFD requests during cooldown = %d, want 0", got)
+	}
+}
+
 func TestScrapeDealDetailPage_PrimaryLink(t *testing.T) {
 	html := getMockSnippetHTML(t, "primary-link")
 
@@ -395,6 +772,130 @@ func TestScrapeDealDetailPage_FallbackLink(t *testing.T) {
 	}
 }
 
+func TestScrapeDealDetailPage_DealButtonVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"wrapped deal_link", `<div class="deal_link"><a href="https://amazon.ca/dp/B001">Get Deal</a></div>`, "https://amazon.ca/dp/B001"},
+		{"anchor deal_link", `<a class="deal_link" href="https://amazon.ca/dp/B002">Get Deal</a>`, "https://amazon.ca/dp/B002"},
+		{"primary_deal_link", `<div class="primary_deal_link"><a href="https://bestbuy.ca/p/1">Get Deal</a></div>`, "https://bestbuy.ca/p/1"},
+		{"get-deal-button", `<a class="get-deal-button" href="https://costco.ca/item">Get Deal</a>`, "https://costco.ca/item"},
+		{"data attribute", `<button class="deal_button" data-deal-url="https://walmart.ca/ip/9">Get Deal</button>`, "https://walmart.ca/ip/9"},
+		{
+			"skips invalid candidate",
+			`<div class="deal_link"><a href="javascript:void(0)">Get Deal</a></div>
+			<a class="primary_deal_link" href="https://newegg.ca/item">Get Deal</a>`,
+			"https://newegg.ca/item",
+		},
+		{
+			"primary beats fallback",
+			`<a class="postlink" href="https://fallback.example.com/">Post link</a>
+			<a class="deal_link" href="https://primary.example.com/">Get Deal</a>`,
+			"https://primary.example.com/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.html)
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{
+				AllowedDomains: []string{"127.0.0.1"},
+			}
+			c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+
+			detail, err := c.scrapeDealDetailPage(context.Background(), srv.URL+"/deal-page")
+			if err != nil {
+				t.Fatalf("scrapeDealDetailPage() error = %v", err)
+			}
+			if detail.DealLink != tt.want {
+				t.Errorf("DealLink = %q, want %q", detail.DealLink, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeDealDetailPage_LinkPolicy(t *testing.T) {
+	html := `<div class="deal_link"><a href="https://go.redirectingat.com/?url=https%3A%2F%2Fwww.walmart.ca%2Fip%2F1">Get Deal</a></div>
+	<a class="postlink" href="https://www.walmart.ca/ip/2">Post link</a>`
+
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", "https://go.redirectingat.com/?url=https%3A%2F%2Fwww.walmart.ca%2Fip%2F1"},
+		{LinkPolicyPreferPrimary, "https://go.redirectingat.com/?url=https%3A%2F%2Fwww.walmart.ca%2Fip%2F1"},
+		{LinkPolicyPreferExternal, "https://www.walmart.ca/ip/2"},
+	}
+
+	for _, tt := range tests {
+		t.Run("policy="+tt.policy, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, html)
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{
+				AllowedDomains: []string{"127.0.0.1"},
+			}
+			sel := DefaultSelectors()
+			sel.DealDetails.LinkPolicy = tt.policy
+			c := NewWithBaseURL(cfg, sel, srv.URL)
+
+			detail, err := c.scrapeDealDetailPage(context.Background(), srv.URL+"/deal-page")
+			if err != nil {
+				t.Fatalf("scrapeDealDetailPage() error = %v", err)
+			}
+			if detail.DealLink != tt.want {
+				t.Errorf("DealLink = %q, want %q", detail.DealLink, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseDealLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		primary  string
+		fallback string
+		want     string
+	}{
+		{"only fallback", LinkPolicyPreferPrimary, "", "https://a.example.com/", "https://a.example.com/"},
+		{"same product ignores tracking", LinkPolicyPreferExternal, "https://www.amazon.ca/dp/B001?tag=x", "https://www.amazon.ca/dp/B001", "https://www.amazon.ca/dp/B001?tag=x"},
+		{"both direct keeps primary", LinkPolicyPreferExternal, "https://a.example.com/", "https://b.example.com/", "https://a.example.com/"},
+		{"both redirects keeps primary", LinkPolicyPreferExternal, "https://click.linksynergy.com/link?murl=a", "https://go.redirectingat.com/?url=b", "https://click.linksynergy.com/link?murl=a"},
+		{"prefer primary keeps redirect", LinkPolicyPreferPrimary, "https://go.redirectingat.com/?url=a", "https://b.example.com/", "https://go.redirectingat.com/?url=a"},
+		{"prefer external skips redirect", LinkPolicyPreferExternal, "https://go.redirectingat.com/?url=a", "https://b.example.com/", "https://b.example.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseDealLink("https://forums.redflagdeals.com/t", tt.policy, tt.primary, tt.fallback); got != tt.want {
+				t.Errorf("chooseDealLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSelectorsFromBytes_UnknownLinkPolicy(t *testing.T) {
+	jsonData := []byte(`{
+		"hot_deals_list": {
+			"container": {"item": "li.deal"},
+			"elements": {"title_link": "a.title", "posted_time": "time"}
+		},
+		"deal_details": {"link_policy": "prefer_anything"}
+	}`)
+	if _, err := LoadSelectorsFromBytes(jsonData); err == nil {
+		t.Error("Expected error for unknown link_policy")
+	}
+}
+
 func TestScrapeDealDetailPage_NoLink(t *testing.T) {
 	html := getMockSnippetHTML(t, "no-link")
 
@@ -634,6 +1135,123 @@ func TestScrapeDealDetailPage_JSONLDFallback(t *testing.T) {
 	}
 }
 
+func TestScrapeDealDetailPage_JSONLDPosting(t *testing.T) {
+	pages := map[string]string{
+		"/object": `<html><head><script type="application/ld+json">
+			{"@type": "DiscussionForumPosting", "text": "<p>Great price</p>", "datePublished": "2025-01-15T10:30:00Z",
+			 "author": {"@type": "Person", "name": " dealhunter "}, "commentCount": 12}
+		</script></head><body></body></html>`,
+		"/array": `<html><head><script type="application/ld+json">
+			[{"@type": "BreadcrumbList"}, {"@type": "DiscussionForumPosting", "text": "Array post", "author": [{"name": "first"}, {"name": "second"}]}]
+		</script></head><body></body></html>`,
+		"/malformed": `<html><head><script type="application/ld+json">{"@type": "DiscussionForumPosting", "text": </script></head><body></body></html>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, pages[r.URL.Path])
+	}))
+	defer srv.Close()
+	c := NewWithBaseURL(&config.Config{AllowedDomains: []string{"127.0.0.1"}}, DefaultSelectors(), srv.URL)
+
+	detail, err := c.scrapeDealDetailPage(context.Background(), srv.URL+"/object")
+	if err != nil {
+		t.Fatalf("scrapeDealDetailPage() error = %v", err)
+	}
+	wantPublished := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	if detail.Description != "Great price" || detail.Author != "dealhunter" || !detail.Published.Equal(wantPublished) || detail.JSONLDCommentCount != 12 {
+		t.Errorf("object posting = %+v, want description, author, date and comment count", detail)
+	}
+
+	detail, err = c.scrapeDealDetailPage(context.Background(), srv.URL+"/array")
+	if err != nil {
+		t.Fatalf("scrapeDealDetailPage() error = %v", err)
+	}
+	if detail.Description != "Array post" || detail.Author != "first" {
+		t.Errorf("array posting = %+v, want the DiscussionForumPosting entry and its first author", detail)
+	}
+
+	detail, err = c.scrapeDealDetailPage(context.Background(), srv.URL+"/malformed")
+	if err != nil {
+		t.Fatalf("scrapeDealDetailPage() error = %v", err)
+	}
+	if detail.Description != "" || detail.Author != "" || !detail.Published.IsZero() {
+		t.Errorf("malformed posting = %+v, want nothing taken from it", detail)
+	}
+
+	listTime := wantPublished.Add(-time.Minute)
+	deal := &models.DealInfo{AuthorName: "list author", PublishedTimestamp: listTime}
+	c.applyDealDetail(deal, dealDetailResult{Author: "dealhunter", Published: wantPublished})
+	if deal.AuthorName != "dealhunter" {
+		t.Errorf("AuthorName = %q, want the JSON-LD author", deal.AuthorName)
+	}
+	if !deal.PublishedTimestamp.Equal(listTime) {
+		t.Errorf("PublishedTimestamp = %v, want the list time kept since it keys the deal ID", deal.PublishedTimestamp)
+	}
+	deal = &models.DealInfo{AuthorName: "list author"}
+	c.applyDealDetail(deal, dealDetailResult{Published: wantPublished})
+	if deal.AuthorName != "list author" || !deal.PublishedTimestamp.Equal(wantPublished) {
+		t.Errorf("deal = %q %v, want the list author kept and the missing time filled", deal.AuthorName, deal.PublishedTimestamp)
+	}
+}
+
+func TestScrapeDealDetailPage_TruncatesDescriptionAndComments(t *testing.T) {
+	page := `<html><head><script type="application/ld+json">
+		{"@type": "DiscussionForumPosting", "text": "Half price on every café blend this week",
+		 "comment": [{"text": "Bought two"}, {"text": "Still in stock at my store"}]}
+	</script></head><body></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+	cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, MaxDescriptionLen: 20, MaxCommentsLen: 12}
+	c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+
+	detail, err := c.scrapeDealDetailPage(context.Background(), srv.URL+"/deal")
+	if err != nil {
+		t.Fatalf("scrapeDealDetailPage() error = %v", err)
+	}
+	if want := "Half price on every…"; detail.Description != want {
+		t.Errorf("Description = %q, want %q", detail.Description, want)
+	}
+	if want := "- Bought tw…"; detail.Comments != want {
+		t.Errorf("Comments = %q, want %q", detail.Comments, want)
+	}
+
+	cfg.MaxDescriptionLen, cfg.MaxCommentsLen = 0, 0
+	detail, err = c.scrapeDealDetailPage(context.Background(), srv.URL+"/deal")
+	if err != nil {
+		t.Fatalf("scrapeDealDetailPage() error = %v", err)
+	}
+	if want := "Half price on every café blend this week"; detail.Description != want {
+		t.Errorf("uncapped Description = %q, want %q", detail.Description, want)
+	}
+	if want := "- Bought two\n- Still in stock at my store"; detail.Comments != want {
+		t.Errorf("uncapped Comments = %q, want %q", detail.Comments, want)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{in: "short", maxLen: 10, want: "short"},
+		{in: "exactly10!", maxLen: 10, want: "exactly10!"},
+		{in: "eleven char", maxLen: 10, want: "eleven ch…"},
+		{in: "crème brûlée", maxLen: 6, want: "crème…"},
+		{in: "anything", maxLen: 0, want: "anything"},
+	}
+	for _, tt := range tests {
+		got := truncateText(tt.in, tt.maxLen)
+		if got != tt.want {
+			t.Errorf("truncateText(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+		if tt.maxLen > 0 && utf8.RuneCountInString(got) > tt.maxLen {
+			t.Errorf("truncateText(%q, %d) is %d characters, over the cap", tt.in, tt.maxLen, utf8.RuneCountInString(got))
+		}
+	}
+}
+
 func TestParseDealFromSelection_ListPrice(t *testing.T) {
 	html := `
 	<li class="topic-card topic">
@@ -840,3 +1458,423 @@ func TestFetchDealDetails_RetriesTransientStatus(t *testing.T) {
 		t.Fatalf("Retailer = %q, want Retry Store", deal.Retailer)
 	}
 }
+
+func TestFetchDealDetails_SkipsSlowDetailPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/slow-deal") {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		fmt.Fprint(w, `<!DOCTYPE html><html><body><a class="retailer_badge">Fast Store</a></body></html>`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		AllowedDomains:   []string{"127.0.0.1"},
+		RFDBaseURL:       srv.URL,
+		RFDDetailTimeout: 200 * time.Millisecond,
+	}
+	c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+
+	slow := models.DealInfo{PostURL: srv.URL + "/slow-deal"}
+	fast := models.DealInfo{PostURL: srv.URL + "/fast-deal"}
+
+	start := time.Now()
+	stats := c.FetchDealDetails(context.Background(), []*models.DealInfo{&slow, &fast})
+	elapsed := time.Since(start)
+
+	if elapsed > 2*time.Second {
+		t.Fatalf("FetchDealDetails took %s, want it bounded by the per-deal timeout", elapsed)
+	}
+	if stats.Succeeded != 1 || stats.Failed != 1 {
+		t.Fatalf("stats = %#v, want one success and one timed-out failure", stats)
+	}
+	if fast.Retailer != "Fast Store" {
+		t.Errorf("fast Retailer = %q, want Fast Store", fast.Retailer)
+	}
+	if slow.Retailer != "" {
+		t.Errorf("slow Retailer = %q, want empty", slow.Retailer)
+	}
+}
+
+type fakeDoer struct {
+	mu    sync.Mutex
+	urls  []string
+	pages map[string]string // path -> body
+}
+
+func (f *fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.urls = append(f.urls, req.URL.String())
+	f.mu.Unlock()
+
+	body, ok := f.pages[req.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestFetchDealDetails_InjectedDoer(t *testing.T) {
+	cfg := &config.Config{AllowedDomains: []string{"forums.redflagdeals.com"}}
+	c := New(cfg, DefaultSelectors())
+	doer := &fakeDoer{pages: map[string]string{
+		"/canned-deal": `<div class="deal_link"><a href="https://www.walmart.ca/ip/42">Get Deal</a></div><a class="retailer_badge">Walmart</a>`,
+	}}
+	c.httpClient = doer
+
+	deal := models.DealInfo{PostURL: "https://forums.redflagdeals.com/canned-deal"}
+	stats := c.FetchDealDetails(context.Background(), []*models.DealInfo{&deal})
+
+	if stats.Succeeded != 1 {
+		t.Fatalf("stats = %#v, want one success", stats)
+	}
+	if len(doer.urls) != 1 || doer.urls[0] != deal.PostURL {
+		t.Errorf("doer saw %v, want a single request for %s", doer.urls, deal.PostURL)
+	}
+	if deal.ActualDealURL != "https://www.walmart.ca/ip/42" {
+		t.Errorf("ActualDealURL = %q, want the canned deal link", deal.ActualDealURL)
+	}
+	if deal.Retailer != "Walmart" {
+		t.Errorf("Retailer = %q, want Walmart", deal.Retailer)
+	}
+}
+
+func TestFetchDealDetails_FollowsMovedThread(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old-post":
+			http.Redirect(w, r, "/new-post-123", http.StatusMovedPermanently)
+		case "/offsite-post":
+			http.Redirect(w, r, "https://evil.example.com/", http.StatusFound)
+		default:
+			fmt.Fprint(w, `<a class="retailer_badge">Moved Store</a>`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, RFDBaseURL: srv.URL}
+	c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+
+	moved := models.DealInfo{
+		PostURL: srv.URL + "/old-post",
+		Threads: []models.ThreadContext{{PostURL: srv.URL + "/old-post"}},
+	}
+	offsite := models.DealInfo{PostURL: srv.URL + "/offsite-post"}
+	stats := c.FetchDealDetails(context.Background(), []*models.DealInfo{&moved, &offsite})
+
+	if stats.Succeeded != 1 || stats.Failed != 1 {
+		t.Fatalf("stats = %#v, want the off-allowlist redirect to fail", stats)
+	}
+	want := strings.Replace(srv.URL, "http://", "https://", 1) + "/new-post-123"
+	if moved.PostURL != want {
+		t.Errorf("PostURL = %q, want %q", moved.PostURL, want)
+	}
+	if moved.Threads[0].PostURL != want {
+		t.Errorf("Threads[0].PostURL = %q, want %q", moved.Threads[0].PostURL, want)
+	}
+	if moved.Retailer != "Moved Store" {
+		t.Errorf("Retailer = %q, want Moved Store", moved.Retailer)
+	}
+	if offsite.PostURL != srv.URL+"/offsite-post" {
+		t.Errorf("offsite PostURL = %q, want unchanged", offsite.PostURL)
+	}
+}
+
+func TestFetchDealDetails_CacheHitSkipsRequest(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `<html><head><script type="application/ld+json">
+			{"@type": "DiscussionForumPosting", "text": "Half price", "commentCount": 40,
+			 "comment": [{"text": "Bought two"}]}
+		</script></head><body><div class="deal_link"><a href="https://www.walmart.ca/ip/1">Get Deal</a></div></body></html>`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		AllowedDomains:      []string{"127.0.0.1"},
+		RFDBaseURL:          srv.URL,
+		CommentCountSources: []string{"jsonld", "list"},
+		RFDDetailCacheSize:  10,
+		RFDDetailCacheTTL:   time.Hour,
+	}
+	c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+
+	first := models.DealInfo{Title: "Deal", PostURL: srv.URL + "/deal-1", Threads: []models.ThreadContext{{PostURL: srv.URL + "/deal-1", CommentCount: 3}}}
+	c.FetchDealDetails(context.Background(), []*models.DealInfo{&first})
+	if first.Description != "Half price" || first.Threads[0].CommentCount != 40 {
+		t.Fatalf("fetched deal = %q with %d comments, want page text and JSON-LD count", first.Description, first.Threads[0].CommentCount)
+	}
+
+	second := models.DealInfo{Title: "Deal", PostURL: srv.URL + "/deal-1", Threads: []models.ThreadContext{{PostURL: srv.URL + "/deal-1", CommentCount: 45}}}
+	stats := c.FetchDealDetails(context.Background(), []*models.DealInfo{&second})
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("detail requests = %d, want 1 (second run served from cache)", got)
+	}
+	if stats.Succeeded != 1 {
+		t.Errorf("stats = %#v, want cached deal counted as succeeded", stats)
+	}
+	if second.ActualDealURL != "https://www.walmart.ca/ip/1" {
+		t.Errorf("ActualDealURL = %q, want cached link", second.ActualDealURL)
+	}
+	if second.Threads[0].CommentCount != 45 || second.Description != "" || second.Comments != "" {
+		t.Errorf("cached deal = %d comments, description %q, comments %q; want the list count and no cached text",
+			second.Threads[0].CommentCount, second.Description, second.Comments)
+	}
+
+	edited := models.DealInfo{Title: "Deal (now cheaper)", PostURL: srv.URL + "/deal-1"}
+	c.FetchDealDetails(context.Background(), []*models.DealInfo{&edited})
+	if got := requests.Load(); got != 2 {
+		t.Errorf("detail requests = %d, want 2 after title edit", got)
+	}
+}
+
+func TestDetailCache_EvictionAndExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newDetailCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", dealDetailResult{DealLink: "https://a.example.com/"})
+	cache.put("b", dealDetailResult{DealLink: "https://b.example.com/"})
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("expected hit for a")
+	}
+	cache.put("c", dealDetailResult{DealLink: "https://c.example.com/"})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected least recently used entry b to be evicted")
+	}
+	if cache.len() != 2 {
+		t.Errorf("len = %d, want 2", cache.len())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("a"); ok {
+		t.Error("expected a to expire after TTL")
+	}
+}
+
+func TestDetailCache_ConcurrentAccess(t *testing.T) {
+	cache := newDetailCache(8, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("deal-%d", i%10)
+			cache.put(key, dealDetailResult{DealLink: key})
+			cache.get(key)
+		}(i)
+	}
+	wg.Wait()
+	if cache.len() > 8 {
+		t.Errorf("len = %d, want at most 8", cache.len())
+	}
+}
+
+func TestHotDealsListURL(t *testing.T) {
+	tests := map[string]string{
+		"newest":  "https://forums.redflagdeals.com/hot-deals-f9/?sk=tt&rfd_sk=tt&sd=d",
+		"replies": "https://forums.redflagdeals.com/hot-deals-f9/?sk=r&rfd_sk=r&sd=d",
+		"views":   "https://forums.redflagdeals.com/hot-deals-f9/?sk=v&rfd_sk=v&sd=d",
+		"":        "https://forums.redflagdeals.com/hot-deals-f9/?sk=tt&rfd_sk=tt&sd=d",
+	}
+	for sort, want := range tests {
+		if got := hotDealsListURL("https://forums.redflagdeals.com", sort); got != want {
+			t.Errorf("hotDealsListURL(%q) = %q, want %q", sort, got, want)
+		}
+	}
+}
+
+func TestExpiredDealsListURL(t *testing.T) {
+	want := "https://forums.redflagdeals.com/expired-hot-deals-f68/?sk=r&rfd_sk=r&sd=d"
+	if got := expiredDealsListURL("https://forums.redflagdeals.com", "replies"); got != want {
+		t.Errorf("expiredDealsListURL() = %q, want %q", got, want)
+	}
+}
+
+func TestScrapeDealList_IncludesExpiredForum(t *testing.T) {
+	card := func(path, title, posted string) string {
+		return `<!DOCTYPE html><html><body>
+	<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="` + path + `">
+			<div class="thread_main"><div class="thread_info"><div class="thread_info_block">
+				<h3 class="thread_title">` + title + `</h3>
+				<div class="thread_footer"><time class="topic_time" datetime="` + posted + `">Apr 16</time></div>
+			</div></div></div>
+		</a>
+	</li>
+</body></html>`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hot-deals":
+			fmt.Fprint(w, card("/active-1", "Active Deal", "2026-04-16T18:00:00Z"))
+		case "/expired-hot-deals":
+			fmt.Fprint(w, card("/expired-2", "Expired Deal", "2026-04-15T18:00:00Z"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, include := range []bool{false, true} {
+		cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, RFDBaseURL: srv.URL, RFDIncludeExpired: include}
+		deals, err := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL).ScrapeDealList(context.Background())
+		if err != nil {
+			t.Fatalf("include=%v: ScrapeDealList() error = %v", include, err)
+		}
+		wantLen := 1
+		if include {
+			wantLen = 2
+		}
+		if len(deals) != wantLen {
+			t.Fatalf("include=%v: got %d deals, want %d", include, len(deals), wantLen)
+		}
+		if deals[0].Title != "Active Deal" || deals[0].Expired {
+			t.Errorf("include=%v: first deal = %q expired=%v, want the active deal", include, deals[0].Title, deals[0].Expired)
+		}
+		if include && (deals[1].Title != "Expired Deal" || !deals[1].Expired || deals[1].Forum != expiredDealsForum) {
+			t.Errorf("expired forum deal = %q expired=%v, want it marked expired", deals[1].Title, deals[1].Expired)
+		}
+	}
+}
+
+func TestScrapeDealList_ExtraForums(t *testing.T) {
+	card := func(path, title string) string {
+		return `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="` + path + `">
+			<h3 class="thread_title">` + title + `</h3>
+			<time class="topic_time" datetime="2026-04-16T18:00:00Z">Apr 16</time>
+		</a>
+	</li>`
+	}
+	page := func(cards ...string) string {
+		return "<!DOCTYPE html><html><body><ul>" + strings.Join(cards, "") + "</ul></body></html>"
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hot-deals":
+			fmt.Fprint(w, page(card("/gpu-1", "GPU Deal")))
+		case "/computers-electronics-f214":
+			fmt.Fprint(w, page(card("/gpu-1", "GPU Deal (cross-posted)"), card("/ssd-2", "SSD Deal")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{
+		AllowedDomains: []string{"127.0.0.1"},
+		RFDBaseURL:     srv.URL,
+		RFDExtraForums: []string{"broken-f1", "computers-electronics-f214"},
+	}
+	deals, err := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL).ScrapeDealList(context.Background())
+	if err != nil {
+		t.Fatalf("ScrapeDealList() error = %v, want a failing extra forum skipped", err)
+	}
+	var got []string
+	for _, deal := range deals {
+		got = append(got, deal.Title+"@"+deal.Forum)
+	}
+	want := []string{"GPU Deal@hot-deals-f9", "SSD Deal@computers-electronics-f214"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deals = %q, want %q", got, want)
+	}
+}
+
+func TestScrapeDealList_FollowsPages(t *testing.T) {
+	card := func(path, posted string) string {
+		return `<li class="topic-card topic">
+		<a class="topic-card-info thread_info" href="` + path + `">
+			<h3 class="thread_title">Deal ` + path + `</h3>
+			<time class="topic_time" datetime="` + posted + `">Apr 16</time>
+		</a>
+	</li>`
+	}
+	pages := map[string]string{
+		"":  card("/deal-1", "2026-04-16T18:00:00Z") + card("/deal-2", "2026-04-16T17:00:00Z"),
+		"2": card("/deal-3", "2026-04-16T16:00:00Z") + card("/deal-4", "2026-04-16T15:00:00Z"),
+		"3": card("/deal-5", "2026-04-16T14:00:00Z"),
+		"4": `<li class="topic-card topic sticky"></li>`,
+	}
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("p")
+		requested = append(requested, page)
+		cards, ok := pages[page]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "<!DOCTYPE html><html><body><ul>"+cards+"</ul></body></html>")
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		maxPages  int
+		floor     time.Time
+		wantDeals int
+		wantPages []string
+	}{
+		{"first page only by default", 1, time.Time{}, 2, []string{""}},
+		{"stops at the page limit", 2, time.Time{}, 4, []string{"", "2"}},
+		{"stops at a page with no topics", 10, time.Time{}, 5, []string{"", "2", "3", "4"}},
+		{"stops at stored deals", 10, time.Date(2026, 4, 16, 15, 30, 0, 0, time.UTC), 4, []string{"", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			cfg := &config.Config{AllowedDomains: []string{"127.0.0.1"}, RFDBaseURL: srv.URL, RFDMaxPages: tt.maxPages}
+			c := NewWithBaseURL(cfg, DefaultSelectors(), srv.URL)
+			c.SetPageFloor(tt.floor)
+
+			deals, err := c.ScrapeDealList(context.Background())
+			if err != nil {
+				t.Fatalf("ScrapeDealList() error = %v", err)
+			}
+			if len(deals) != tt.wantDeals {
+				t.Errorf("got %d deals, want %d", len(deals), tt.wantDeals)
+			}
+			if !reflect.DeepEqual(requested, tt.wantPages) {
+				t.Errorf("requested pages %q, want %q", requested, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestApplyDealDetail_NormalizesDealURLKeepingAffiliateTag(t *testing.T) {
+	c := &Client{config: &config.Config{AmazonAffiliateTag: "mytag-20"}}
+	deal := &models.DealInfo{PostURL: "https://forums.redflagdeals.com/deal-1"}
+
+	// The referral redirect hides the target's tracking params from CleanProductURL.
+	target := url.QueryEscape("https://www.amazon.ca/dp/B000123?utm_source=rfd&fbclid=abc")
+	c.applyDealDetail(deal, dealDetailResult{DealLink: "https://click.linksynergy.com/deeplink?murl=" + target})
+
+	if want := "https://www.amazon.ca/dp/B000123?tag=mytag-20"; deal.ActualDealURL != want {
+		t.Errorf("ActualDealURL = %q, want %q", deal.ActualDealURL, want)
+	}
+}
+
+func TestNew_CanonicalHostsExtendDefaults(t *testing.T) {
+	c := New(&config.Config{CanonicalHosts: map[string]string{"m.redflagdeals.com": "forums.redflagdeals.com"}}, SelectorConfig{})
+	allowed := []string{"m.redflagdeals.com", "www.redflagdeals.com"}
+
+	for _, raw := range []string{"https://m.redflagdeals.com/deal-1", "https://www.redflagdeals.com/deal-1"} {
+		got, err := util.NormalizeURL(raw, allowed, c.canonicalHosts)
+		if err != nil || got != "https://forums.redflagdeals.com/deal-1" {
+			t.Errorf("NormalizeURL(%q) = %q, %v; want forums host", raw, got, err)
+		}
+	}
+}
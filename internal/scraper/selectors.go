@@ -32,12 +32,54 @@ type ListElements struct {
 	CommentCount         string `json:"comment_count"`
 	CommentCountFallback string `json:"comment_count_fallback"`
 	ViewCount            string `json:"view_count"`
+	AuthorName           string `json:"author_name"`
 }
 
 type DetailSelectors struct {
-	PrimaryLink  string `json:"primary_link"`
-	FallbackLink string `json:"fallback_link"`
-	Category     string `json:"category"`
+	PrimaryLink  SelectorList `json:"primary_link"`
+	FallbackLink SelectorList `json:"fallback_link"`
+	Category     string       `json:"category"`
+	// CommentCount reads the reply count off the thread page for
+	// COMMENT_COUNT_SOURCES=detail. Empty disables that source.
+	CommentCount string `json:"comment_count"`
+	// LinkPolicy picks the deal link when the primary and fallback selectors
+	// disagree. Empty means LinkPolicyPreferPrimary.
+	LinkPolicy string `json:"link_policy"`
+}
+
+const (
+	// LinkPolicyPreferPrimary keeps the primary (deal button) link.
+	LinkPolicyPreferPrimary = "prefer_primary"
+	// LinkPolicyPreferExternal keeps whichever link points straight at the
+	// retailer rather than through an affiliate redirect, preferring primary
+	// when that doesn't settle it.
+	LinkPolicyPreferExternal = "prefer_external"
+)
+
+// SelectorList is an ordered list of candidate selectors; the first one that
+// yields a usable match wins. In JSON it may be a single string or an array.
+type SelectorList []string
+
+func (l *SelectorList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = nil
+		if strings.TrimSpace(single) != "" {
+			*l = SelectorList{single}
+		}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("selector must be a string or array of strings: %w", err)
+	}
+	*l = nil
+	for _, sel := range list {
+		if strings.TrimSpace(sel) != "" {
+			*l = append(*l, sel)
+		}
+	}
+	return nil
 }
 
 // LoadSelectors loads the selector configuration from the specified JSON file.
@@ -65,21 +107,31 @@ func LoadSelectorsFromBytes(data []byte) (SelectorConfig, error) {
 	return config, nil
 }
 
-// Validate checks that critical selector fields are non-empty.
+// Validate checks that the selectors the scraper can't work without are set
+// (whitespace doesn't count): the list item, title link and posted time, and
+// at least one deal-link selector for detail pages.
 func (c SelectorConfig) Validate() error {
 	var missing []string
-	if c.HotDealsList.Container.Item == "" {
-		missing = append(missing, "hot_deals_list.container.item")
+	for _, field := range []struct{ name, value string }{
+		{"hot_deals_list.container.item", c.HotDealsList.Container.Item},
+		{"hot_deals_list.elements.title_link", c.HotDealsList.Elements.TitleLink},
+		{"hot_deals_list.elements.posted_time", c.HotDealsList.Elements.PostedTime},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
 	}
-	if c.HotDealsList.Elements.TitleLink == "" {
-		missing = append(missing, "hot_deals_list.elements.title_link")
-	}
-	if c.HotDealsList.Elements.PostedTime == "" {
-		missing = append(missing, "hot_deals_list.elements.posted_time")
+	if len(c.DealDetails.PrimaryLink) == 0 && len(c.DealDetails.FallbackLink) == 0 {
+		missing = append(missing, "deal_details.primary_link or deal_details.fallback_link")
 	}
 	if len(missing) > 0 {
 		return fmt.Errorf("missing required selectors: %s", strings.Join(missing, ", "))
 	}
+	switch c.DealDetails.LinkPolicy {
+	case "", LinkPolicyPreferPrimary, LinkPolicyPreferExternal:
+	default:
+		return fmt.Errorf("unknown deal_details.link_policy %q", c.DealDetails.LinkPolicy)
+	}
 	return nil
 }
 
@@ -102,12 +154,22 @@ func DefaultSelectors() SelectorConfig {
 				CommentCount:         ".thread_extra_info .posts",
 				CommentCountFallback: ".posts_count",
 				ViewCount:            "",
+				AuthorName:           ".author_name",
 			},
 		},
 		DealDetails: DetailSelectors{
-			PrimaryLink:  ".deal_link a",
-			FallbackLink: ".postlink",
+			// RFD thread templates render the deal button several ways.
+			PrimaryLink: SelectorList{
+				".deal_link a",
+				"a.deal_link",
+				".primary_deal_link a",
+				"a.primary_deal_link",
+				"a.get-deal-button",
+				"[data-deal-url]",
+			},
+			FallbackLink: SelectorList{".postlink"},
 			Category:     ".thread_category",
+			LinkPolicy:   LinkPolicyPreferPrimary,
 		},
 	}
 }
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/logger"
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+// MigrateDealIDs moves deals stored under legacy auto-generated IDs to the
+// deterministic ID derived from their published timestamp, returning how many
+// documents were moved. Each document is copied before the legacy row is
+// deleted, so an interrupted run can simply be repeated.
+func (c *Client) MigrateDealIDs(ctx context.Context) (int, error) {
+	return c.migrateDealIDs(ctx, dealsCollection)
+}
+
+func (c *Client) migrateDealIDs(ctx context.Context, collection string) (int, error) {
+	rows, err := c.ListDocuments(ctx, collection)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	var errs []error
+	for _, row := range rows {
+		var deal models.DealInfo
+		if err := decodeDocument(row.Data, &deal); err != nil {
+			errs = append(errs, fmt.Errorf("decode %s: %w", row.ID, err))
+			continue
+		}
+		if deal.PublishedTimestamp.IsZero() {
+			slog.Warn("MigrateDealIDs: deal has no published timestamp, leaving in place", "id", row.ID)
+			continue
+		}
+		// Deals that shared a timestamp keep their disambiguated IDs.
+		if models.IsDealIDFor(row.ID, deal.PublishedTimestamp) {
+			continue
+		}
+		newID := models.DealID(deal.PublishedTimestamp)
+
+		// If the processor already wrote the deterministic document, it is
+		// newer than the legacy copy and wins.
+		_, exists, err := c.GetRawDocument(ctx, collection, newID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lookup %s: %w", newID, err))
+			continue
+		}
+		if !exists {
+			if err := c.SetRawDocument(ctx, collection, newID, row.Data); err != nil {
+				errs = append(errs, fmt.Errorf("copy %s -> %s: %w", row.ID, newID, err))
+				continue
+			}
+		}
+		if err := c.DeleteDocument(ctx, collection, row.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete legacy %s: %w", row.ID, err))
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		logger.Notice("MigrateDealIDs: moved legacy deals", "migrated", migrated, "total", len(rows))
+	}
+	return migrated, errors.Join(errs...)
+}
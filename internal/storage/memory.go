@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+// MemoryStore is an in-process deal store for local development and tests.
+// It implements the same deal methods as Client but keeps everything in a map,
+// so nothing survives a restart.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	deals         map[string]models.DealInfo
+	subscriptions []models.Subscription
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{deals: make(map[string]models.DealInfo)}
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+func (m *MemoryStore) Backend() string {
+	return "memory"
+}
+
+// cloneDeal copies the reference-typed fields so callers cannot mutate stored state.
+func cloneDeal(deal models.DealInfo) models.DealInfo {
+	deal.DiscordMessageIDs = maps.Clone(deal.DiscordMessageIDs)
+	deal.Threads = slices.Clone(deal.Threads)
+	deal.SearchTokens = slices.Clone(deal.SearchTokens)
+	return deal
+}
+
+func (m *MemoryStore) GetDealByID(ctx context.Context, id string) (*models.DealInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	deal, ok := m.deals[id]
+	if !ok {
+		return nil, nil
+	}
+	deal = cloneDeal(deal)
+	return &deal, nil
+}
+
+func (m *MemoryStore) GetDealsByIDs(ctx context.Context, ids []string) (map[string]*models.DealInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]*models.DealInfo, len(ids))
+	for _, id := range ids {
+		if deal, ok := m.deals[id]; ok {
+			deal = cloneDeal(deal)
+			result[id] = &deal
+		}
+	}
+	return result, nil
+}
+
+func (m *MemoryStore) GetRecentDeals(ctx context.Context, d time.Duration) ([]models.DealInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	since := time.Now().Add(-d)
+	deals := make([]models.DealInfo, 0, len(m.deals))
+	for _, deal := range m.deals {
+		if deal.PublishedTimestamp.Before(since) {
+			continue
+		}
+		deals = append(deals, cloneDeal(deal))
+	}
+	sortDealsByPublished(deals)
+	return deals, nil
+}
+
+func (m *MemoryStore) TryCreateDeal(ctx context.Context, deal models.DealInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createLocked(deal)
+}
+
+func (m *MemoryStore) createLocked(deal models.DealInfo) error {
+	if _, ok := m.deals[deal.DocumentID]; ok {
+		return models.ErrDealExists
+	}
+	m.deals[deal.DocumentID] = cloneDeal(prepareDealForCreate(deal))
+	return nil
+}
+
+func (m *MemoryStore) UpdateDeal(ctx context.Context, deal models.DealInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updateLocked(deal)
+	return nil
+}
+
+// updateLocked keeps the stored creation-only fields, matching Client.
+func (m *MemoryStore) updateLocked(deal models.DealInfo) {
+	if stored, ok := m.deals[deal.DocumentID]; ok && !stored.FirstSeen.IsZero() {
+		deal.FirstSeen = stored.FirstSeen
+	}
+	m.deals[deal.DocumentID] = cloneDeal(prepareDealForStorage(deal))
+}
+
+// TrimOldDeals keeps the maxDeals most recently updated deals, matching Client.
+func (m *MemoryStore) TrimOldDeals(ctx context.Context, maxDeals int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if maxDeals < 0 || len(m.deals) <= maxDeals {
+		return nil
+	}
+	ids := make([]string, 0, len(m.deals))
+	for id := range m.deals {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return m.deals[ids[i]].LastUpdated.After(m.deals[ids[j]].LastUpdated)
+	})
+	for _, id := range ids[maxDeals:] {
+		delete(m.deals, id)
+	}
+	return nil
+}
+
+// PurgeAll deletes every deal and returns how many were removed.
+func (m *MemoryStore) PurgeAll(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := len(m.deals)
+	clear(m.deals)
+	return deleted, nil
+}
+
+func (m *MemoryStore) BatchWrite(ctx context.Context, creates []models.DealInfo, updates []models.DealInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for _, d := range creates {
+		if err := m.createLocked(d); err != nil {
+			errs = append(errs, fmt.Errorf("create %s: %w", d.DocumentID, err))
+		}
+	}
+	for _, d := range updates {
+		m.updateLocked(d)
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// SetSubscriptions replaces the subscriptions returned by GetAllSubscriptions.
+func (m *MemoryStore) SetSubscriptions(subs []models.Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions = slices.Clone(subs)
+}
+
+func (m *MemoryStore) GetAllSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.subscriptions), nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+func TestMemoryStoreDealRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	deal := models.DealInfo{
+		DocumentID:         "deal-1",
+		Title:              "Test Deal",
+		PublishedTimestamp: now,
+		LastUpdated:        now,
+		DiscordMessageIDs:  map[string]string{"chan": "msg"},
+	}
+	if err := store.TryCreateDeal(ctx, deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	if err := store.TryCreateDeal(ctx, deal); !errors.Is(err, models.ErrDealExists) {
+		t.Fatalf("TryCreateDeal() duplicate error = %v, want ErrDealExists", err)
+	}
+
+	got, err := store.GetDealByID(ctx, "deal-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetDealByID() = %v, %v", got, err)
+	}
+	if got.Title != "Test Deal" || got.ExpiresAt.IsZero() {
+		t.Fatalf("GetDealByID() = %+v, want title and expiry set", got)
+	}
+
+	got.DiscordMessageIDs["chan"] = "mutated"
+	again, _ := store.GetDealByID(ctx, "deal-1")
+	if again.DiscordMessageIDs["chan"] != "msg" {
+		t.Fatalf("stored deal was mutated through returned copy")
+	}
+
+	deal.Title = "Updated Deal"
+	if err := store.UpdateDeal(ctx, deal); err != nil {
+		t.Fatalf("UpdateDeal() error = %v", err)
+	}
+	byID, err := store.GetDealsByIDs(ctx, []string{"deal-1", "missing"})
+	if err != nil {
+		t.Fatalf("GetDealsByIDs() error = %v", err)
+	}
+	if len(byID) != 1 || byID["deal-1"].Title != "Updated Deal" {
+		t.Fatalf("GetDealsByIDs() = %+v", byID)
+	}
+
+	if missing, err := store.GetDealByID(ctx, "missing"); missing != nil || err != nil {
+		t.Fatalf("GetDealByID(missing) = %v, %v, want nil, nil", missing, err)
+	}
+	if err := store.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+}
+
+func TestMemoryStoreBatchWriteAndRecent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	creates := []models.DealInfo{
+		{DocumentID: "new", PublishedTimestamp: now.Add(-time.Hour)},
+		{DocumentID: "old", PublishedTimestamp: now.Add(-72 * time.Hour)},
+	}
+	updates := []models.DealInfo{{DocumentID: "newest", PublishedTimestamp: now}}
+	if err := store.BatchWrite(ctx, creates, updates); err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	if err := store.BatchWrite(ctx, creates[:1], nil); !errors.Is(err, models.ErrDealExists) {
+		t.Fatalf("BatchWrite() duplicate create error = %v, want ErrDealExists", err)
+	}
+
+	recent, err := store.GetRecentDeals(ctx, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentDeals() error = %v", err)
+	}
+	if len(recent) != 2 || recent[0].DocumentID != "newest" || recent[1].DocumentID != "new" {
+		t.Fatalf("GetRecentDeals() = %+v, want newest then new", recent)
+	}
+}
+
+func TestMemoryStoreTrimOldDeals(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	for i, id := range []string{"a", "b", "c", "d"} {
+		deal := models.DealInfo{DocumentID: id, LastUpdated: now.Add(time.Duration(i) * time.Minute)}
+		if err := store.TryCreateDeal(ctx, deal); err != nil {
+			t.Fatalf("TryCreateDeal(%s) error = %v", id, err)
+		}
+	}
+
+	if err := store.TrimOldDeals(ctx, 2); err != nil {
+		t.Fatalf("TrimOldDeals() error = %v", err)
+	}
+	remaining, _ := store.GetDealsByIDs(ctx, []string{"a", "b", "c", "d"})
+	if len(remaining) != 2 || remaining["c"] == nil || remaining["d"] == nil {
+		t.Fatalf("TrimOldDeals() kept %v, want c and d", remaining)
+	}
+}
+
+func TestMemoryStorePurgeAll(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.TryCreateDeal(ctx, models.DealInfo{DocumentID: id}); err != nil {
+			t.Fatalf("TryCreateDeal(%s) error = %v", id, err)
+		}
+	}
+
+	deleted, err := store.PurgeAll(ctx)
+	if err != nil || deleted != 3 {
+		t.Fatalf("PurgeAll() = %d, %v, want 3, nil", deleted, err)
+	}
+	recent, _ := store.GetRecentDeals(ctx, time.Hour)
+	if len(recent) != 0 {
+		t.Fatalf("GetRecentDeals() after purge = %d deals, want 0", len(recent))
+	}
+}
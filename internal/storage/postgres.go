@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
@@ -18,18 +20,34 @@ import (
 
 var errDocumentExists = errors.New("document already exists")
 
-// Document is a raw JSONB-backed document row.
+// errPreconditionFailed is returned by conditional writes when the document
+// was changed (or deleted) after it was read.
+var errPreconditionFailed = errors.New("document changed since it was read")
+
+// Document is a raw JSONB-backed document row. UpdatedAt is only populated by
+// GetRawDocument, for use as an UpdateRawDocumentIfUnchanged precondition.
 type Document struct {
-	ID   string
-	Data map[string]any
+	ID        string
+	Data      map[string]any
+	UpdatedAt time.Time
 }
 
 func (c *Client) usesPostgres() bool {
 	return c != nil && c.pg != nil
 }
 
-func (c *Client) ensurePostgresSchema(ctx context.Context) error {
-	_, err := c.pg.Exec(ctx, `
+// documentIndex is an index the documents queries rely on.
+type documentIndex struct {
+	name   string
+	create string
+}
+
+var documentIndexes = []documentIndex{
+	{"documents_collection_updated_idx", "CREATE INDEX IF NOT EXISTS documents_collection_updated_idx ON documents (collection, updated_at DESC);"},
+	{"documents_data_gin_idx", "CREATE INDEX IF NOT EXISTS documents_data_gin_idx ON documents USING gin (data jsonb_path_ops);"},
+}
+
+const documentsTableSQL = `
 CREATE TABLE IF NOT EXISTS documents (
 	collection text NOT NULL,
 	doc_id text NOT NULL,
@@ -37,16 +55,93 @@ CREATE TABLE IF NOT EXISTS documents (
 	created_at timestamptz NOT NULL DEFAULT now(),
 	updated_at timestamptz NOT NULL DEFAULT now(),
 	PRIMARY KEY (collection, doc_id)
-);
-CREATE INDEX IF NOT EXISTS documents_collection_updated_idx ON documents (collection, updated_at DESC);
-CREATE INDEX IF NOT EXISTS documents_data_gin_idx ON documents USING gin (data jsonb_path_ops);
-`)
-	if err != nil {
-		return fmt.Errorf("failed to initialize postgres document schema: %w", err)
+);`
+
+func documentsSchemaSQL() string {
+	var b strings.Builder
+	b.WriteString(documentsTableSQL)
+	for _, index := range documentIndexes {
+		b.WriteString("\n" + index.create)
+	}
+	return b.String()
+}
+
+// ensurePostgresSchema creates the documents table and its indexes. A role
+// without CREATE rights can still run against a schema an admin applied, so
+// insufficient privilege only fails startup when the table itself is missing;
+// missing indexes are logged with the statement that creates them.
+func (c *Client) ensurePostgresSchema(ctx context.Context) error {
+	_, err := c.pg.Exec(ctx, documentsSchemaSQL())
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgInsufficientPrivilege {
+		return fmt.Errorf("failed to initialize postgres document schema: %w", describeSchemaError(err))
+	}
+	missing, tableExists, checkErr := c.missingDocumentIndexes(ctx)
+	if checkErr != nil || !tableExists {
+		return fmt.Errorf("failed to initialize postgres document schema: %w", describeSchemaError(err))
+	}
+	slog.Warn("Postgres role cannot create the document schema; using the existing table", "error", err)
+	for _, index := range missing {
+		slog.Warn("Postgres index missing; deal queries will be slow until an admin creates it",
+			"index", index.name,
+			"create", index.create,
+		)
 	}
 	return nil
 }
 
+// missingDocumentIndexes lists the documentIndexes not present on the
+// documents table, and whether the table exists (its primary key does).
+func (c *Client) missingDocumentIndexes(ctx context.Context) (missing []documentIndex, tableExists bool, err error) {
+	var names []string
+	err = c.pg.QueryRow(ctx, `
+SELECT coalesce(array_agg(indexname::text), '{}')
+FROM pg_indexes
+WHERE schemaname = current_schema() AND tablename = 'documents'`).Scan(&names)
+	if err != nil {
+		return nil, false, fmt.Errorf("list documents indexes: %w", err)
+	}
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+	for _, index := range documentIndexes {
+		if !present[index.name] {
+			missing = append(missing, index)
+		}
+	}
+	return missing, present["documents_pkey"], nil
+}
+
+// Postgres SQLSTATE codes for schema problems.
+const (
+	pgUndefinedTable        = "42P01"
+	pgUndefinedColumn       = "42703"
+	pgInsufficientPrivilege = "42501"
+)
+
+// describeSchemaError rewrites the Postgres errors a missing or unmigrated
+// documents schema produces into an actionable message that includes the SQL
+// to apply. Other errors are returned unchanged.
+func describeSchemaError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUndefinedTable, pgUndefinedColumn:
+		return fmt.Errorf("documents schema is missing or out of date (%s); check DATABASE_URL points at the bot's database, or apply:%s\n: %w",
+			pgErr.Message, documentsSchemaSQL(), err)
+	case pgInsufficientPrivilege:
+		return fmt.Errorf("database role cannot create the documents schema (%s); grant it CREATE on the schema, or have an admin apply:%s\n: %w",
+			pgErr.Message, documentsSchemaSQL(), err)
+	}
+	return err
+}
+
 // SetDocument upserts one JSONB document while preserving the supplied document ID.
 func (c *Client) SetDocument(ctx context.Context, collection, docID string, value any) error {
 	data, err := encodeDocument(value)
@@ -56,6 +151,25 @@ func (c *Client) SetDocument(ctx context.Context, collection, docID string, valu
 	return c.SetRawDocument(ctx, collection, docID, data)
 }
 
+// createDocumentSQL inserts a document unless its ID is taken; callers
+// check RowsAffected to report errDocumentExists.
+const createDocumentSQL = `
+INSERT INTO documents (collection, doc_id, data)
+VALUES ($1, $2, $3::jsonb)
+ON CONFLICT DO NOTHING`
+
+// setDocumentPreservingSQL upserts a document, keeping the stored values of
+// the keys listed in $4.
+const setDocumentPreservingSQL = `
+INSERT INTO documents (collection, doc_id, data)
+VALUES ($1, $2, $3::jsonb)
+ON CONFLICT (collection, doc_id)
+DO UPDATE SET data = EXCLUDED.data || COALESCE((
+	SELECT jsonb_object_agg(key, documents.data -> key)
+	FROM unnest($4::text[]) AS key
+	WHERE documents.data ? key
+), '{}'::jsonb), updated_at = now()`
+
 // CreateDocument creates one JSONB document and returns errDocumentExists if it already exists.
 func (c *Client) CreateDocument(ctx context.Context, collection, docID string, value any) error {
 	data, err := encodeDocument(value)
@@ -66,10 +180,7 @@ func (c *Client) CreateDocument(ctx context.Context, collection, docID string, v
 	if err != nil {
 		return fmt.Errorf("marshal document %s/%s: %w", collection, docID, err)
 	}
-	tag, err := c.pg.Exec(ctx, `
-INSERT INTO documents (collection, doc_id, data)
-VALUES ($1, $2, $3::jsonb)
-ON CONFLICT DO NOTHING`, collection, docID, payload)
+	tag, err := c.pg.Exec(ctx, createDocumentSQL, collection, docID, payload)
 	if err != nil {
 		return fmt.Errorf("create document %s/%s: %w", collection, docID, err)
 	}
@@ -153,6 +264,23 @@ DO UPDATE SET data = EXCLUDED.data, updated_at = now()`, collection, docID, payl
 	return nil
 }
 
+// SetRawDocumentPreserving upserts like SetRawDocument but keeps the stored
+// values of the preserve keys when the document already has them.
+func (c *Client) SetRawDocumentPreserving(ctx context.Context, collection, docID string, data map[string]any, preserve []string) error {
+	if collection == "" || docID == "" {
+		return fmt.Errorf("collection and docID are required")
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal document %s/%s: %w", collection, docID, err)
+	}
+	_, err = c.pg.Exec(ctx, setDocumentPreservingSQL, collection, docID, payload, preserve)
+	if err != nil {
+		return fmt.Errorf("set document %s/%s: %w", collection, docID, err)
+	}
+	return nil
+}
+
 func (c *Client) AddDocument(ctx context.Context, collection string, value any) (string, error) {
 	for i := 0; i < 5; i++ {
 		docID := randomDocumentID()
@@ -178,7 +306,8 @@ func (c *Client) GetDocument(ctx context.Context, collection, docID string, dst
 
 func (c *Client) GetRawDocument(ctx context.Context, collection, docID string) (Document, bool, error) {
 	var payload []byte
-	err := c.pg.QueryRow(ctx, `SELECT data FROM documents WHERE collection=$1 AND doc_id=$2`, collection, docID).Scan(&payload)
+	var updatedAt time.Time
+	err := c.pg.QueryRow(ctx, `SELECT data, updated_at FROM documents WHERE collection=$1 AND doc_id=$2`, collection, docID).Scan(&payload, &updatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Document{}, false, nil
@@ -189,7 +318,27 @@ func (c *Client) GetRawDocument(ctx context.Context, collection, docID string) (
 	if err := json.Unmarshal(payload, &data); err != nil {
 		return Document{}, false, fmt.Errorf("unmarshal document %s/%s: %w", collection, docID, err)
 	}
-	return Document{ID: docID, Data: data}, true, nil
+	return Document{ID: docID, Data: data, UpdatedAt: updatedAt}, true, nil
+}
+
+// UpdateRawDocumentIfUnchanged replaces a document's data only if its
+// updated_at still equals updatedAt (as returned by GetRawDocument), and
+// returns errPreconditionFailed otherwise.
+func (c *Client) UpdateRawDocumentIfUnchanged(ctx context.Context, collection, docID string, data map[string]any, updatedAt time.Time) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal document %s/%s: %w", collection, docID, err)
+	}
+	tag, err := c.pg.Exec(ctx, `
+UPDATE documents SET data = $3::jsonb, updated_at = clock_timestamp()
+WHERE collection=$1 AND doc_id=$2 AND updated_at=$4`, collection, docID, payload, updatedAt)
+	if err != nil {
+		return fmt.Errorf("update document %s/%s: %w", collection, docID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errPreconditionFailed
+	}
+	return nil
 }
 
 func (c *Client) DeleteDocument(ctx context.Context, collection, docID string) error {
@@ -287,6 +436,33 @@ func (c *Client) DeleteDocuments(ctx context.Context, collection string, docIDs
 	return tag.RowsAffected(), nil
 }
 
+// ListDocumentIDs returns the IDs of every document in collection.
+func (c *Client) ListDocumentIDs(ctx context.Context, collection string) ([]string, error) {
+	rows, err := c.pg.Query(ctx, `SELECT doc_id FROM documents WHERE collection=$1 ORDER BY doc_id`, collection)
+	if err != nil {
+		return nil, fmt.Errorf("list document ids %s: %w", collection, err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteCollection removes every document in collection.
+func (c *Client) DeleteCollection(ctx context.Context, collection string) (int64, error) {
+	tag, err := c.pg.Exec(ctx, `DELETE FROM documents WHERE collection=$1`, collection)
+	if err != nil {
+		return 0, fmt.Errorf("delete collection %s: %w", collection, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 func (c *Client) DeleteDocumentsWhere(ctx context.Context, collection string, fields map[string]any) (int64, error) {
 	if len(fields) == 0 {
 		return 0, fmt.Errorf("delete documents %s: predicate is required", collection)
@@ -303,20 +479,30 @@ func (c *Client) DeleteDocumentsWhere(ctx context.Context, collection string, fi
 }
 
 func (c *Client) DeleteOldestDocuments(ctx context.Context, collection, timeKey string, keepMax int) (int, error) {
-	rows, err := c.ListDocuments(ctx, collection)
+	ids, err := c.oldestDocumentIDs(ctx, collection, timeKey, keepMax)
 	if err != nil {
 		return 0, err
 	}
+	deleted, err := c.DeleteDocuments(ctx, collection, ids)
+	return int(deleted), err
+}
+
+// oldestDocumentIDs returns the IDs beyond the keepMax newest documents by
+// timeKey, oldest first.
+func (c *Client) oldestDocumentIDs(ctx context.Context, collection, timeKey string, keepMax int) ([]string, error) {
+	rows, err := c.ListDocuments(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
 	if keepMax < 0 || len(rows) <= keepMax {
-		return 0, nil
+		return nil, nil
 	}
 	sortDocumentsByTime(rows, timeKey, true)
 	ids := make([]string, 0, len(rows)-keepMax)
 	for _, row := range rows[:len(rows)-keepMax] {
 		ids = append(ids, row.ID)
 	}
-	deleted, err := c.DeleteDocuments(ctx, collection, ids)
-	return int(deleted), err
+	return ids, nil
 }
 
 func (c *Client) PruneDocumentsByTime(ctx context.Context, collection, timeKey string, cutoff time.Time, maxRecords int) (int, error) {
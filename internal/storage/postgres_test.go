@@ -2,14 +2,22 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
 	"github.com/pauljones0/rfd-discord-bot/internal/bestbuy"
 	"github.com/pauljones0/rfd-discord-bot/internal/crux"
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
 )
 
 func TestEncodeDecodeDocumentPrefersDocstoreTags(t *testing.T) {
@@ -41,6 +49,316 @@ func TestEncodeDecodeDocumentPrefersDocstoreTags(t *testing.T) {
 	}
 }
 
+func TestRetryTrimRecoversFromTransientFailure(t *testing.T) {
+	calls := 0
+	deleted, err := retryTrim(context.Background(), func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, fmt.Errorf("connection reset by peer")
+		}
+		return 7, nil
+	})
+	if err != nil {
+		t.Fatalf("retryTrim() error = %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("trim calls = %d, want 2", calls)
+	}
+	if deleted != 7 {
+		t.Fatalf("deleted = %d, want 7", deleted)
+	}
+}
+
+func TestDeleteInBatchesFlushesEveryBatchSize(t *testing.T) {
+	ids := make([]string, 1203)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("deal-%d", i)
+	}
+
+	var flushes []int
+	deleted, err := deleteInBatches(context.Background(), ids, 500, func(_ context.Context, batch []string) (int64, error) {
+		flushes = append(flushes, len(batch))
+		return int64(len(batch)), nil
+	})
+	if err != nil {
+		t.Fatalf("deleteInBatches() error = %v", err)
+	}
+	if deleted != int64(len(ids)) {
+		t.Fatalf("deleted = %d, want %d", deleted, len(ids))
+	}
+	if want := []int{500, 500, 203}; !slices.Equal(flushes, want) {
+		t.Fatalf("flush sizes = %v, want %v", flushes, want)
+	}
+
+	flushes = nil
+	if _, err := deleteInBatches(context.Background(), ids, 0, func(_ context.Context, batch []string) (int64, error) {
+		flushes = append(flushes, len(batch))
+		return int64(len(batch)), nil
+	}); err != nil || !slices.Equal(flushes, []int{len(ids)}) {
+		t.Fatalf("unbatched flush sizes = %v, %v, want one flush of %d", flushes, err, len(ids))
+	}
+}
+
+func TestDeleteInBatchesStopsAtFailedBatch(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+	calls := 0
+	deleted, err := deleteInBatches(context.Background(), ids, 2, func(_ context.Context, batch []string) (int64, error) {
+		calls++
+		if calls == 2 {
+			return 0, fmt.Errorf("connection reset by peer")
+		}
+		return int64(len(batch)), nil
+	})
+	if err == nil || deleted != 2 || calls != 2 {
+		t.Fatalf("deleteInBatches() = %d, %v after %d calls, want 2 deleted, an error, 2 calls", deleted, err, calls)
+	}
+}
+
+func TestPostgresTrimOldDealsInBatchesIntegration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	collection := fmt.Sprintf("test_trim_batches_%d", time.Now().UnixNano())
+	defer func() { _, _ = client.DeleteCollection(ctx, collection) }()
+	base := time.Now().UTC()
+	for i := range 25 {
+		id := fmt.Sprintf("deal-%02d", i)
+		if err := client.SetDocument(ctx, collection, id, map[string]any{"lastUpdated": base.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("SetDocument(%s) error = %v", id, err)
+		}
+	}
+
+	ids, err := client.oldestDocumentIDs(ctx, collection, "lastUpdated", 5)
+	if err != nil {
+		t.Fatalf("oldestDocumentIDs() error = %v", err)
+	}
+	flushes := 0
+	deleted, err := deleteInBatches(ctx, ids, 7, func(ctx context.Context, batch []string) (int64, error) {
+		flushes++
+		return client.DeleteDocuments(ctx, collection, batch)
+	})
+	if err != nil || deleted != 20 || flushes != 3 {
+		t.Fatalf("deleteInBatches() = %d, %v in %d flushes, want 20, nil in 3", deleted, err, flushes)
+	}
+	remaining, err := client.ListDocumentIDs(ctx, collection)
+	if err != nil {
+		t.Fatalf("ListDocumentIDs() error = %v", err)
+	}
+	if want := []string{"deal-20", "deal-21", "deal-22", "deal-23", "deal-24"}; !slices.Equal(remaining, want) {
+		t.Fatalf("remaining = %v, want the 5 newest %v", remaining, want)
+	}
+}
+
+// fakePool answers Exec and QueryRow with canned results; every other call
+// is unexpected in the tests that use it.
+type fakePool struct {
+	pgPool
+	execTag  pgconn.CommandTag
+	execErr  error
+	rowErr   error
+	rowValue any
+	lastExec string
+}
+
+func (f *fakePool) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	f.lastExec = sql
+	return f.execTag, f.execErr
+}
+
+func (f *fakePool) QueryRow(context.Context, string, ...any) pgx.Row {
+	return fakeRow{err: f.rowErr, value: f.rowValue}
+}
+
+// fakeRow scans value, if set, into the first destination.
+type fakeRow struct {
+	err   error
+	value any
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err == nil && r.value != nil && len(dest) > 0 {
+		reflect.ValueOf(dest[0]).Elem().Set(reflect.ValueOf(r.value))
+	}
+	return r.err
+}
+
+func TestDescribeSchemaError(t *testing.T) {
+	missing := &pgconn.PgError{Code: "42P01", Message: `relation "documents" does not exist`}
+	err := describeSchemaError(fmt.Errorf("get deal: %w", missing))
+	if !errors.Is(err, missing) {
+		t.Fatalf("describeSchemaError() = %v, want it to wrap the Postgres error", err)
+	}
+	for _, want := range []string{"documents schema is missing", "DATABASE_URL", "CREATE TABLE IF NOT EXISTS documents", "documents_data_gin_idx"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("describeSchemaError() = %q, want it to mention %q", err, want)
+		}
+	}
+
+	other := errors.New("connection reset by peer")
+	if err := describeSchemaError(other); err != other {
+		t.Errorf("describeSchemaError(%v) = %v, want it unchanged", other, err)
+	}
+}
+
+func TestEnsurePostgresSchemaWithoutCreatePrivilege(t *testing.T) {
+	denied := &pgconn.PgError{Code: "42501", Message: "permission denied for schema public"}
+
+	pool := &fakePool{execErr: denied, rowValue: []string{"documents_pkey", "documents_collection_updated_idx"}}
+	if err := newClient(pool).ensurePostgresSchema(context.Background()); err != nil {
+		t.Fatalf("ensurePostgresSchema() error = %v, want the existing table to be used", err)
+	}
+	missing, tableExists, err := newClient(pool).missingDocumentIndexes(context.Background())
+	if err != nil || !tableExists || len(missing) != 1 || missing[0].name != "documents_data_gin_idx" {
+		t.Fatalf("missingDocumentIndexes() = %v, %v, %v, want the gin index missing", missing, tableExists, err)
+	}
+
+	pool = &fakePool{execErr: denied, rowValue: []string{}}
+	err = newClient(pool).ensurePostgresSchema(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "cannot create the documents schema") {
+		t.Fatalf("ensurePostgresSchema() error = %v, want an actionable privilege error when the table is missing", err)
+	}
+}
+
+func TestGetDealByIDMissingRowIsNotAnError(t *testing.T) {
+	client := newClient(&fakePool{rowErr: pgx.ErrNoRows})
+	deal, err := client.GetDealByID(context.Background(), "missing")
+	if err != nil || deal != nil {
+		t.Fatalf("GetDealByID() = %v, %v, want nil, nil", deal, err)
+	}
+
+	dbErr := errors.New("connection reset by peer")
+	client = newClient(&fakePool{rowErr: dbErr})
+	if _, err := client.GetDealByID(context.Background(), "deal"); !errors.Is(err, dbErr) {
+		t.Fatalf("GetDealByID() error = %v, want wrapped %v", err, dbErr)
+	}
+}
+
+func TestTryCreateDealConflictReturnsErrDealExists(t *testing.T) {
+	pool := &fakePool{execTag: pgconn.NewCommandTag("INSERT 0 0")}
+	err := newClient(pool).TryCreateDeal(context.Background(), models.DealInfo{DocumentID: "deal", Title: "Deal"})
+	if !errors.Is(err, models.ErrDealExists) {
+		t.Fatalf("TryCreateDeal() error = %v, want models.ErrDealExists", err)
+	}
+	if !strings.Contains(pool.lastExec, "ON CONFLICT DO NOTHING") {
+		t.Fatalf("TryCreateDeal() ran %q, want a conflict-tolerant insert", pool.lastExec)
+	}
+
+	pool.execTag = pgconn.NewCommandTag("INSERT 0 1")
+	if err := newClient(pool).TryCreateDeal(context.Background(), models.DealInfo{DocumentID: "deal", Title: "Deal"}); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v, want nil for a fresh insert", err)
+	}
+}
+
+// fakeBatchPool answers SendBatch with one canned result per queued query.
+type fakeBatchPool struct {
+	fakePool
+	sends    int
+	queries  []string
+	results  []fakeExecResult
+	execs    []string         // document IDs written one at a time
+	execErrs map[string]error // per-document errors for single writes
+}
+
+func (f *fakeBatchPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var docID string
+	if len(args) > 1 {
+		docID, _ = args[1].(string)
+	}
+	f.execs = append(f.execs, docID)
+	if err := f.execErrs[docID]; err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return f.fakePool.Exec(ctx, sql, args...)
+}
+
+type fakeExecResult struct {
+	tag pgconn.CommandTag
+	err error
+}
+
+func (f *fakeBatchPool) SendBatch(_ context.Context, batch *pgx.Batch) pgx.BatchResults {
+	f.sends++
+	for _, q := range batch.QueuedQueries {
+		f.queries = append(f.queries, q.SQL)
+	}
+	return &fakeBatchResults{results: f.results}
+}
+
+type fakeBatchResults struct {
+	pgx.BatchResults
+	results []fakeExecResult
+}
+
+func (r *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	next := r.results[0]
+	r.results = r.results[1:]
+	return next.tag, next.err
+}
+
+func (r *fakeBatchResults) Close() error { return nil }
+
+func TestBatchWriteSendsOneBatchAndJoinsErrors(t *testing.T) {
+	pool := &fakeBatchPool{results: []fakeExecResult{
+		{tag: pgconn.NewCommandTag("INSERT 0 1")},
+		{tag: pgconn.NewCommandTag("INSERT 0 0")},
+		{tag: pgconn.NewCommandTag("INSERT 0 1")},
+		{tag: pgconn.NewCommandTag("INSERT 0 1")},
+	}}
+	creates := []models.DealInfo{{DocumentID: "new-1", Title: "New 1"}, {DocumentID: "taken", Title: "Taken"}}
+	updates := []models.DealInfo{{DocumentID: "old-1", Title: "Old 1"}, {DocumentID: "old-2", Title: "Old 2"}}
+
+	err := newClient(pool).BatchWrite(context.Background(), creates, updates)
+	if pool.sends != 1 || len(pool.queries) != 4 || len(pool.execs) != 0 {
+		t.Fatalf("sent %d batches of %d queries and %d single writes, want 1 batch of 4", pool.sends, len(pool.queries), len(pool.execs))
+	}
+	if !strings.Contains(pool.queries[0], "ON CONFLICT DO NOTHING") || !strings.Contains(pool.queries[2], "jsonb_object_agg") {
+		t.Errorf("queries = %q, want creates as conflict-tolerant inserts and updates preserving creation-only fields", pool.queries)
+	}
+	if !errors.Is(err, errDocumentExists) || !strings.Contains(err.Error(), "create taken") {
+		t.Fatalf("BatchWrite() error = %v, want the existing create reported", err)
+	}
+
+	if err := newClient(pool).BatchWrite(context.Background(), nil, nil); err != nil || pool.sends != 1 {
+		t.Errorf("empty BatchWrite() = %v after %d sends, want nil without a round trip", err, pool.sends)
+	}
+}
+
+func TestBatchWriteRetriesWritesAfterMidBatchFailure(t *testing.T) {
+	updateErr := errors.New("value too long")
+	aborted := errors.New("current transaction is aborted")
+	pool := &fakeBatchPool{
+		results: []fakeExecResult{
+			{tag: pgconn.NewCommandTag("INSERT 0 1")},
+			{err: updateErr},
+			{err: aborted},
+		},
+		execErrs: map[string]error{"old-1": updateErr},
+	}
+	pool.execTag = pgconn.NewCommandTag("INSERT 0 1")
+	creates := []models.DealInfo{{DocumentID: "new-1", Title: "New 1"}}
+	updates := []models.DealInfo{{DocumentID: "old-1", Title: "Old 1"}, {DocumentID: "old-2", Title: "Old 2"}}
+
+	err := newClient(pool).BatchWrite(context.Background(), creates, updates)
+	if want := []string{"new-1", "old-1", "old-2"}; !slices.Equal(pool.execs, want) {
+		t.Fatalf("retried writes = %v, want every write of the rolled-back batch retried: %v", pool.execs, want)
+	}
+	if !errors.Is(err, updateErr) || errors.Is(err, aborted) {
+		t.Fatalf("BatchWrite() error = %v, want only the failing update's error", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "update old-1") || strings.Contains(msg, "new-1") || strings.Contains(msg, "old-2") {
+		t.Errorf("BatchWrite() error = %q, want only the failed write named", msg)
+	}
+}
+
 func TestPostgresDocumentHelpersIntegration(t *testing.T) {
 	dsn := os.Getenv("POSTGRES_TEST_DSN")
 	if dsn == "" {
@@ -273,3 +591,241 @@ func TestBestBuySoldCompSnapshotIntegration(t *testing.T) {
 		t.Fatalf("snapshot = %#v, want saved snapshot", got)
 	}
 }
+
+func TestPostgresMigrateDealIDsIntegration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	collection := fmt.Sprintf("test_deal_migration_%d", time.Now().UnixNano())
+	published := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	collisionID := models.CollisionDealID(models.DealID(published), "rfd:2800002")
+	defer func() {
+		_, _ = client.DeleteDocuments(ctx, collection, []string{"autoGeneratedID123", models.DealID(published), collisionID})
+	}()
+
+	legacy := models.DealInfo{
+		Title:              "Legacy Deal",
+		PostURL:            "https://forums.redflagdeals.com/legacy-1/",
+		PublishedTimestamp: published,
+		DiscordMessageIDs:  map[string]string{"chan": "msg"},
+	}
+	if err := client.SetDocument(ctx, collection, "autoGeneratedID123", legacy); err != nil {
+		t.Fatalf("SetDocument() error = %v", err)
+	}
+	// A second deal published in the same second, stored by the processor
+	// under a disambiguated ID, must stay where it is.
+	collision := models.DealInfo{
+		Title:              "Same-Second Deal",
+		PostURL:            "https://forums.redflagdeals.com/same-second-2800002/",
+		PublishedTimestamp: published,
+	}
+	if err := client.SetDocument(ctx, collection, collisionID, collision); err != nil {
+		t.Fatalf("SetDocument(collision) error = %v", err)
+	}
+
+	migrated, err := client.migrateDealIDs(ctx, collection)
+	if err != nil || migrated != 1 {
+		t.Fatalf("migrateDealIDs() = %d, %v, want 1, nil", migrated, err)
+	}
+
+	if _, ok, err := client.GetRawDocument(ctx, collection, "autoGeneratedID123"); err != nil || ok {
+		t.Fatalf("legacy document still present: ok=%v err=%v", ok, err)
+	}
+	var got models.DealInfo
+	ok, err := client.GetDocument(ctx, collection, models.DealID(published), &got)
+	if err != nil || !ok {
+		t.Fatalf("GetDocument(new ID) ok=%v err=%v", ok, err)
+	}
+	if got.Title != legacy.Title || got.PostURL != legacy.PostURL || got.DiscordMessageIDs["chan"] != "msg" {
+		t.Fatalf("migrated deal = %+v, want data preserved", got)
+	}
+
+	if _, ok, err := client.GetRawDocument(ctx, collection, collisionID); err != nil || !ok {
+		t.Fatalf("disambiguated deal was removed: ok=%v err=%v", ok, err)
+	}
+
+	migrated, err = client.migrateDealIDs(ctx, collection)
+	if err != nil || migrated != 0 {
+		t.Fatalf("second migrateDealIDs() = %d, %v, want 0, nil", migrated, err)
+	}
+}
+
+func TestPostgresDeleteCollectionIntegration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	collection := fmt.Sprintf("test_purge_%d", time.Now().UnixNano())
+	for _, id := range []string{"a", "b", "c"} {
+		if err := client.SetDocument(ctx, collection, id, map[string]any{"title": id}); err != nil {
+			t.Fatalf("SetDocument(%s) error = %v", id, err)
+		}
+	}
+
+	deleted, err := client.DeleteCollection(ctx, collection)
+	if err != nil || deleted != 3 {
+		t.Fatalf("DeleteCollection() = %d, %v, want 3, nil", deleted, err)
+	}
+	rows, err := client.ListDocuments(ctx, collection)
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(rows) != 0 {
+		t.Fatalf("ListDocuments() after purge = %d rows, want 0", len(rows))
+	}
+}
+
+func TestPostgresModifyDealRetriesOnConcurrentWrite(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	id := fmt.Sprintf("test-modify-deal-%d", time.Now().UnixNano())
+	defer func() { _ = client.DeleteDocument(ctx, dealsCollection, id) }()
+	if err := client.TryCreateDeal(ctx, models.DealInfo{DocumentID: id, Title: "Deal", PublishedTimestamp: time.Now()}); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+
+	snoozeUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	attempts := 0
+	got, err := client.ModifyDeal(ctx, id, func(deal *models.DealInfo) {
+		attempts++
+		if attempts == 1 {
+			// A run saves the Discord message ID between our read and write.
+			concurrent := *deal
+			concurrent.DiscordMessageIDs = map[string]string{"chan": "msg-1"}
+			if err := client.UpdateDeal(ctx, concurrent); err != nil {
+				t.Fatalf("concurrent UpdateDeal() error = %v", err)
+			}
+		}
+		deal.SnoozeUntil = snoozeUntil
+	})
+	if err != nil {
+		t.Fatalf("ModifyDeal() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("mutate called %d times, want 2 (one retry after the conflict)", attempts)
+	}
+	if got.DiscordMessageIDs["chan"] != "msg-1" || !got.SnoozeUntil.Equal(snoozeUntil) {
+		t.Fatalf("ModifyDeal() = %+v, want both the concurrent message ID and the snooze", got)
+	}
+
+	stored, err := client.GetDealByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetDealByID() error = %v", err)
+	}
+	if stored.DiscordMessageIDs["chan"] != "msg-1" || !stored.SnoozeUntil.Equal(snoozeUntil) {
+		t.Fatalf("stored deal = %+v, want the concurrent message ID kept", stored)
+	}
+
+	missing, err := client.ModifyDeal(ctx, id+"-missing", func(*models.DealInfo) {})
+	if err != nil || missing != nil {
+		t.Fatalf("ModifyDeal(missing) = %v, %v, want nil, nil", missing, err)
+	}
+}
+
+func TestPostgresUpdateDealPreservesFirstSeen(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	id := fmt.Sprintf("test-first-seen-%d", time.Now().UnixNano())
+	defer func() { _ = client.DeleteDocument(ctx, dealsCollection, id) }()
+	firstSeen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	deal := models.DealInfo{DocumentID: id, Title: "Original", PublishedTimestamp: firstSeen, FirstSeen: firstSeen}
+	if err := client.TryCreateDeal(ctx, deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+
+	deal.Title = "Rescraped"
+	deal.FirstSeen = time.Time{}
+	if err := client.UpdateDeal(ctx, deal); err != nil {
+		t.Fatalf("UpdateDeal() error = %v", err)
+	}
+
+	stored, err := client.GetDealByID(ctx, id)
+	if err != nil || stored == nil {
+		t.Fatalf("GetDealByID() = %v, %v", stored, err)
+	}
+	if stored.Title != "Rescraped" || !stored.FirstSeen.Equal(firstSeen) {
+		t.Fatalf("stored deal = %q first seen %v, want the new title and FirstSeen %v", stored.Title, stored.FirstSeen, firstSeen)
+	}
+}
+
+func TestPostgresRunSummariesIntegration(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	ctx := context.Background()
+	client, err := NewPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() error = %v", err)
+	}
+	defer client.Close()
+
+	collection := fmt.Sprintf("test_runs_%d", time.Now().UnixNano())
+	defer func() { _, _ = client.DeleteCollection(ctx, collection) }()
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		run := models.RunSummary{StartedAt: base.Add(time.Duration(i) * 5 * time.Minute), DurationMs: 1500, Status: "ok", ScrapeOK: true, Scraped: 30, New: i}
+		if err := client.appendRunSummary(ctx, collection, run, 3); err != nil {
+			t.Fatalf("appendRunSummary(%d) error = %v", i, err)
+		}
+	}
+
+	runs, err := client.recentRunSummaries(ctx, collection, 10)
+	if err != nil {
+		t.Fatalf("recentRunSummaries() error = %v", err)
+	}
+	var news []int
+	for _, run := range runs {
+		news = append(news, run.New)
+	}
+	if want := []int{3, 2, 1}; !slices.Equal(news, want) {
+		t.Fatalf("runs by New = %v, want the 3 newest %v, newest first", news, want)
+	}
+	want := models.RunSummary{StartedAt: base.Add(15 * time.Minute), DurationMs: 1500, Status: "ok", ScrapeOK: true, Scraped: 30, New: 3}
+	if got := runs[0]; !got.StartedAt.Equal(want.StartedAt) || got.DurationMs != want.DurationMs || got.Status != want.Status || !got.ScrapeOK || got.Scraped != want.Scraped {
+		t.Fatalf("newest run = %+v, want %+v", got, want)
+	}
+
+	runs, err = client.recentRunSummaries(ctx, collection, 1)
+	if err != nil || len(runs) != 1 || runs[0].New != 3 {
+		t.Fatalf("recentRunSummaries(limit 1) = %+v, %v, want only the newest run", runs, err)
+	}
+}
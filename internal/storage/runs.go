@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+const runsCollection = "runs"
+
+// AppendRunSummary stores one RFD run and trims the runs collection to its
+// keepMax newest entries.
+func (c *Client) AppendRunSummary(ctx context.Context, run models.RunSummary, keepMax int) error {
+	return c.appendRunSummary(ctx, runsCollection, run, keepMax)
+}
+
+// GetRecentRunSummaries returns up to limit stored runs, newest first.
+func (c *Client) GetRecentRunSummaries(ctx context.Context, limit int) ([]models.RunSummary, error) {
+	return c.recentRunSummaries(ctx, runsCollection, limit)
+}
+
+func (c *Client) appendRunSummary(ctx context.Context, collection string, run models.RunSummary, keepMax int) error {
+	ctx, cancel := ensureDeadline(ctx, DefaultTimeout)
+	defer cancel()
+
+	id := run.StartedAt.UTC().Format("20060102T150405.000000000")
+	if err := c.SetDocument(ctx, collection, id, run); err != nil {
+		return fmt.Errorf("save run summary: %w", err)
+	}
+	if _, err := c.DeleteOldestDocuments(ctx, collection, "startedAt", keepMax); err != nil {
+		return fmt.Errorf("trim run summaries: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) recentRunSummaries(ctx context.Context, collection string, limit int) ([]models.RunSummary, error) {
+	ctx, cancel := ensureDeadline(ctx, DefaultTimeout)
+	defer cancel()
+
+	rows, err := c.ListDocuments(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	sortDocumentsByTime(rows, "startedAt", false)
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	runs := make([]models.RunSummary, 0, len(rows))
+	for _, row := range rows {
+		var run models.RunSummary
+		if err := decodeDocument(row.Data, &run); err != nil {
+			return nil, fmt.Errorf("decode run summary %s: %w", row.ID, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/logger"
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+// SQLiteStore persists RFD deals in a single SQLite file for self-hosting
+// without a Postgres server. Rows are keyed by the deterministic deal ID and
+// hold the same JSON document shape Client writes to Postgres.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLite opens (or creates) the SQLite database at path and ensures the schema.
+func NewSQLite(ctx context.Context, path string) (*SQLiteStore, error) {
+	ctx, cancel := ensureDeadline(ctx, DefaultTimeout)
+	defer cancel()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open sqlite: %w", err)
+	}
+	// SQLite serialises writers; a single connection avoids SQLITE_BUSY between goroutines.
+	db.SetMaxOpenConns(1)
+	store := &SQLiteStore{db: db}
+	if err := store.ensureSchema(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *SQLiteStore) ensureSchema(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS deals (
+	id TEXT PRIMARY KEY,
+	published_at INTEGER NOT NULL,
+	last_updated INTEGER NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS deals_published_idx ON deals (published_at DESC);
+CREATE INDEX IF NOT EXISTS deals_last_updated_idx ON deals (last_updated DESC);
+`)
+	if err != nil {
+		return fmt.Errorf("failed to initialize sqlite deal schema: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Backend() string {
+	return "sqlite"
+}
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func encodeSQLiteDeal(deal models.DealInfo) ([]byte, error) {
+	data, err := encodeDocument(prepareDealForStorage(deal))
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal deal %s: %w", deal.DocumentID, err)
+	}
+	return payload, nil
+}
+
+func decodeSQLiteDeal(id string, payload []byte) (*models.DealInfo, error) {
+	var data map[string]any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal deal %s: %w", id, err)
+	}
+	var deal models.DealInfo
+	if err := decodeDocument(data, &deal); err != nil {
+		return nil, fmt.Errorf("decode deal %s: %w", id, err)
+	}
+	deal.DocumentID = id
+	return &deal, nil
+}
+
+func createSQLiteDeal(ctx context.Context, exec sqlExecer, deal models.DealInfo) error {
+	payload, err := encodeSQLiteDeal(prepareDealForCreate(deal))
+	if err != nil {
+		return err
+	}
+	res, err := exec.ExecContext(ctx, `
+INSERT INTO deals (id, published_at, last_updated, data)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (id) DO NOTHING`,
+		deal.DocumentID, deal.PublishedTimestamp.UnixNano(), deal.LastUpdated.UnixNano(), string(payload))
+	if err != nil {
+		return fmt.Errorf("create deal %s: %w", deal.DocumentID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return models.ErrDealExists
+	}
+	return nil
+}
+
+// sqlitePreservedData builds an expression that is incoming with each key in
+// preserve replaced by its stored value, when the stored document has one.
+// Keys come from creationOnlyDealFields, never from user input.
+func sqlitePreservedData(incoming, stored string, preserve []string) string {
+	expr := incoming
+	for _, key := range preserve {
+		path := "'$." + key + "'"
+		expr = fmt.Sprintf("CASE WHEN json_type(%[2]s, %[3]s) IS NULL THEN %[1]s ELSE json_set(%[1]s, %[3]s, json_extract(%[2]s, %[3]s)) END", expr, stored, path)
+	}
+	return expr
+}
+
+func upsertSQLiteDeal(ctx context.Context, exec sqlExecer, deal models.DealInfo) error {
+	payload, err := encodeSQLiteDeal(deal)
+	if err != nil {
+		return err
+	}
+	_, err = exec.ExecContext(ctx, `
+INSERT INTO deals (id, published_at, last_updated, data)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	published_at = excluded.published_at,
+	last_updated = excluded.last_updated,
+	data = `+sqlitePreservedData("excluded.data", "deals.data", creationOnlyDealFields),
+		deal.DocumentID, deal.PublishedTimestamp.UnixNano(), deal.LastUpdated.UnixNano(), string(payload))
+	if err != nil {
+		return fmt.Errorf("update deal %s: %w", deal.DocumentID, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetDealByID(ctx context.Context, id string) (*models.DealInfo, error) {
+	var payload []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM deals WHERE id = ?`, id).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get deal %s: %w", id, err)
+	}
+	return decodeSQLiteDeal(id, payload)
+}
+
+func (s *SQLiteStore) GetDealsByIDs(ctx context.Context, ids []string) (map[string]*models.DealInfo, error) {
+	result := make(map[string]*models.DealInfo, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id, data FROM deals WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get deals by ids: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, fmt.Errorf("scan deal: %w", err)
+		}
+		deal, err := decodeSQLiteDeal(id, payload)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = deal
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteStore) GetRecentDeals(ctx context.Context, d time.Duration) ([]models.DealInfo, error) {
+	since := time.Now().Add(-d).UnixNano()
+	rows, err := s.db.QueryContext(ctx, `SELECT id, data FROM deals WHERE published_at >= ? ORDER BY published_at DESC`, since)
+	if err != nil {
+		return nil, fmt.Errorf("list recent deals: %w", err)
+	}
+	defer rows.Close()
+	var deals []models.DealInfo
+	for rows.Next() {
+		var id string
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, fmt.Errorf("scan deal: %w", err)
+		}
+		deal, err := decodeSQLiteDeal(id, payload)
+		if err != nil {
+			slog.Warn("Failed to decode recent deal", "id", id, "error", err)
+			continue
+		}
+		deals = append(deals, *deal)
+	}
+	return deals, rows.Err()
+}
+
+func (s *SQLiteStore) TryCreateDeal(ctx context.Context, deal models.DealInfo) error {
+	return createSQLiteDeal(ctx, s.db, deal)
+}
+
+func (s *SQLiteStore) UpdateDeal(ctx context.Context, deal models.DealInfo) error {
+	return upsertSQLiteDeal(ctx, s.db, deal)
+}
+
+// TrimOldDeals keeps the maxDeals most recently updated deals, matching Client.
+func (s *SQLiteStore) TrimOldDeals(ctx context.Context, maxDeals int) error {
+	if maxDeals < 0 {
+		return nil
+	}
+	res, err := s.db.ExecContext(ctx, `
+DELETE FROM deals WHERE id NOT IN (
+	SELECT id FROM deals ORDER BY last_updated DESC LIMIT ?
+)`, maxDeals)
+	if err != nil {
+		return fmt.Errorf("trim deals: %w", err)
+	}
+	if deleted, err := res.RowsAffected(); err == nil && deleted > 0 {
+		logger.Notice("TrimOldDeals: deleted old rows", "deleted", deleted)
+	}
+	return nil
+}
+
+// PurgeAll deletes every deal and returns how many were removed.
+func (s *SQLiteStore) PurgeAll(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM deals`)
+	if err != nil {
+		return 0, fmt.Errorf("purge deals: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge deals: %w", err)
+	}
+	logger.Notice("PurgeAll: deleted all deals", "deleted", deleted)
+	return int(deleted), nil
+}
+
+// BatchWrite applies creates and updates in one transaction. Duplicate creates
+// are reported but do not roll back the rest of the batch, matching Client.
+func (s *SQLiteStore) BatchWrite(ctx context.Context, creates []models.DealInfo, updates []models.DealInfo) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin batch: %w", err)
+	}
+	var errs []error
+	for _, d := range creates {
+		if err := createSQLiteDeal(ctx, tx, d); err != nil {
+			errs = append(errs, fmt.Errorf("create %s: %w", d.DocumentID, err))
+		}
+	}
+	for _, d := range updates {
+		if err := upsertSQLiteDeal(ctx, tx, d); err != nil {
+			errs = append(errs, fmt.Errorf("update %s: %w", d.DocumentID, err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		errs = append(errs, fmt.Errorf("commit batch: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	ctx, cancel := ensureDeadline(ctx, DefaultTimeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+// GetAllSubscriptions returns no subscriptions: SQLiteStore only persists deals,
+// and cmd/server reads subscriptions from the primary store.
+func (s *SQLiteStore) GetAllSubscriptions(ctx context.Context) ([]models.Subscription, error) {
+	return nil, nil
+}
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLite(context.Background(), filepath.Join(t.TempDir(), "deals.db"))
+	if err != nil {
+		t.Fatalf("NewSQLite() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteStoreCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	deal := models.DealInfo{
+		DocumentID:         "deal-1",
+		Title:              "Test Deal",
+		PostURL:            "https://forums.redflagdeals.com/test-1/",
+		PublishedTimestamp: now,
+		LastUpdated:        now,
+		DiscordMessageIDs:  map[string]string{"chan": "msg"},
+		Threads:            []models.ThreadContext{{PostURL: "https://forums.redflagdeals.com/test-1/", LikeCount: 5}},
+	}
+	if err := store.TryCreateDeal(ctx, deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	if err := store.TryCreateDeal(ctx, deal); !errors.Is(err, models.ErrDealExists) {
+		t.Fatalf("TryCreateDeal() duplicate error = %v, want ErrDealExists", err)
+	}
+
+	got, err := store.GetDealByID(ctx, "deal-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetDealByID() = %v, %v", got, err)
+	}
+	if got.Title != "Test Deal" || got.DiscordMessageIDs["chan"] != "msg" || len(got.Threads) != 1 || got.Threads[0].LikeCount != 5 {
+		t.Fatalf("GetDealByID() = %+v", got)
+	}
+	if !got.PublishedTimestamp.Equal(now) {
+		t.Fatalf("PublishedTimestamp = %v, want %v", got.PublishedTimestamp, now)
+	}
+
+	deal.Title = "Updated Deal"
+	if err := store.UpdateDeal(ctx, deal); err != nil {
+		t.Fatalf("UpdateDeal() error = %v", err)
+	}
+	byID, err := store.GetDealsByIDs(ctx, []string{"deal-1", "missing"})
+	if err != nil {
+		t.Fatalf("GetDealsByIDs() error = %v", err)
+	}
+	if len(byID) != 1 || byID["deal-1"].Title != "Updated Deal" {
+		t.Fatalf("GetDealsByIDs() = %+v", byID)
+	}
+
+	if missing, err := store.GetDealByID(ctx, "missing"); missing != nil || err != nil {
+		t.Fatalf("GetDealByID(missing) = %v, %v, want nil, nil", missing, err)
+	}
+}
+
+// TestSQLiteStoreUpdateRoundTripsEveryDealField fills every DealInfo field,
+// so a newly added field that the docstore encoding drops fails here instead
+// of silently not persisting.
+func TestSQLiteStoreUpdateRoundTripsEveryDealField(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// FirstSeen is creation-only, so it must match what the update sends.
+	base := models.DealInfo{DocumentID: "deal-1", Title: "Base", PostURL: "https://forums.redflagdeals.com/base-1/", PublishedTimestamp: now, FirstSeen: now}
+	if err := store.TryCreateDeal(ctx, base); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+
+	var want models.DealInfo
+	fillNonZero(t, reflect.ValueOf(&want).Elem(), now)
+	want.DocumentID = "deal-1"
+	if err := store.UpdateDeal(ctx, want); err != nil {
+		t.Fatalf("UpdateDeal() error = %v", err)
+	}
+
+	got, err := store.GetDealByID(ctx, "deal-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetDealByID() = %v, %v", got, err)
+	}
+	gotValue, wantValue := reflect.ValueOf(*got), reflect.ValueOf(want)
+	for i := 0; i < wantValue.NumField(); i++ {
+		name := wantValue.Type().Field(i).Name
+		if !reflect.DeepEqual(gotValue.Field(i).Interface(), wantValue.Field(i).Interface()) {
+			t.Errorf("%s did not round-trip: got %#v, want %#v", name, gotValue.Field(i).Interface(), wantValue.Field(i).Interface())
+		}
+	}
+}
+
+func TestUpdateDealPreservesFirstSeen(t *testing.T) {
+	stores := map[string]interface {
+		TryCreateDeal(context.Context, models.DealInfo) error
+		UpdateDeal(context.Context, models.DealInfo) error
+		BatchWrite(context.Context, []models.DealInfo, []models.DealInfo) error
+		GetDealByID(context.Context, string) (*models.DealInfo, error)
+	}{
+		"sqlite": newTestSQLiteStore(t),
+		"memory": NewMemoryStore(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			firstSeen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			deal := models.DealInfo{
+				DocumentID:         "deal-1",
+				Title:              "Original",
+				PostURL:            "https://forums.redflagdeals.com/deal-1/",
+				PublishedTimestamp: firstSeen,
+				FirstSeen:          firstSeen,
+			}
+			if err := store.TryCreateDeal(ctx, deal); err != nil {
+				t.Fatalf("TryCreateDeal() error = %v", err)
+			}
+
+			rescraped := deal
+			rescraped.Title = "Rescraped"
+			rescraped.FirstSeen = time.Time{}
+			if err := store.UpdateDeal(ctx, rescraped); err != nil {
+				t.Fatalf("UpdateDeal() error = %v", err)
+			}
+			rescraped.Title = "Batched"
+			rescraped.FirstSeen = firstSeen.Add(time.Hour)
+			if err := store.BatchWrite(ctx, nil, []models.DealInfo{rescraped}); err != nil {
+				t.Fatalf("BatchWrite() error = %v", err)
+			}
+
+			got, err := store.GetDealByID(ctx, "deal-1")
+			if err != nil || got == nil {
+				t.Fatalf("GetDealByID() = %v, %v", got, err)
+			}
+			if got.Title != "Batched" {
+				t.Errorf("Title = %q, want the update applied", got.Title)
+			}
+			if !got.FirstSeen.Equal(firstSeen) {
+				t.Errorf("FirstSeen = %v, want unchanged %v", got.FirstSeen, firstSeen)
+			}
+		})
+	}
+}
+
+func TestTryCreateDealStampsFirstSeen(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	before := time.Now()
+	deal := models.DealInfo{DocumentID: "deal-1", Title: "New", PostURL: "https://forums.redflagdeals.com/deal-1/", PublishedTimestamp: before}
+	if err := store.TryCreateDeal(ctx, deal); err != nil {
+		t.Fatalf("TryCreateDeal() error = %v", err)
+	}
+	got, err := store.GetDealByID(ctx, "deal-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetDealByID() = %v, %v", got, err)
+	}
+	if got.FirstSeen.Before(before.Add(-time.Second)) || got.FirstSeen.After(time.Now()) {
+		t.Errorf("FirstSeen = %v, want stamped at creation", got.FirstSeen)
+	}
+}
+
+// fillNonZero sets every exported field reachable from v to a non-zero value,
+// skipping fields opted out of storage with docstore:"-".
+func fillNonZero(t *testing.T, v reflect.Value, now time.Time) {
+	t.Helper()
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(now))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x-" + v.Type().Name())
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() && field.Tag.Get("docstore") != "-" {
+				fillNonZero(t, v.Field(i), now)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillNonZero(t, v.Index(0), now)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fillNonZero(t, key, now)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillNonZero(t, elem, now)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	default:
+		t.Fatalf("fillNonZero: unsupported kind %s (%s); extend the helper", v.Kind(), v.Type())
+	}
+}
+
+func TestSQLiteStoreBatchWriteAndRecent(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	now := time.Now()
+
+	creates := []models.DealInfo{
+		{DocumentID: "new", PublishedTimestamp: now.Add(-time.Hour)},
+		{DocumentID: "old", PublishedTimestamp: now.Add(-72 * time.Hour)},
+	}
+	updates := []models.DealInfo{{DocumentID: "newest", PublishedTimestamp: now}}
+	if err := store.BatchWrite(ctx, creates, updates); err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	if err := store.BatchWrite(ctx, creates[:1], nil); !errors.Is(err, models.ErrDealExists) {
+		t.Fatalf("BatchWrite() duplicate create error = %v, want ErrDealExists", err)
+	}
+
+	recent, err := store.GetRecentDeals(ctx, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentDeals() error = %v", err)
+	}
+	if len(recent) != 2 || recent[0].DocumentID != "newest" || recent[1].DocumentID != "new" {
+		t.Fatalf("GetRecentDeals() = %+v, want newest then new", recent)
+	}
+}
+
+func TestSQLiteStoreTrimOldDeals(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	now := time.Now()
+
+	for i, id := range []string{"a", "b", "c", "d"} {
+		deal := models.DealInfo{DocumentID: id, LastUpdated: now.Add(time.Duration(i) * time.Minute)}
+		if err := store.TryCreateDeal(ctx, deal); err != nil {
+			t.Fatalf("TryCreateDeal(%s) error = %v", id, err)
+		}
+	}
+
+	if err := store.TrimOldDeals(ctx, 2); err != nil {
+		t.Fatalf("TrimOldDeals() error = %v", err)
+	}
+	remaining, err := store.GetDealsByIDs(ctx, []string{"a", "b", "c", "d"})
+	if err != nil {
+		t.Fatalf("GetDealsByIDs() error = %v", err)
+	}
+	if len(remaining) != 2 || remaining["c"] == nil || remaining["d"] == nil {
+		t.Fatalf("TrimOldDeals() kept %v, want c and d", remaining)
+	}
+}
+
+func TestSQLiteStorePurgeAll(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.TryCreateDeal(ctx, models.DealInfo{DocumentID: id, LastUpdated: time.Now()}); err != nil {
+			t.Fatalf("TryCreateDeal(%s) error = %v", id, err)
+		}
+	}
+
+	deleted, err := store.PurgeAll(ctx)
+	if err != nil || deleted != 3 {
+		t.Fatalf("PurgeAll() = %d, %v, want 3, nil", deleted, err)
+	}
+	remaining, err := store.GetDealsByIDs(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("GetDealsByIDs() error = %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("GetDealsByIDs() after purge = %v, want none", remaining)
+	}
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -10,10 +11,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/pauljones0/rfd-discord-bot/internal/logger"
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
+	"github.com/pauljones0/rfd-discord-bot/internal/util"
 )
 
 const dealsCollection = "deals"
@@ -21,15 +25,55 @@ const dealsCollection = "deals"
 // DefaultTimeout is the default duration for storage operations if the context has no deadline.
 const DefaultTimeout = 30 * time.Second
 
+const (
+	// trimTimeout bounds TrimOldDeals, retries included.
+	trimTimeout    = 2 * time.Minute
+	trimMaxRetries = 2
+)
+
+// modifyDealMaxAttempts bounds how often ModifyDeal re-reads a deal that keeps
+// changing underneath it.
+const modifyDealMaxAttempts = 5
+
+// defaultDeleteBatchSize bounds how many deals one trim or purge statement
+// deletes unless SetDeleteBatchSize says otherwise.
+const defaultDeleteBatchSize = 500
+
+// pgPool is the subset of *pgxpool.Pool the Client uses, so unit tests can
+// fake database results (missing rows, conflicting inserts) without Postgres.
+type pgPool interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
 type Client struct {
-	pg *pgxpool.Pool
+	pg              pgPool
+	deleteBatchSize int
 }
 
+// creationOnlyDealFields are deal document keys written on create and kept
+// by every update, so a freshly scraped deal that leaves them zero can't
+// overwrite them.
+var creationOnlyDealFields = []string{"firstSeen"}
+
 func prepareDealForStorage(deal models.DealInfo) models.DealInfo {
 	deal.ExpiresAt = deal.ExpiryTime()
 	return deal
 }
 
+// prepareDealForCreate also stamps FirstSeen when the caller didn't.
+func prepareDealForCreate(deal models.DealInfo) models.DealInfo {
+	if deal.FirstSeen.IsZero() {
+		deal.FirstSeen = time.Now().UTC()
+	}
+	return prepareDealForStorage(deal)
+}
+
 // ensureDeadline returns a context with a deadline if one isn't already set.
 // The caller must defer the returned cancel function.
 func ensureDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
@@ -55,7 +99,7 @@ func NewPostgres(ctx context.Context, databaseURL string) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("pgxpool.New: %w", err)
 	}
-	client := &Client{pg: pool}
+	client := newClient(pool)
 	if err := client.ensurePostgresSchema(ctx); err != nil {
 		pool.Close()
 		return nil, err
@@ -63,6 +107,10 @@ func NewPostgres(ctx context.Context, databaseURL string) (*Client, error) {
 	return client, nil
 }
 
+func newClient(pool pgPool) *Client {
+	return &Client{pg: pool, deleteBatchSize: defaultDeleteBatchSize}
+}
+
 func (c *Client) Close() error {
 	if c == nil || c.pg == nil {
 		return nil
@@ -71,6 +119,12 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// SetDeleteBatchSize sets how many deals TrimOldDeals and PurgeAll delete per
+// statement. 0 deletes them all in one statement.
+func (c *Client) SetDeleteBatchSize(n int) {
+	c.deleteBatchSize = max(n, 0)
+}
+
 func (c *Client) Backend() string {
 	return "postgres"
 }
@@ -103,7 +157,7 @@ func (c *Client) GetDealsByIDs(ctx context.Context, ids []string) (map[string]*m
 }
 
 func (c *Client) TryCreateDeal(ctx context.Context, deal models.DealInfo) error {
-	deal = prepareDealForStorage(deal)
+	deal = prepareDealForCreate(deal)
 	if err := c.CreateDocument(ctx, dealsCollection, deal.DocumentID, deal); err != nil {
 		if errors.Is(err, errDocumentExists) {
 			return models.ErrDealExists
@@ -113,13 +167,63 @@ func (c *Client) TryCreateDeal(ctx context.Context, deal models.DealInfo) error
 	return nil
 }
 
+// UpdateDeal upserts the deal, keeping the stored creationOnlyDealFields.
 func (c *Client) UpdateDeal(ctx context.Context, deal models.DealInfo) error {
 	deal = prepareDealForStorage(deal)
-	return c.SetDocument(ctx, dealsCollection, deal.DocumentID, deal)
+	data, err := encodeDocument(deal)
+	if err != nil {
+		return err
+	}
+	return c.SetRawDocumentPreserving(ctx, dealsCollection, deal.DocumentID, data, creationOnlyDealFields)
+}
+
+// ModifyDeal applies mutate to the stored deal and saves it only if the
+// document hasn't changed since it was read; on a conflict it re-reads and
+// retries, so a concurrent writer's fields (e.g. freshly saved Discord message
+// IDs) aren't overwritten with stale data. It returns nil if the deal doesn't
+// exist.
+func (c *Client) ModifyDeal(ctx context.Context, id string, mutate func(*models.DealInfo)) (*models.DealInfo, error) {
+	for attempt := 1; ; attempt++ {
+		doc, ok, err := c.GetRawDocument(ctx, dealsCollection, id)
+		if err != nil || !ok {
+			return nil, err
+		}
+		var deal models.DealInfo
+		if err := decodeDocument(doc.Data, &deal); err != nil {
+			return nil, fmt.Errorf("decode deal %s: %w", id, err)
+		}
+		deal.DocumentID = id
+		mutate(&deal)
+
+		data, err := encodeDocument(prepareDealForStorage(deal))
+		if err != nil {
+			return nil, err
+		}
+		err = c.UpdateRawDocumentIfUnchanged(ctx, dealsCollection, id, data, doc.UpdatedAt)
+		if err == nil {
+			return &deal, nil
+		}
+		if !errors.Is(err, errPreconditionFailed) || attempt >= modifyDealMaxAttempts {
+			return nil, fmt.Errorf("modify deal %s: %w", id, err)
+		}
+		slog.Warn("Deal changed during update, retrying with fresh data", "id", id, "attempt", attempt)
+	}
 }
 
 func (c *Client) TrimOldDeals(ctx context.Context, maxDeals int) error {
-	deleted, err := c.DeleteOldestDocuments(ctx, dealsCollection, "lastUpdated", maxDeals)
+	ctx, cancel := ensureDeadline(ctx, trimTimeout)
+	defer cancel()
+
+	deleted, err := retryTrim(ctx, func() (int, error) {
+		ids, err := c.oldestDocumentIDs(ctx, dealsCollection, "lastUpdated", maxDeals)
+		if err != nil {
+			return 0, err
+		}
+		deleted, err := deleteInBatches(ctx, ids, c.deleteBatchSize, func(ctx context.Context, batch []string) (int64, error) {
+			return c.DeleteDocuments(ctx, dealsCollection, batch)
+		})
+		return int(deleted), err
+	})
 	if err != nil {
 		return err
 	}
@@ -129,23 +233,161 @@ func (c *Client) TrimOldDeals(ctx context.Context, maxDeals int) error {
 	return nil
 }
 
+// PurgeAll deletes every stored deal and returns how many were removed. It is
+// meant for re-bootstrapping after a selector change leaves stored data unusable.
+func (c *Client) PurgeAll(ctx context.Context) (int, error) {
+	var deleted int64
+	var err error
+	if c.deleteBatchSize > 0 {
+		var ids []string
+		if ids, err = c.ListDocumentIDs(ctx, dealsCollection); err == nil {
+			deleted, err = deleteInBatches(ctx, ids, c.deleteBatchSize, func(ctx context.Context, batch []string) (int64, error) {
+				return c.DeleteDocuments(ctx, dealsCollection, batch)
+			})
+		}
+	} else {
+		deleted, err = c.DeleteCollection(ctx, dealsCollection)
+	}
+	if err != nil {
+		return int(deleted), err
+	}
+	logger.Notice("PurgeAll: deleted all deals", "deleted", deleted)
+	return int(deleted), nil
+}
+
+// deleteInBatches deletes ids in chunks of batchSize (all at once when
+// batchSize is 0) so a large trim never sends thousands of IDs in one
+// statement. It stops at the first failed chunk and returns what was deleted.
+func deleteInBatches(ctx context.Context, ids []string, batchSize int, deleteBatch func(context.Context, []string) (int64, error)) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = len(ids)
+	}
+	var deleted int64
+	for start := 0; start < len(ids); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		n, err := deleteBatch(ctx, ids[start:min(start+batchSize, len(ids))])
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// retryTrim retries a trim pass so a transient database error doesn't skip
+// cleanup for the whole run. Trimming is idempotent: each pass re-reads the
+// rows before deleting the oldest ones.
+func retryTrim(ctx context.Context, trim func() (int, error)) (int, error) {
+	var deleted int
+	err := util.RetryWithBackoff(ctx, trimMaxRetries, func(attempt int) error {
+		if attempt > 0 {
+			slog.Warn("Retrying TrimOldDeals", "attempt", attempt)
+		}
+		var trimErr error
+		deleted, trimErr = trim()
+		return trimErr
+	})
+	return deleted, err
+}
+
+// BatchWrite saves a run's new and changed deals in one round trip: creates
+// as TryCreateDeal does and updates as UpdateDeal does, queued in a single
+// pgx batch. pgx runs a batch in one implicit transaction, so a failed
+// statement rolls back the whole batch; BatchWrite then retries each write on
+// its own, so only the writes that fail again are lost. Every failed write is
+// reported in the joined error.
 func (c *Client) BatchWrite(ctx context.Context, creates []models.DealInfo, updates []models.DealInfo) error {
+	if len(creates) == 0 && len(updates) == 0 {
+		return nil
+	}
 	var errs []error
+	var ops []dealWrite
 	for _, d := range creates {
-		d = prepareDealForStorage(d)
-		if err := c.CreateDocument(ctx, dealsCollection, d.DocumentID, d); err != nil {
+		payload, err := dealPayload(prepareDealForCreate(d))
+		if err != nil {
 			errs = append(errs, fmt.Errorf("create %s: %w", d.DocumentID, err))
+			continue
 		}
+		ops = append(ops, dealWrite{label: "create " + d.DocumentID, create: true, sql: createDocumentSQL, args: []any{dealsCollection, d.DocumentID, payload}})
 	}
 	for _, d := range updates {
-		d = prepareDealForStorage(d)
-		if err := c.SetDocument(ctx, dealsCollection, d.DocumentID, d); err != nil {
+		payload, err := dealPayload(prepareDealForStorage(d))
+		if err != nil {
 			errs = append(errs, fmt.Errorf("update %s: %w", d.DocumentID, err))
+			continue
+		}
+		ops = append(ops, dealWrite{label: "update " + d.DocumentID, sql: setDocumentPreservingSQL, args: []any{dealsCollection, d.DocumentID, payload, creationOnlyDealFields}})
+	}
+	if len(ops) == 0 {
+		return errors.Join(errs...)
+	}
+
+	batch := &pgx.Batch{}
+	for _, op := range ops {
+		batch.Queue(op.sql, op.args...)
+	}
+	results := c.pg.SendBatch(ctx, batch)
+	tags := make([]pgconn.CommandTag, 0, len(ops))
+	var batchErr error
+	for range ops {
+		tag, err := results.Exec()
+		if err != nil {
+			batchErr = err
+			break
+		}
+		tags = append(tags, tag)
+	}
+	if err := results.Close(); err != nil && batchErr == nil {
+		batchErr = err
+	}
+	if batchErr != nil {
+		slog.Warn("Batch write rolled back, retrying each write on its own", "writes", len(ops), "error", batchErr)
+		for _, op := range ops {
+			tag, err := c.pg.Exec(ctx, op.sql, op.args...)
+			if err = op.check(tag, err); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for i, op := range ops {
+		if err := op.check(tags[i], nil); err != nil {
+			errs = append(errs, err)
 		}
 	}
 	return errors.Join(errs...)
 }
 
+// dealWrite is one statement of a BatchWrite.
+type dealWrite struct {
+	label  string
+	create bool
+	sql    string
+	args   []any
+}
+
+// check turns a write's result into its error, reporting a create that found
+// the ID taken as errDocumentExists.
+func (w dealWrite) check(tag pgconn.CommandTag, err error) error {
+	if err == nil && w.create && tag.RowsAffected() == 0 {
+		err = errDocumentExists
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.label, err)
+	}
+	return nil
+}
+
+func dealPayload(deal models.DealInfo) ([]byte, error) {
+	data, err := encodeDocument(deal)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
 func (c *Client) Ping(ctx context.Context) error {
 	ctx, cancel := ensureDeadline(ctx, DefaultTimeout)
 	defer cancel()
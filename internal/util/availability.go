@@ -0,0 +1,113 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultYMMVPatterns flag deals whose availability varies by store or
+// account.
+var DefaultYMMVPatterns = []string{
+	`(?i)\bymmv\b`,
+	`(?i)\byour mileage may vary\b`,
+	`(?i)\bselect (?:stores|locations)\b`,
+}
+
+// DefaultRegionPatterns capture a province hint such as "[ON]" or "Quebec
+// only". The first capture group is the region.
+var DefaultRegionPatterns = []string{
+	`(?i)[\[(](ON|QC|BC|AB|SK|MB|NS|NB|NL|PE|PEI|YT|NT|NU)[\])]`,
+	`(?i)\b(ontario|quebec|british columbia|alberta|saskatchewan|manitoba|nova scotia|new brunswick|newfoundland|prince edward island)\s+only\b`,
+}
+
+// provinceCodes maps province names captured by region patterns to the
+// codes RFD posters use in brackets.
+var provinceCodes = map[string]string{
+	"ontario":              "ON",
+	"quebec":               "QC",
+	"british columbia":     "BC",
+	"alberta":              "AB",
+	"saskatchewan":         "SK",
+	"manitoba":             "MB",
+	"nova scotia":          "NS",
+	"new brunswick":        "NB",
+	"newfoundland":         "NL",
+	"prince edward island": "PE",
+	"pei":                  "PE",
+}
+
+// AvailabilityDetector matches text against configurable YMMV and region
+// patterns.
+type AvailabilityDetector struct {
+	ymmv    []*regexp.Regexp
+	regions []*regexp.Regexp
+}
+
+// NewAvailabilityDetector compiles both pattern lists. Region patterns
+// without a capture group report the whole match.
+func NewAvailabilityDetector(ymmvPatterns, regionPatterns []string) (*AvailabilityDetector, error) {
+	ymmv, err := compilePatterns("YMMV", ymmvPatterns)
+	if err != nil {
+		return nil, err
+	}
+	regions, err := compilePatterns("region", regionPatterns)
+	if err != nil {
+		return nil, err
+	}
+	return &AvailabilityDetector{ymmv: ymmv, regions: regions}, nil
+}
+
+func compilePatterns(kind string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", kind, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Detect reports whether any of texts (e.g. title and description) marks the
+// deal YMMV, and the first region hint found. Known province names are
+// returned as their two-letter codes.
+func (d *AvailabilityDetector) Detect(texts ...string) (ymmv bool, region string) {
+	if d == nil {
+		return false, ""
+	}
+	for _, text := range texts {
+		if !ymmv {
+			for _, re := range d.ymmv {
+				if re.MatchString(text) {
+					ymmv = true
+					break
+				}
+			}
+		}
+		if region == "" {
+			for _, re := range d.regions {
+				match := re.FindStringSubmatch(text)
+				if match == nil {
+					continue
+				}
+				hint := match[0]
+				if len(match) > 1 && match[1] != "" {
+					hint = match[1]
+				}
+				region = normalizeRegion(hint)
+				break
+			}
+		}
+	}
+	return ymmv, region
+}
+
+func normalizeRegion(hint string) string {
+	hint = strings.Join(strings.Fields(strings.Trim(hint, "[]() ")), " ")
+	if code, ok := provinceCodes[strings.ToLower(hint)]; ok {
+		return code
+	}
+	return strings.ToUpper(hint)
+}
@@ -0,0 +1,53 @@
+package util
+
+import "testing"
+
+func TestAvailabilityDetector_Defaults(t *testing.T) {
+	d, err := NewAvailabilityDetector(DefaultYMMVPatterns, DefaultRegionPatterns)
+	if err != nil {
+		t.Fatalf("NewAvailabilityDetector() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		title       string
+		description string
+		wantYMMV    bool
+		wantRegion  string
+	}{
+		{name: "ymmv in title", title: "[Walmart] YMMV Clearance LEGO sets 50% off", wantYMMV: true},
+		{name: "ymmv in description", title: "Costco Dyson V15 $499", description: "Your mileage may vary, select stores only", wantYMMV: true},
+		{name: "ontario marker", title: "[ON] Free Slurpee at 7-Eleven", wantRegion: "ON"},
+		{name: "quebec marker lowercase", title: "Metro [qc] Coffee $5", wantRegion: "QC"},
+		{name: "ymmv and region", title: "(BC) YMMV Tires $50 off", wantYMMV: true, wantRegion: "BC"},
+		{name: "province name only", title: "Beer Store", description: "Ontario only, in store", wantRegion: "ON"},
+		{name: "ordinary deal", title: "[Amazon] Echo Dot $29", description: "Ships anywhere in Canada"},
+		{name: "store prefix is not a region", title: "[Best Buy] TV $299"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ymmv, region := d.Detect(tt.title, tt.description)
+			if ymmv != tt.wantYMMV || region != tt.wantRegion {
+				t.Errorf("Detect(%q, %q) = %v, %q, want %v, %q", tt.title, tt.description, ymmv, region, tt.wantYMMV, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestAvailabilityDetector_CustomPatterns(t *testing.T) {
+	d, err := NewAvailabilityDetector([]string{`(?i)\ben magasin seulement\b`}, []string{`(?i)\bGTA\b`})
+	if err != nil {
+		t.Fatalf("NewAvailabilityDetector() error = %v", err)
+	}
+	ymmv, region := d.Detect("Pizza deal GTA, en magasin seulement")
+	if !ymmv || region != "GTA" {
+		t.Errorf("Detect() = %v, %q, want true, %q", ymmv, region, "GTA")
+	}
+	if ymmv, region := d.Detect("[ON] YMMV"); ymmv || region != "" {
+		t.Errorf("custom patterns should replace the defaults, got %v, %q", ymmv, region)
+	}
+
+	if _, err := NewAvailabilityDetector(nil, []string{`(`}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+}
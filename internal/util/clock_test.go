@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+	clock.Advance(90 * time.Second)
+	if got := clock.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Fatalf("Now() after Advance = %v, want %v", got, start.Add(90*time.Second))
+	}
+	clock.Set(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("Now() after Set = %v, want %v", got, start)
+	}
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostLimiter paces outbound requests per host so no single site receives
+// more than the configured requests per second. A nil *HostLimiter is valid
+// and never waits.
+type HostLimiter struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	limiters map[string]*rate.Limiter
+}
+
+// NewHostLimiter returns a limiter allowing perSecond requests to each host,
+// or nil (unlimited) when perSecond is not positive.
+func NewHostLimiter(perSecond int) *HostLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &HostLimiter{
+		limit:    rate.Limit(perSecond),
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Wait blocks until a request to host is allowed or ctx is done.
+func (h *HostLimiter) Wait(ctx context.Context, host string) error {
+	if h == nil {
+		return nil
+	}
+	return h.forHost(host).Wait(ctx)
+}
+
+func (h *HostLimiter) forHost(host string) *rate.Limiter {
+	host = strings.ToLower(host)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l, ok := h.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(h.limit, 1)
+		h.limiters[host] = l
+	}
+	return l
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter_PacesSameHost(t *testing.T) {
+	h := NewHostLimiter(20) // one request every 50ms per host
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := h.Wait(ctx, "www.walmart.ca"); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests to one host took %s, want them paced ~50ms apart", elapsed)
+	}
+
+	start = time.Now()
+	for _, host := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		if err := h.Wait(ctx, host); err != nil {
+			t.Fatalf("Wait(%s) error = %v", host, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("first requests to distinct hosts took %s, want no pacing", elapsed)
+	}
+}
+
+func TestHostLimiter_NilAndCancelled(t *testing.T) {
+	h := NewHostLimiter(0)
+	if h != nil {
+		t.Fatal("NewHostLimiter(0) should disable pacing")
+	}
+	if err := h.Wait(context.Background(), "example.com"); err != nil {
+		t.Errorf("nil limiter Wait() error = %v", err)
+	}
+
+	h = NewHostLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = h.Wait(ctx, "example.com")
+	cancel()
+	if err := h.Wait(ctx, "example.com"); err == nil {
+		t.Error("Wait() on cancelled context should fail")
+	}
+}
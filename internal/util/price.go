@@ -0,0 +1,33 @@
+package util
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var priceAmountRegex = regexp.MustCompile(`\d[\d,]*(?:\.\d+)?`)
+
+// ParsePriceCents extracts the first amount from a display price such as
+// "$1,299.99" or "CAD 45" and returns it in cents.
+func ParsePriceCents(s string) (int64, bool) {
+	match := priceAmountRegex.FindString(s)
+	if match == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(math.Round(value * 100)), true
+}
+
+// PercentOff returns the whole-number discount of current relative to original.
+// It returns 0 when there is no usable original price or no discount.
+func PercentOff(current, original int64) int {
+	if original <= 0 || current < 0 || current >= original {
+		return 0
+	}
+	return int(math.Round(float64(original-current) * 100 / float64(original)))
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultPriceErrorPatterns flag deals that posters or commenters call a
+// pricing mistake.
+var DefaultPriceErrorPatterns = []string{
+	`(?i)\bpric(?:e|ing)\s*(?:error|mistake|glitch)\b`,
+	`(?i)\bglitch\b`,
+	`(?i)\bymmv\b[^.!?\n]{0,30}\bmistake\b`,
+}
+
+// PriceErrorDetector matches text against configurable price-error patterns.
+type PriceErrorDetector struct {
+	patterns []*regexp.Regexp
+}
+
+// NewPriceErrorDetector compiles patterns; any single match flags the text.
+func NewPriceErrorDetector(patterns []string) (*PriceErrorDetector, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price error pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &PriceErrorDetector{patterns: compiled}, nil
+}
+
+// Detect reports whether any of texts (e.g. title and comments) looks like a
+// price error.
+func (d *PriceErrorDetector) Detect(texts ...string) bool {
+	if d == nil {
+		return false
+	}
+	for _, text := range texts {
+		for _, re := range d.patterns {
+			if re.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
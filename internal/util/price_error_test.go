@@ -0,0 +1,49 @@
+package util
+
+import "testing"
+
+func TestPriceErrorDetector_Defaults(t *testing.T) {
+	d, err := NewPriceErrorDetector(DefaultPriceErrorPatterns)
+	if err != nil {
+		t.Fatalf("NewPriceErrorDetector() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		title    string
+		comments string
+		want     bool
+	}{
+		{name: "price error in title", title: "[Walmart] PRICE ERROR? 65\" TV $49", want: true},
+		{name: "pricing mistake in title", title: "Pricing mistake on AirPods", want: true},
+		{name: "glitch in comments", title: "LG OLED $199", comments: "Looks like a glitch, ordered 2", want: true},
+		{name: "ymmv mistake in comments", title: "Dyson V15 $150", comments: "YMMV, probably a mistake so order fast", want: true},
+		{name: "ordinary deal", title: "Costco Kirkland Olive Oil $15", comments: "Great price, stocked up", want: false},
+		{name: "glitched app is not a price glitch", title: "Free coffee", comments: "The app glitched on me", want: false},
+		{name: "unrelated mistake", title: "Keyboard $40", comments: "My mistake, it's the tenkeyless one", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.Detect(tt.title, tt.comments); got != tt.want {
+				t.Errorf("Detect(%q, %q) = %v, want %v", tt.title, tt.comments, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceErrorDetector_CustomPatterns(t *testing.T) {
+	d, err := NewPriceErrorDetector([]string{`(?i)\berreur de prix\b`})
+	if err != nil {
+		t.Fatalf("NewPriceErrorDetector() error = %v", err)
+	}
+	if !d.Detect("Erreur de prix chez Best Buy") {
+		t.Error("expected custom pattern to match")
+	}
+	if d.Detect("Price error on TVs") {
+		t.Error("custom patterns should replace the defaults")
+	}
+
+	if _, err := NewPriceErrorDetector([]string{`(`}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+}
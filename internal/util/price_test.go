@@ -0,0 +1,48 @@
+package util
+
+import "testing"
+
+func TestPercentOff(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int64
+		original int64
+		want     int
+	}{
+		{name: "normal discount", current: 7500, original: 10000, want: 25},
+		{name: "rounds to nearest percent", current: 1999, original: 2999, want: 33},
+		{name: "free", current: 0, original: 5000, want: 100},
+		{name: "zero original", current: 1000, original: 0, want: 0},
+		{name: "current above original", current: 12000, original: 10000, want: 0},
+		{name: "no change", current: 10000, original: 10000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PercentOff(tt.current, tt.original); got != tt.want {
+				t.Errorf("PercentOff(%d, %d) = %d, want %d", tt.current, tt.original, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePriceCents(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   int64
+		wantOK bool
+	}{
+		{input: "$1,299.99", want: 129999, wantOK: true},
+		{input: "CAD 45", want: 4500, wantOK: true},
+		{input: "$19.9", want: 1990, wantOK: true},
+		{input: "Free", want: 0, wantOK: false},
+		{input: "", want: 0, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParsePriceCents(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParsePriceCents(%q) = (%d, %v), want (%d, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
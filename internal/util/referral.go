@@ -10,9 +10,44 @@ const (
 	ebayCAAffiliateTracking = "?mkcid=1&mkrid=706-53473-19255-0&siteid=2&campid=5339131483&customid=&toolid=10001&mkevt=1"
 )
 
+// AffiliateConfig holds the affiliate identifiers applied by CleanReferralLink.
+type AffiliateConfig struct {
+	// AmazonTag is the fallback store ID for marketplaces without an AmazonTags entry.
+	AmazonTag string
+	// AmazonTags maps a marketplace host (e.g. "amazon.ca") to its store ID.
+	// Amazon tags are marketplace-specific, so a .com tag does not track on .ca.
+	AmazonTags map[string]string
+	// BestBuyPrefix is the affiliate redirect prefix for Best Buy links.
+	BestBuyPrefix string
+	// NoAffiliateDomains lists domains (subdomains included) whose links are
+	// unwrapped from referral redirects but never tagged or re-tagged.
+	NoAffiliateDomains []string
+}
+
+// skipsAffiliate reports whether host is on the NoAffiliateDomains list.
+func (a AffiliateConfig) skipsAffiliate(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	for _, domain := range a.NoAffiliateDomains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// amazonTagFor returns the store ID for the marketplace serving host.
+func (a AffiliateConfig) amazonTagFor(host string) string {
+	marketplace := strings.TrimPrefix(strings.ToLower(host), "www.")
+	marketplace = strings.TrimPrefix(marketplace, "smile.")
+	if tag, ok := a.AmazonTags[marketplace]; ok && tag != "" {
+		return tag
+	}
+	return a.AmazonTag
+}
+
 // CleanReferralLink processes deal URLs to strip/replace affiliate tracking.
-// bestBuyPrefix is the affiliate redirect prefix for Best Buy links.
-func CleanReferralLink(rawUrl string, amazonTag string, bestBuyPrefix string) (string, bool) {
+func CleanReferralLink(rawUrl string, affiliates AffiliateConfig) (string, bool) {
 	parsedUrl, err := url.Parse(rawUrl)
 	if err != nil {
 		return rawUrl, false
@@ -20,10 +55,10 @@ func CleanReferralLink(rawUrl string, amazonTag string, bestBuyPrefix string) (s
 
 	switch {
 	case parsedUrl.Host == "click.linksynergy.com":
-		return cleanDecodedReferralTarget(rawUrl, parsedUrl.Query().Get("murl"), amazonTag, bestBuyPrefix)
+		return cleanDecodedReferralTarget(rawUrl, parsedUrl.Query().Get("murl"), affiliates)
 
 	case parsedUrl.Host == "go.redirectingat.com":
-		return cleanDecodedReferralTarget(rawUrl, parsedUrl.Query().Get("url"), amazonTag, bestBuyPrefix)
+		return cleanDecodedReferralTarget(rawUrl, parsedUrl.Query().Get("url"), affiliates)
 
 	case parsedUrl.Host == "bestbuyca.o93x.net" && strings.HasPrefix(parsedUrl.Path, "/c/"):
 		// Swap to our Best Buy affiliate link, preserving the destination product URL.
@@ -31,16 +66,32 @@ func CleanReferralLink(rawUrl string, amazonTag string, bestBuyPrefix string) (s
 		if productURL == "" {
 			return rawUrl, false
 		}
-		cleanedURL := bestBuyPrefix + url.QueryEscape(productURL)
+		if target, err := url.Parse(productURL); err == nil && affiliates.skipsAffiliate(target.Hostname()) {
+			return productURL, true
+		}
+		cleanedURL := affiliates.BestBuyPrefix + url.QueryEscape(productURL)
 		return cleanedURL, true
 
+	case affiliates.skipsAffiliate(parsedUrl.Hostname()):
+		return rawUrl, false
+
 	case strings.HasSuffix(parsedUrl.Host, "bestbuy.ca"):
 		// Direct bestbuy.ca link - wrap it
-		cleanedURL := bestBuyPrefix + url.QueryEscape(rawUrl)
+		cleanedURL := affiliates.BestBuyPrefix + url.QueryEscape(rawUrl)
 		return cleanedURL, true
 
 	case strings.Contains(parsedUrl.Host, "amazon."):
+		amazonTag := affiliates.amazonTagFor(parsedUrl.Hostname())
 		queryParams := parsedUrl.Query()
+		if amazonTag == "" {
+			// No tag of our own: still drop a third party's.
+			if !queryParams.Has("tag") {
+				return rawUrl, false
+			}
+			queryParams.Del("tag")
+			parsedUrl.RawQuery = queryParams.Encode()
+			return parsedUrl.String(), true
+		}
 		originalTag := queryParams.Get("tag")
 		tagModified := false
 
@@ -69,7 +120,7 @@ func CleanReferralLink(rawUrl string, amazonTag string, bestBuyPrefix string) (s
 	}
 }
 
-func cleanDecodedReferralTarget(rawUrl string, encodedTarget string, amazonTag string, bestBuyPrefix string) (string, bool) {
+func cleanDecodedReferralTarget(rawUrl string, encodedTarget string, affiliates AffiliateConfig) (string, bool) {
 	if encodedTarget == "" {
 		return rawUrl, false
 	}
@@ -79,7 +130,7 @@ func cleanDecodedReferralTarget(rawUrl string, encodedTarget string, amazonTag s
 		return rawUrl, false
 	}
 
-	if cleanedURL, changed := CleanReferralLink(decodedTarget, amazonTag, bestBuyPrefix); changed {
+	if cleanedURL, changed := CleanReferralLink(decodedTarget, affiliates); changed {
 		return cleanedURL, true
 	}
 
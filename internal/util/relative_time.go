@@ -0,0 +1,120 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var relativeAgoRegex = regexp.MustCompile(`^(\d+|an?|one)\s+(sec|second|min|minute|hr|hour|day|week|month|year)s?\s+ago$`)
+
+// ordinalSuffixRegex matches the suffix in dates like "Jun 1st, 2025".
+var ordinalSuffixRegex = regexp.MustCompile(`(\d)(?:st|nd|rd|th)\b`)
+
+var relativeUnits = map[string]time.Duration{
+	"sec":    time.Second,
+	"second": time.Second,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"hr":     time.Hour,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// absoluteDateLayouts are the absolute forms RFD falls back to for older
+// posts, tried in order.
+var absoluteDateLayouts = []string{
+	"Jan 2, 2006 3:04 pm",
+	"January 2, 2006 3:04 pm",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2006-01-02",
+}
+
+// ParseRelativeTime parses the posted-time text RFD shows when there is no
+// machine-readable datetime: "just now", "5 minutes ago", "an hour ago",
+// "Yesterday", "Today at 3:04 PM" or an absolute "Jun 1, 2025". Relative forms
+// are measured back from now and rounded down to their unit, so a post parses
+// to the same time on every run while its text reads the same; dates are
+// read in now's location.
+func ParseRelativeTime(text string, now time.Time) (time.Time, bool) {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	text = strings.TrimPrefix(text, "posted ")
+	if text == "" {
+		return time.Time{}, false
+	}
+
+	switch text {
+	case "just now", "now", "moments ago", "a moment ago":
+		return roundDownTo(now, "minute"), true
+	}
+
+	if match := relativeAgoRegex.FindStringSubmatch(text); match != nil {
+		n := 1
+		if match[1] != "a" && match[1] != "an" && match[1] != "one" {
+			var err error
+			if n, err = strconv.Atoi(match[1]); err != nil {
+				return time.Time{}, false
+			}
+		}
+		switch match[2] {
+		case "month":
+			return roundDownTo(now.AddDate(0, -n, 0), "month"), true
+		case "year":
+			return roundDownTo(now.AddDate(-n, 0, 0), "year"), true
+		}
+		return roundDownTo(now.Add(-time.Duration(n)*relativeUnits[match[2]]), match[2]), true
+	}
+
+	for _, day := range []struct {
+		prefix string
+		offset int
+	}{{"today", 0}, {"yesterday", -1}} {
+		rest, ok := strings.CutPrefix(text, day.prefix)
+		if !ok {
+			continue
+		}
+		date := now.AddDate(0, 0, day.offset)
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), "at ")
+		if rest == "" {
+			return roundDownTo(date, "day"), true
+		}
+		clock, err := time.Parse("3:04 pm", rest)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), true
+	}
+
+	text = ordinalSuffixRegex.ReplaceAllString(text, "$1")
+	for _, layout := range absoluteDateLayouts {
+		if parsed, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// roundDownTo truncates t to the start of unit in t's location.
+func roundDownTo(t time.Time, unit string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch unit {
+	case "sec", "second":
+		return t.Truncate(time.Second)
+	case "min", "minute":
+		return t.Truncate(time.Minute)
+	case "hr", "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case "day":
+		return day
+	case "week":
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
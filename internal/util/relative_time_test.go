@@ -0,0 +1,66 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pauljones0/rfd-discord-bot/internal/models"
+)
+
+func TestParseRelativeTime(t *testing.T) {
+	toronto := time.FixedZone("EDT", -4*60*60)
+	now := time.Date(2025, 6, 10, 15, 30, 0, 0, toronto)
+
+	tests := []struct {
+		text   string
+		want   time.Time
+		wantOK bool
+	}{
+		{text: "just now", want: now, wantOK: true},
+		{text: "Moments ago", want: now, wantOK: true},
+		{text: "30 seconds ago", want: now.Add(-30 * time.Second), wantOK: true},
+		{text: "a minute ago", want: now.Add(-time.Minute), wantOK: true},
+		{text: "5 minutes ago", want: now.Add(-5 * time.Minute), wantOK: true},
+		{text: "5 mins ago", want: now.Add(-5 * time.Minute), wantOK: true},
+		{text: "an hour ago", want: time.Date(2025, 6, 10, 14, 0, 0, 0, toronto), wantOK: true},
+		{text: " 2  hours ago ", want: time.Date(2025, 6, 10, 13, 0, 0, 0, toronto), wantOK: true},
+		{text: "3 days ago", want: time.Date(2025, 6, 7, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "2 weeks ago", want: time.Date(2025, 5, 25, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "1 month ago", want: time.Date(2025, 5, 1, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "Yesterday", want: time.Date(2025, 6, 9, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "Yesterday at 9:15 PM", want: time.Date(2025, 6, 9, 21, 15, 0, 0, toronto), wantOK: true},
+		{text: "Today at 8:05 am", want: time.Date(2025, 6, 10, 8, 5, 0, 0, toronto), wantOK: true},
+		{text: "Jun 1, 2025", want: time.Date(2025, 6, 1, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "June 1st, 2025", want: time.Date(2025, 6, 1, 0, 0, 0, 0, toronto), wantOK: true},
+		{text: "Jun 1, 2025 12:00 pm", want: time.Date(2025, 6, 1, 12, 0, 0, 0, toronto), wantOK: true},
+		{text: "Posted 2 hours ago", want: time.Date(2025, 6, 10, 13, 0, 0, 0, toronto), wantOK: true},
+		{text: ""},
+		{text: "sometime soon"},
+		{text: "Yesterday at noon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseRelativeTime(tt.text, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("ParseRelativeTime(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseRelativeTime_StableAcrossRuns(t *testing.T) {
+	toronto := time.FixedZone("EDT", -4*60*60)
+	firstRun := time.Date(2025, 6, 10, 15, 30, 12, 0, toronto)
+	nextRun := firstRun.Add(time.Minute)
+
+	for _, text := range []string{"2 hours ago", "3 days ago", "Yesterday", "1 month ago"} {
+		first, ok1 := ParseRelativeTime(text, firstRun)
+		next, ok2 := ParseRelativeTime(text, nextRun)
+		if !ok1 || !ok2 {
+			t.Fatalf("ParseRelativeTime(%q) failed to parse", text)
+		}
+		if models.DealID(first) != models.DealID(next) {
+			t.Errorf("%q: runs a minute apart parsed %v and %v, want the same deal ID", text, first, next)
+		}
+	}
+}
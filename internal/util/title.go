@@ -0,0 +1,95 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTitleStripPatterns remove the noise RFD posters put in front of a
+// title: bracketed store prefixes ("[Amazon.ca]") and heat markers ("Lava Hot!").
+var DefaultTitleStripPatterns = []string{
+	`^\[[^\]]*\]`,
+	// Bare "Hot" needs punctuation so titles like "Hot Sauce" survive.
+	`(?i)^(?:lava\s+hot\b\s*!*|hot\s*(?:!+|[:\-–]))\s*[:\-–]?`,
+}
+
+var defaultTitleCleaner = mustTitleCleaner(DefaultTitleStripPatterns)
+
+// TitleCleaner strips configurable prefixes from deal titles without AI.
+type TitleCleaner struct {
+	patterns []*regexp.Regexp
+}
+
+// NewTitleCleaner compiles patterns; each is removed wherever it matches.
+// Anchor a pattern with ^ to strip it only as a prefix.
+func NewTitleCleaner(patterns []string) (*TitleCleaner, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title strip pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &TitleCleaner{patterns: compiled}, nil
+}
+
+func mustTitleCleaner(patterns []string) *TitleCleaner {
+	tc, err := NewTitleCleaner(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return tc
+}
+
+// Clean applies the patterns until the title stops changing, so stacked
+// prefixes like "[Costco] Hot! [In-Store]" are all removed, then collapses
+// whitespace. If everything would be stripped, the trimmed raw title is kept.
+func (tc *TitleCleaner) Clean(raw string) string {
+	title := strings.Join(strings.Fields(raw), " ")
+	for {
+		before := title
+		for _, re := range tc.patterns {
+			title = strings.TrimSpace(re.ReplaceAllString(title, ""))
+		}
+		if title == before {
+			break
+		}
+	}
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return strings.Join(strings.Fields(raw), " ")
+	}
+	return title
+}
+
+// CleanTitle strips RFD title noise using DefaultTitleStripPatterns.
+func CleanTitle(raw string) string {
+	return defaultTitleCleaner.Clean(raw)
+}
+
+var storePrefixRegex = regexp.MustCompile(`^\s*\[([^\]]*)\]\s*`)
+
+// ExtractStoreFromTitle returns the store named in a title's leading
+// "[Store]" token, or "" when the title has none. Only the first bracket is
+// the store; later ones ("[In-Store]", "[2TB]") describe the deal.
+func ExtractStoreFromTitle(title string) string {
+	matches := storePrefixRegex.FindStringSubmatch(title)
+	if len(matches) < 2 {
+		return ""
+	}
+	return strings.Join(strings.Fields(matches[1]), " ")
+}
+
+// StripStorePrefix removes the leading "[Store]" token from title. A title
+// that is nothing but the token is returned unchanged.
+func StripStorePrefix(title string) string {
+	if ExtractStoreFromTitle(title) == "" {
+		return title
+	}
+	if stripped := strings.TrimSpace(storePrefixRegex.ReplaceAllString(title, "")); stripped != "" {
+		return stripped
+	}
+	return title
+}
@@ -0,0 +1,83 @@
+package util
+
+import "testing"
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "store prefix", input: "[Amazon.ca] Sony WH-1000XM5 $299", want: "Sony WH-1000XM5 $299"},
+		{name: "store prefix and lava hot", input: "[Amazon.ca] Lava Hot! Sony WH-1000XM5 $299", want: "Sony WH-1000XM5 $299"},
+		{name: "hot marker before prefix", input: "HOT!! [Costco] Kirkland Olive Oil", want: "Kirkland Olive Oil"},
+		{name: "stacked prefixes", input: "[Costco] [In-Store] Hot: Dyson V15", want: "Dyson V15"},
+		{name: "excess whitespace", input: "  [Best Buy]   LG   C3  OLED  ", want: "LG C3 OLED"},
+		{name: "hot inside word untouched", input: "Hotel Points Promo", want: "Hotel Points Promo"},
+		{name: "bare hot product untouched", input: "Hot Sauce 2 for $5", want: "Hot Sauce 2 for $5"},
+		{name: "lava hot without punctuation", input: "Lava Hot [Walmart] AirPods", want: "AirPods"},
+		{name: "mid-title brackets kept", input: "Samsung 990 Pro [2TB] $159", want: "Samsung 990 Pro [2TB] $159"},
+		{name: "only prefix keeps raw", input: "[Amazon.ca]", want: "[Amazon.ca]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanTitle(tt.input); got != tt.want {
+				t.Errorf("CleanTitle(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitleCleanerCustomPatterns(t *testing.T) {
+	tc, err := NewTitleCleaner([]string{`(?i)\s*\(expired\)$`})
+	if err != nil {
+		t.Fatalf("NewTitleCleaner() error = %v", err)
+	}
+	if got := tc.Clean("[Amazon.ca] Echo Dot (Expired)"); got != "[Amazon.ca] Echo Dot" {
+		t.Errorf("Clean() = %q, want custom pattern only", got)
+	}
+
+	if _, err := NewTitleCleaner([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestExtractStoreFromTitle(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "[Amazon.ca] Sony WH-1000XM5 $299", want: "Amazon.ca"},
+		{input: "[Best Buy] LG C3 65\" OLED $1499", want: "Best Buy"},
+		{input: "  [ Canada  Computers ] Samsung 990 Pro", want: "Canada Computers"},
+		{input: "[Costco] [In-Store] Dyson V15", want: "Costco"},
+		{input: "Samsung 990 Pro [2TB] $159", want: ""},
+		{input: "No bracket title", want: ""},
+		{input: "[] Empty brackets", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractStoreFromTitle(tt.input); got != tt.want {
+			t.Errorf("ExtractStoreFromTitle(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStripStorePrefix(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "[Amazon.ca] Sony WH-1000XM5 $299", want: "Sony WH-1000XM5 $299"},
+		{input: "[Costco] [In-Store] Dyson V15", want: "[In-Store] Dyson V15"},
+		{input: "No bracket title", want: "No bracket title"},
+		{input: "[Amazon.ca]", want: "[Amazon.ca]"},
+	}
+
+	for _, tt := range tests {
+		if got := StripStorePrefix(tt.input); got != tt.want {
+			t.Errorf("StripStorePrefix(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
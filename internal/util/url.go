@@ -5,9 +5,19 @@ import (
 	"strings"
 )
 
-// NormalizeURL applies RFD-specific normalization (force HTTPS, strip tracking params, etc.)
-// only if the URL's hostname is in the provided allowedDomains list.
-func NormalizeURL(rawURL string, allowedDomains []string) (string, error) {
+// DefaultCanonicalHosts rewrites RFD host aliases to the forum host so the
+// same thread always normalizes to one URL.
+var DefaultCanonicalHosts = map[string]string{
+	"redflagdeals.com":            "forums.redflagdeals.com",
+	"www.redflagdeals.com":        "forums.redflagdeals.com",
+	"www.forums.redflagdeals.com": "forums.redflagdeals.com",
+}
+
+// NormalizeURL applies RFD-specific normalization (force HTTPS, canonical host,
+// strip tracking params, etc.) only if the URL's hostname is in the provided
+// allowedDomains list. canonicalHosts maps a hostname to the host it should be
+// rewritten to; nil uses DefaultCanonicalHosts.
+func NormalizeURL(rawURL string, allowedDomains []string, canonicalHosts map[string]string) (string, error) {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		return rawURL, err
@@ -27,9 +37,12 @@ func NormalizeURL(rawURL string, allowedDomains []string) (string, error) {
 	}
 
 	parsedURL.Scheme = "https"
-	hostOnly := strings.TrimPrefix(parsedURL.Hostname(), "www.")
-	if hostOnly == "redflagdeals.com" {
-		hostOnly = "forums.redflagdeals.com"
+	if canonicalHosts == nil {
+		canonicalHosts = DefaultCanonicalHosts
+	}
+	hostOnly := hostname
+	if canonical, ok := canonicalHosts[hostname]; ok {
+		hostOnly = canonical
 	}
 	// Preserve port if present
 	if port := parsedURL.Port(); port != "" {
@@ -52,3 +65,35 @@ func NormalizeURL(rawURL string, allowedDomains []string) (string, error) {
 	parsedURL.RawQuery = queryParams.Encode()
 	return parsedURL.String(), nil
 }
+
+// dealURLTrackingParams are generic tracking parameters stripped from external
+// deal links. Affiliate parameters (Amazon tag, eBay campid, ...) are left out
+// on purpose so referral cleaning survives normalization.
+var dealURLTrackingParams = []string{
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "utm_id",
+	"fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid", "_ga",
+}
+
+// NormalizeDealURL lowercases the host of an external deal link and strips
+// generic tracking parameters so the same product dedups across posts. The
+// query is only re-encoded when something was removed.
+func NormalizeDealURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return rawURL
+	}
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+
+	queryParams := parsedURL.Query()
+	removed := false
+	for _, param := range dealURLTrackingParams {
+		if queryParams.Has(param) {
+			queryParams.Del(param)
+			removed = true
+		}
+	}
+	if removed {
+		parsedURL.RawQuery = queryParams.Encode()
+	}
+	return parsedURL.String()
+}
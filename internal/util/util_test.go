@@ -94,7 +94,7 @@ func TestCleanReferralLink(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, changed := CleanReferralLink(tt.input, "beauahrens0d-20", bestBuyPrefix)
+			got, changed := CleanReferralLink(tt.input, AffiliateConfig{AmazonTag: "beauahrens0d-20", BestBuyPrefix: bestBuyPrefix})
 			if got != tt.expected {
 				t.Errorf("CleanReferralLink() got = %v, want %v", got, tt.expected)
 			}
@@ -105,6 +105,111 @@ func TestCleanReferralLink(t *testing.T) {
 	}
 }
 
+func TestCleanReferralLinkNoAffiliateDomains(t *testing.T) {
+	affiliates := AffiliateConfig{
+		AmazonTag:          "ours-20",
+		BestBuyPrefix:      "https://bestbuyca.o93x.net/c/5215192/2035226/10221?u=",
+		NoAffiliateDomains: []string{"amazon.ca", "bestbuy.ca"},
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  bool
+	}{
+		{
+			name:     "linksynergy redirect unwrapped without tagging",
+			input:    "https://click.linksynergy.com/deeplink?id=abc&murl=https%3A%2F%2Fwww.amazon.ca%2Fdp%2FB0TEST%3Ftag%3Dposter-20",
+			expected: "https://www.amazon.ca/dp/B0TEST?tag=poster-20",
+			changed:  true,
+		},
+		{
+			name:     "direct link keeps the poster's tag",
+			input:    "https://www.amazon.ca/dp/B0TEST?tag=poster-20",
+			expected: "https://www.amazon.ca/dp/B0TEST?tag=poster-20",
+		},
+		{
+			name:     "other marketplaces are still tagged",
+			input:    "https://www.amazon.com/dp/B0TEST",
+			expected: "https://www.amazon.com/dp/B0TEST?tag=ours-20",
+			changed:  true,
+		},
+		{
+			name:     "Best Buy affiliate redirect unwrapped to the product",
+			input:    "https://bestbuyca.o93x.net/c/123/456/789?u=https://www.bestbuy.ca/product",
+			expected: "https://www.bestbuy.ca/product",
+			changed:  true,
+		},
+		{
+			name:     "direct Best Buy link left alone",
+			input:    "https://www.bestbuy.ca/product",
+			expected: "https://www.bestbuy.ca/product",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := CleanReferralLink(tt.input, affiliates)
+			if got != tt.expected || changed != tt.changed {
+				t.Errorf("CleanReferralLink() = %v, %v, want %v, %v", got, changed, tt.expected, tt.changed)
+			}
+		})
+	}
+}
+
+func TestCleanReferralLinkAmazonMarketplaces(t *testing.T) {
+	affiliates := AffiliateConfig{
+		AmazonTag: "fallback-20",
+		AmazonTags: map[string]string{
+			"amazon.ca":  "canada-20",
+			"amazon.com": "usa-20",
+		},
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "amazon.ca uses Canadian tag",
+			input:    "https://www.amazon.ca/dp/B0TEST",
+			expected: "https://www.amazon.ca/dp/B0TEST?tag=canada-20",
+		},
+		{
+			name:     "amazon.com uses US tag",
+			input:    "https://www.amazon.com/dp/B0TEST?tag=someone-else-20",
+			expected: "https://www.amazon.com/dp/B0TEST?tag=usa-20",
+		},
+		{
+			name:     "unmapped marketplace falls back to default tag",
+			input:    "https://www.amazon.co.uk/dp/B0TEST",
+			expected: "https://www.amazon.co.uk/dp/B0TEST?tag=fallback-20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := CleanReferralLink(tt.input, affiliates)
+			if got != tt.expected || !changed {
+				t.Errorf("CleanReferralLink() = (%v, %v), want (%v, true)", got, changed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCleanReferralLinkAmazonWithoutTag(t *testing.T) {
+	input := "https://www.amazon.ca/dp/B0TEST"
+	if got, changed := CleanReferralLink(input, AffiliateConfig{}); got != input || changed {
+		t.Errorf("CleanReferralLink() = (%v, %v), want (%v, false)", got, changed, input)
+	}
+
+	tagged := "https://www.amazon.ca/dp/B0TEST?psc=1&tag=someoneelse-20"
+	want := "https://www.amazon.ca/dp/B0TEST?psc=1"
+	if got, changed := CleanReferralLink(tagged, AffiliateConfig{}); got != want || !changed {
+		t.Errorf("CleanReferralLink(%s) = (%v, %v), want (%v, true)", tagged, got, changed, want)
+	}
+}
+
 func TestIsHTTPURL(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -199,7 +304,7 @@ func TestNormalizeURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NormalizeURL(tt.input, allowedDomains)
+			got, err := NormalizeURL(tt.input, allowedDomains, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NormalizeURL() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -211,6 +316,62 @@ func TestNormalizeURL(t *testing.T) {
 	}
 }
 
+func TestNormalizeURLCanonicalHosts(t *testing.T) {
+	allowedDomains := []string{"redflagdeals.com", "www.redflagdeals.com", "forums.redflagdeals.com", "m.redflagdeals.com"}
+
+	got, err := NormalizeURL("http://www.redflagdeals.com/deal-1/", allowedDomains, nil)
+	if err != nil || got != "https://forums.redflagdeals.com/deal-1" {
+		t.Errorf("NormalizeURL() with default hosts = %q, %v; want forums host", got, err)
+	}
+
+	custom := map[string]string{"m.redflagdeals.com": "forums.redflagdeals.com"}
+	got, err = NormalizeURL("https://m.redflagdeals.com/deal-2", allowedDomains, custom)
+	if err != nil || got != "https://forums.redflagdeals.com/deal-2" {
+		t.Errorf("NormalizeURL() with custom mapping = %q, %v; want forums host", got, err)
+	}
+	got, err = NormalizeURL("https://www.redflagdeals.com/deal-3", allowedDomains, custom)
+	if err != nil || got != "https://www.redflagdeals.com/deal-3" {
+		t.Errorf("NormalizeURL() with custom mapping = %q, %v; want host left alone when unmapped", got, err)
+	}
+}
+
+func TestNormalizeDealURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "strips tracking and keeps Amazon affiliate tag",
+			input: "https://WWW.Amazon.ca/dp/B000123?tag=mytag-20&utm_source=rfd&fbclid=abc",
+			want:  "https://www.amazon.ca/dp/B000123?tag=mytag-20",
+		},
+		{
+			name:  "keeps eBay affiliate params",
+			input: "https://www.ebay.ca/itm/123?mkcid=1&campid=555&gclid=xyz",
+			want:  "https://www.ebay.ca/itm/123?campid=555&mkcid=1",
+		},
+		{
+			name:  "untouched query keeps its encoding and order",
+			input: "https://bestbuy.7tiv.net/c/1/2/3?u=https%3A%2F%2Fwww.bestbuy.ca%2Fen-ca%2Fproduct%2F1",
+			want:  "https://bestbuy.7tiv.net/c/1/2/3?u=https%3A%2F%2Fwww.bestbuy.ca%2Fen-ca%2Fproduct%2F1",
+		},
+		{
+			name:  "not a URL",
+			input: "not a url",
+			want:  "not a url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeDealURL(tt.input); got != tt.want {
+				t.Errorf("NormalizeDealURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSafeAtoi(t *testing.T) {
 	tests := []struct {
 		name  string
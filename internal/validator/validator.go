@@ -2,20 +2,74 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// DefaultRFDHosts are the hosts the rfd_url rule accepts; subdomains match too.
+var DefaultRFDHosts = []string{"redflagdeals.com"}
+
 // Validator is a wrapper around the validator library.
 type Validator struct {
 	validate *validator.Validate
+
+	mu       sync.RWMutex
+	rfdHosts []string
 }
 
-// New creates a new Validator instance.
+// New creates a new Validator instance with the custom rules registered:
+//
+//   - rfd_url: the URL's host is an RFD host (see SetRFDHosts)
+//   - external_url: the URL's host is not an RFD host
 func New() *Validator {
-	return &Validator{
+	v := &Validator{
 		validate: validator.New(),
+		rfdHosts: DefaultRFDHosts,
+	}
+	// Registration only fails on an empty tag or nil func.
+	_ = v.RegisterValidation("rfd_url", func(fl validator.FieldLevel) bool {
+		return v.isRFDURL(fl.Field().String())
+	})
+	_ = v.RegisterValidation("external_url", func(fl validator.FieldLevel) bool {
+		return !v.isRFDURL(fl.Field().String())
+	})
+	return v
+}
+
+// RegisterValidation adds a custom rule usable as a validate tag.
+func (v *Validator) RegisterValidation(tag string, fn validator.Func) error {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("register validation %q: %w", tag, err)
+	}
+	return nil
+}
+
+// SetRFDHosts replaces the hosts rfd_url accepts, e.g. to add a test server
+// standing in for RFD.
+func (v *Validator) SetRFDHosts(hosts ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rfdHosts = hosts
+}
+
+func (v *Validator) isRFDURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for _, rfdHost := range v.rfdHosts {
+		rfdHost = strings.ToLower(rfdHost)
+		if host == rfdHost || strings.HasSuffix(host, "."+rfdHost) {
+			return true
+		}
 	}
+	return false
 }
 
 // ValidateStruct validates a struct based on its tags.
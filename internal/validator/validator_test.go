@@ -1,9 +1,12 @@
 package validator
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-playground/validator/v10"
+
 	"github.com/pauljones0/rfd-discord-bot/internal/models"
 )
 
@@ -19,7 +22,7 @@ func TestValidator_ValidateStruct(t *testing.T) {
 			name: "Valid Deal",
 			deal: models.DealInfo{
 				Title:              "Test Deal",
-				PostURL:            "https://example.com/deal",
+				PostURL:            "https://forums.redflagdeals.com/deal",
 				PublishedTimestamp: time.Now(),
 				Threads: []models.ThreadContext{
 					{
@@ -34,7 +37,7 @@ func TestValidator_ValidateStruct(t *testing.T) {
 		{
 			name: "Missing Title",
 			deal: models.DealInfo{
-				PostURL:            "https://example.com/deal",
+				PostURL:            "https://forums.redflagdeals.com/deal",
 				PublishedTimestamp: time.Now(),
 			},
 			wantErr: true,
@@ -52,7 +55,7 @@ func TestValidator_ValidateStruct(t *testing.T) {
 			name: "Negative Likes",
 			deal: models.DealInfo{
 				Title:              "Test Deal",
-				PostURL:            "https://example.com/deal",
+				PostURL:            "https://forums.redflagdeals.com/deal",
 				PublishedTimestamp: time.Now(),
 				Threads: []models.ThreadContext{
 					{
@@ -72,3 +75,64 @@ func TestValidator_ValidateStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_CustomURLRules(t *testing.T) {
+	v := New()
+	base := models.DealInfo{Title: "Test Deal", PostURL: "https://forums.redflagdeals.com/deal-1", PublishedTimestamp: time.Now()}
+
+	tests := []struct {
+		name    string
+		mutate  func(*models.DealInfo)
+		wantErr string
+	}{
+		{name: "rfd post and external item", mutate: func(d *models.DealInfo) { d.ActualDealURL = "https://www.amazon.ca/dp/B0TEST" }},
+		{name: "bare rfd host", mutate: func(d *models.DealInfo) { d.PostURL = "https://redflagdeals.com/deal-1" }},
+		{name: "post on another site", mutate: func(d *models.DealInfo) { d.PostURL = "https://example.com/deal" }, wantErr: "rfd_url"},
+		{name: "lookalike host", mutate: func(d *models.DealInfo) { d.PostURL = "https://notredflagdeals.com/deal" }, wantErr: "rfd_url"},
+		{name: "item link back to rfd", mutate: func(d *models.DealInfo) { d.ActualDealURL = "https://forums.redflagdeals.com/deal-1" }, wantErr: "external_url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deal := base
+			tt.mutate(&deal)
+			err := v.ValidateStruct(deal)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateStruct() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateStruct() error = %v, want a %s failure", err, tt.wantErr)
+			}
+		})
+	}
+
+	v.SetRFDHosts("127.0.0.1")
+	deal := base
+	deal.PostURL = "http://127.0.0.1:8080/deal-1"
+	if err := v.ValidateStruct(deal); err != nil {
+		t.Errorf("ValidateStruct() with SetRFDHosts error = %v, want nil", err)
+	}
+}
+
+func TestValidator_RegisterValidation(t *testing.T) {
+	v := New()
+	if err := v.RegisterValidation("no_shouting", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() != strings.ToUpper(fl.Field().String())
+	}); err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+	type post struct {
+		Title string `validate:"no_shouting"`
+	}
+	if err := v.ValidateStruct(post{Title: "Echo Dot"}); err != nil {
+		t.Errorf("ValidateStruct() error = %v, want nil", err)
+	}
+	if err := v.ValidateStruct(post{Title: "ECHO DOT"}); err == nil {
+		t.Error("ValidateStruct() = nil, want no_shouting failure")
+	}
+	if err := v.RegisterValidation("", nil); err == nil {
+		t.Error("RegisterValidation() with empty tag = nil, want error")
+	}
+}